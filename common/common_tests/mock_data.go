@@ -1,11 +1,33 @@
 package common_tests
 
-import "github.com/stretchr/testify/mock"
+import (
+	"errors"
+
+	"github.com/stretchr/testify/mock"
+)
 
 type MockEmail struct {
 	mock.Mock
 }
 
-func (m *MockEmail) SendEmailForPasswordReset(emailId, subject, password string) error {
+func (m *MockEmail) SendEmailForPasswordReset(emailId, subject, resetLink string) error {
+	return nil
+}
+
+func (m *MockEmail) SendEmailForVerification(emailId, subject, verificationLink string) error {
 	return nil
 }
+
+func (m *MockEmail) SendEmailForBoardInvitation(emailId, subject, boardName, invitationLink string) error {
+	return nil
+}
+
+// MockFailingEmail fails to send the password reset email, like an SMTP
+// server that is down.
+type MockFailingEmail struct {
+	MockEmail
+}
+
+func (m *MockFailingEmail) SendEmailForPasswordReset(emailId, subject, resetLink string) error {
+	return errors.New("smtp server is not reachable")
+}
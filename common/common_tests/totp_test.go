@@ -0,0 +1,54 @@
+package common_tests
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// The SHA1 test vectors of RFC 6238, truncated to six digits.
+func TestTOTPCodeAtStep(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		code, err := common.TOTPCodeAtStep(secret, common.TOTPStep(time.Unix(unix, 0)))
+		assert.Nil(t, err)
+		assert.Equal(t, expected, code)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := common.GenerateTOTPSecret()
+	assert.Nil(t, err)
+
+	now := time.Now()
+	code, _ := common.TOTPCodeAtStep(secret, common.TOTPStep(now.Add(-30*time.Second)))
+
+	step, ok := common.ValidateTOTP(secret, code, now)
+	assert.True(t, ok)
+	assert.Equal(t, common.TOTPStep(now)-1, step)
+
+	oldCode, _ := common.TOTPCodeAtStep(secret, common.TOTPStep(now.Add(-5*time.Minute)))
+	_, ok = common.ValidateTOTP(secret, oldCode, now)
+	assert.False(t, ok)
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := common.TOTPURI("Reetro", "test@email.com", "SECRET")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Reetro:test@email.com?"))
+	assert.Contains(t, uri, "secret=SECRET")
+	assert.Contains(t, uri, "issuer=Reetro")
+}
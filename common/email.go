@@ -2,6 +2,7 @@ package common
 
 import (
 	"fmt"
+	"html"
 	"log"
 	"os"
 
@@ -21,12 +22,29 @@ type Email struct {
 }
 
 type EmailInterface interface {
-	SendEmailForPasswordReset(emailId, subject, password string) error
+	SendEmailForPasswordReset(emailId, subject, resetLink string) error
+	SendEmailForVerification(emailId, subject, verificationLink string) error
+	SendEmailForBoardInvitation(emailId, subject, boardName, invitationLink string) error
 }
 
-func (e *Email) SendEmailForPasswordReset(emailId, subject, password string) error {
+func (e *Email) SendEmailForPasswordReset(emailId, subject, resetLink string) error {
+	body := fmt.Sprintf("Use the following link to reset your password, it expires in an hour: <a href=\"%s\">%s</a>", resetLink, resetLink)
+	return e.send(emailId, subject, body)
+}
+
+func (e *Email) SendEmailForVerification(emailId, subject, verificationLink string) error {
+	body := fmt.Sprintf("Please confirm your email address by opening the following link: <a href=\"%s\">%s</a>", verificationLink, verificationLink)
+	return e.send(emailId, subject, body)
+}
+
+func (e *Email) SendEmailForBoardInvitation(emailId, subject, boardName, invitationLink string) error {
+	body := fmt.Sprintf("You have been invited to the retro board %s, use the following link to join it, it expires in a week: <a href=\"%s\">%s</a>", html.EscapeString(boardName), invitationLink, invitationLink)
+	return e.send(emailId, subject, body)
+}
+
+func (e *Email) send(emailId, subject, body string) error {
 	e.Subject = subject
-	e.Body = fmt.Sprintf("Your New Password is: %s", password)
+	e.Body = body
 	e.EmailTo = []string{emailId}
 
 	msg := gomail.NewMessage()
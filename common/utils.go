@@ -1,14 +1,11 @@
 package common
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
-
-	"math/rand"
-)
-
-const (
-	PASSWORD_LENGTH = 6
-	letterBytes     = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 )
 
 func AnyToAnyStructField(from, to any) any {
@@ -17,10 +14,19 @@ func AnyToAnyStructField(from, to any) any {
 	return to
 }
 
-func GenerateRandomPassword() string {
-	b := make([]byte, PASSWORD_LENGTH)
-	for i := range b {
-		b[i] = letterBytes[rand.Intn(len(letterBytes))]
+// GenerateSecureToken returns a url safe random string built from length
+// bytes of crypto/rand, suitable for secrets that are handed to users.
+func GenerateSecureToken(length int) (string, error) {
+	b := make([]byte, length)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
 	}
-	return string(b)
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken is used for secrets we hand out but never need to read back,
+// only the hash is stored so a leaked table can not be replayed.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,27 @@
+package core
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/Aakash-Pandit/reetro-golang/config"
+)
+
+// ClientIP returns the address of the caller. X-Forwarded-For is only
+// trusted when TRUST_PROXY_HEADERS is set, otherwise any client could pick
+// its own address. A forwarded value that is not an IP address is ignored.
+func ClientIP(r *http.Request) string {
+	if config.EnvBool("TRUST_PROXY_HEADERS", false) {
+		forwarded := strings.TrimSpace(strings.Split(r.Header.Get("X-Forwarded-For"), ",")[0])
+		if ip := net.ParseIP(forwarded); ip != nil {
+			return ip.String()
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
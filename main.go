@@ -7,6 +7,7 @@ import (
 
 	"github.com/Aakash-Pandit/reetro-golang/common"
 	"github.com/Aakash-Pandit/reetro-golang/config"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
 	"github.com/Aakash-Pandit/reetro-golang/routes"
 	"github.com/Aakash-Pandit/reetro-golang/server"
 	"github.com/Aakash-Pandit/reetro-golang/storages"
@@ -22,6 +23,11 @@ func init() {
 }
 
 func main() {
+	if _, err := middlewares.SigningKeys(); err != nil {
+		log.Fatal("Unable to load the JWT signing keys:", err)
+		return
+	}
+
 	database, err := storages.DBInit()
 	if err != nil {
 		log.Fatal("Unable to connect DB:", err)
@@ -1,69 +1,175 @@
 package middlewares
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
+const (
+	ACCESS_TOKEN             = "access"
+	REFRESH_TOKEN            = "refresh"
+	EMAIL_VERIFICATION_TOKEN = "email_verification"
+	MFA_PENDING_TOKEN        = "mfa_pending"
+)
+
+var (
+	ACCESS_TOKEN_LIFETIME             = time.Hour
+	REFRESH_TOKEN_LIFETIME            = time.Hour * 24 * 7
+	EMAIL_VERIFICATION_TOKEN_LIFETIME = time.Hour * 48
+	MFA_PENDING_TOKEN_LIFETIME        = time.Minute * 5
+)
+
 type Middleware struct {
-	Store MiddlewareInterface
+	Store       MiddlewareInterface
+	RedisClient storages.RedisStoreInterface
 }
 
-func NewMiddleware(store MiddlewareInterface) *Middleware {
-	return &Middleware{Store: store}
+func NewMiddleware(store MiddlewareInterface, redisClient storages.RedisStoreInterface) *Middleware {
+	return &Middleware{Store: store, RedisClient: redisClient}
 }
 
-func GenerateJSONWebToken(id, email string) (string, error) {
-	secretKey := []byte(os.Getenv("JWT_SECRET_KEY"))
-
-	claims := jwt.MapClaims{
+func newClaims(id, email, tokenType string, lifetime time.Duration) jwt.MapClaims {
+	return jwt.MapClaims{
 		"authorized": true,
 		"id":         id,
 		"email":      email,
-		"exp":        time.Now().Add(time.Hour * 24).Unix(),
+		"jti":        uuid.New().String(),
+		"token_type": tokenType,
+		"exp":        time.Now().Add(lifetime).Unix(),
 	}
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	tokenString, err := token.SignedString(secretKey)
+func generateToken(id, email, tokenType, sessionId string, lifetime time.Duration) (string, error) {
+	keySet, err := SigningKeys()
 	if err != nil {
 		return "", err
 	}
 
-	return tokenString, nil
+	claims := newClaims(id, email, tokenType, lifetime)
+	if sessionId != "" {
+		claims["sid"] = sessionId
+	}
+
+	return keySet.Sign(claims)
+}
+
+// GenerateEmailVerificationToken signs the id and the email together, a
+// link sent before the user changed the address stops working after it.
+func GenerateEmailVerificationToken(id, email string) (string, error) {
+	return generateToken(id, email, EMAIL_VERIFICATION_TOKEN, "", EMAIL_VERIFICATION_TOKEN_LIFETIME)
+}
+
+// GenerateMFAPendingToken is handed out after the password check for users
+// with two-factor authentication, it only works on /login/mfa/.
+func GenerateMFAPendingToken(id, email string) (string, error) {
+	return generateToken(id, email, MFA_PENDING_TOKEN, "", MFA_PENDING_TOKEN_LIFETIME)
+}
+
+// GenerateTokenPair issues the tokens of a login session, both carry the
+// session id in the sid claim so revoking the session revokes them too.
+func GenerateTokenPair(id, email, sessionId string) (*core.TokenResponse, error) {
+	token, err := generateToken(id, email, ACCESS_TOKEN, sessionId, ACCESS_TOKEN_LIFETIME)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateToken(id, email, REFRESH_TOKEN, sessionId, REFRESH_TOKEN_LIFETIME)
+	if err != nil {
+		return nil, err
+	}
+
+	return &core.TokenResponse{Token: token, RefreshToken: refreshToken}, nil
 }
 
 func ValidateJWT(tokenString string) (*jwt.Token, error) {
-	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
+	keySet, err := SigningKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	return jwt.Parse(tokenString, keySet.VerificationKey)
+}
+
+// ValidateTokenOfType validates the signature and expiry of the token and
+// makes sure it was issued for the given purpose, so a refresh token can not
+// be used as an access token and vice versa.
+func ValidateTokenOfType(tokenString, tokenType string) (jwt.MapClaims, error) {
+	token, err := ValidateJWT(tokenString)
+	if err != nil {
+		return nil, err
+	}
 
-		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
-	})
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid token claims")
+	}
+
+	if ClaimString(claims, "token_type") != tokenType {
+		return nil, fmt.Errorf("expected %s token", tokenType)
+	}
+
+	if ClaimString(claims, "jti") == "" || ClaimString(claims, "id") == "" {
+		return nil, errors.New("invalid token claims")
+	}
+
+	return claims, nil
+}
+
+func ClaimString(claims jwt.MapClaims, key string) string {
+	value, _ := claims[key].(string)
+	return value
 }
 
+func BearerToken(r *http.Request) (string, error) {
+	tokenString := r.Header.Get("Authorization")
+
+	if tokenString == "" {
+		return "", errors.New("Empty Token")
+	}
+
+	token_info := strings.Split(tokenString, " ")
+	if len(token_info) != 2 {
+		return "", errors.New("Invalid token")
+	}
+
+	if token_info[0] != "Bearer" {
+		return "", errors.New("Bearer token is required")
+	}
+
+	return token_info[1], nil
+}
+
+// JWTAuthentication also accepts personal access tokens, so every route
+// behind it can be used by scripts without a separate chain.
 func (m *Middleware) JWTAuthentication(next http.HandlerFunc) http.HandlerFunc {
+	personalAccessTokenHandler := m.PersonalAccessTokenAuthentication(next)
+
 	return func(w http.ResponseWriter, r *http.Request) {
-		tokenString := r.Header.Get("Authorization")
+		if IsPersonalAccessTokenRequest(r) {
+			personalAccessTokenHandler(w, r)
+			return
+		}
 
-		if tokenString == "" {
+		tokenString, err := BearerToken(r)
+		if err != nil {
 			core.APIResponse(w, &core.Response{
 				Status: http.StatusUnauthorized,
-				Data:   &core.APIError{Detail: "Empty Token"},
+				Data:   &core.APIError{Detail: err.Error()},
 			})
 			return
 		}
 
-		token_info := strings.Split(tokenString, " ")
-		if len(token_info) != 2 {
+		claims, err := ValidateTokenOfType(tokenString, ACCESS_TOKEN)
+		if err != nil {
 			core.APIResponse(w, &core.Response{
 				Status: http.StatusUnauthorized,
 				Data:   &core.APIError{Detail: "Invalid token"},
@@ -71,26 +177,29 @@ func (m *Middleware) JWTAuthentication(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		if token_info[0] != "Bearer" {
+		revoked, err := IsTokenRevoked(m.RedisClient, ClaimString(claims, "jti"))
+		if err != nil {
+			log.Println("Error while checking the token denylist", err)
+		}
+
+		if err != nil || revoked {
 			core.APIResponse(w, &core.Response{
 				Status: http.StatusUnauthorized,
-				Data:   &core.APIError{Detail: "Bearer token is required"},
+				Data:   &core.APIError{Detail: "Token has been revoked"},
 			})
 			return
 		}
 
-		token, err := ValidateJWT(token_info[1])
-		if err != nil {
+		if err := m.validateSession(claims); err != nil {
 			core.APIResponse(w, &core.Response{
 				Status: http.StatusUnauthorized,
-				Data:   &core.APIError{Detail: "Invalid token"},
+				Data:   &core.APIError{Detail: "Session has been revoked"},
 			})
 			return
 		}
 
-		claims := token.Claims.(jwt.MapClaims)
-		id, _ := uuid.Parse(claims["id"].(string))
-		_, err = m.Store.GetUserById(id)
+		id, _ := uuid.Parse(ClaimString(claims, "id"))
+		user, err := m.Store.GetUserById(id)
 
 		if err != nil {
 			core.APIResponse(w, &core.Response{
@@ -100,6 +209,11 @@ func (m *Middleware) JWTAuthentication(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		next(w, r)
+		ctx := WithTokenClaims(WithRequestUser(r.Context(), user), claims)
+		if boardId, ok := GuestBoardIdFromClaims(claims); ok {
+			ctx = WithGuestBoard(WithTokenScopes(ctx, GuestScopes()), boardId)
+		}
+
+		next(w, r.WithContext(ctx))
 	}
 }
@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/Aakash-Pandit/reetro-golang/models"
 	"github.com/google/uuid"
@@ -9,6 +10,10 @@ import (
 
 type MiddlewareInterface interface {
 	GetUserById(uuid.UUID) (*models.CreateUserResponse, error)
+	GetPersonalAccessTokenByHash(string) (*models.PersonalAccessToken, error)
+	TouchPersonalAccessToken(uuid.UUID, time.Time) error
+	GetUserSession(uuid.UUID) (*models.UserSession, error)
+	TouchUserSession(uuid.UUID, time.Time, time.Time) error
 }
 
 func ChainOfMiddleware(handler http.HandlerFunc, middlewares ...func(http.HandlerFunc) http.HandlerFunc) http.HandlerFunc {
@@ -0,0 +1,62 @@
+package middlewares
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type contextKey string
+
+const (
+	requestUserKey contextKey = "request_user"
+	tokenClaimsKey contextKey = "token_claims"
+	tokenScopesKey contextKey = "token_scopes"
+	guestBoardKey  contextKey = "guest_board"
+)
+
+func WithRequestUser(ctx context.Context, user *models.CreateUserResponse) context.Context {
+	return context.WithValue(ctx, requestUserKey, user)
+}
+
+// RequestUserFromContext returns the user loaded by JWTAuthentication. The
+// second value is false when the route is not behind the middleware.
+func RequestUserFromContext(ctx context.Context) (*models.CreateUserResponse, bool) {
+	user, ok := ctx.Value(requestUserKey).(*models.CreateUserResponse)
+	return user, ok && user != nil
+}
+
+func WithTokenClaims(ctx context.Context, claims jwt.MapClaims) context.Context {
+	return context.WithValue(ctx, tokenClaimsKey, claims)
+}
+
+func TokenClaimsFromContext(ctx context.Context) (jwt.MapClaims, bool) {
+	claims, ok := ctx.Value(tokenClaimsKey).(jwt.MapClaims)
+	return claims, ok && claims != nil
+}
+
+func WithTokenScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, tokenScopesKey, scopes)
+}
+
+// TokenScopesFromContext returns the scopes of the personal access token used
+// for the request. The second value is false for regular logins, which are
+// not narrowed down by scopes.
+func TokenScopesFromContext(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(tokenScopesKey).([]string)
+	return scopes, ok
+}
+
+func WithGuestBoard(ctx context.Context, boardId uuid.UUID) context.Context {
+	return context.WithValue(ctx, guestBoardKey, boardId)
+}
+
+// GuestBoardFromContext returns the board a guest token was issued for. The
+// second value is false for every other kind of authentication.
+func GuestBoardFromContext(ctx context.Context) (uuid.UUID, bool) {
+	boardId, ok := ctx.Value(guestBoardKey).(uuid.UUID)
+	return boardId, ok
+}
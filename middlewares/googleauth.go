@@ -2,29 +2,55 @@ package middlewares
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"net/url"
+	"os"
+	"time"
 )
 
 var GOOGLE_ACCOUNT_DOMAIN = []string{"accounts.google.com", "https://accounts.google.com"}
 
+// GOOGLE_IDENTITY_PROVIDER is the provider Google subjects are linked under.
+const GOOGLE_IDENTITY_PROVIDER = "google"
+
 var GOOGLE_TOKEN_VALIDATION_URL = "https://www.googleapis.com/oauth2/v3/tokeninfo?id_token="
 
 type GoogleAuth struct {
 	Domain             []string
 	TokenValidationURL string
+	ClientId           string
+	Client             *http.Client
+}
+
+type GoogleAuthInterface interface {
+	GoogleTokenValidation(token string) (map[string]interface{}, error)
+}
+
+func NewGoogleAuth() *GoogleAuth {
+	validationURL := os.Getenv("GOOGLE_TOKEN_VALIDATION_URL")
+	if validationURL == "" {
+		validationURL = GOOGLE_TOKEN_VALIDATION_URL
+	}
+
+	return &GoogleAuth{
+		Domain:             GOOGLE_ACCOUNT_DOMAIN,
+		TokenValidationURL: validationURL,
+		ClientId:           os.Getenv("GOOGLE_CLIENT_ID"),
+		Client:             &http.Client{Timeout: 10 * time.Second},
+	}
 }
 
-func ReadJsonData(content []uint8) map[string]interface{} {
+func ReadJsonData(content []uint8) (map[string]interface{}, error) {
 	var payload map[string]interface{}
 	err := json.Unmarshal(content, &payload)
 	if err != nil {
-		log.Fatal("Error during Unmarshal: ", err)
+		return nil, err
 	}
 
-	return payload
+	return payload, nil
 }
 
 func VerifyIss(google_account_domain []string, iss string) bool {
@@ -37,35 +63,76 @@ func VerifyIss(google_account_domain []string, iss string) bool {
 	return false
 }
 
-func GoogleTokenValidation(token string) (bool, map[string]interface{}) {
-	googleAuth := &GoogleAuth{
-		Domain:             GOOGLE_ACCOUNT_DOMAIN,
-		TokenValidationURL: GOOGLE_TOKEN_VALIDATION_URL,
+// VerifyAud makes sure the ID token was issued for our own client, otherwise
+// a token obtained by any other Google application would log the user in.
+func VerifyAud(clientId string, aud interface{}) bool {
+	if clientId == "" {
+		return false
 	}
 
-	url := fmt.Sprint(googleAuth.TokenValidationURL + token)
+	switch value := aud.(type) {
+	case string:
+		return value == clientId
+	case []interface{}:
+		for _, data := range value {
+			if data == clientId {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (g *GoogleAuth) GoogleTokenValidation(token string) (map[string]interface{}, error) {
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
 
-	response, err := http.Get(url)
+	response, err := client.Get(fmt.Sprint(g.TokenValidationURL + url.QueryEscape(token)))
 	if err != nil {
-		log.Fatalln("Error: ", err)
+		return nil, err
 	}
+	defer response.Body.Close()
 
 	data, err := io.ReadAll(response.Body)
 	if err != nil {
-		log.Fatalln("Error: ", err)
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.New("invalid token")
+	}
+
+	payload, err := ReadJsonData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	iss, _ := payload["iss"].(string)
+	if !VerifyIss(g.Domain, iss) {
+		return nil, errors.New("invalid token issuer")
 	}
 
-	payload := ReadJsonData(data)
-	fmt.Println("payload", payload)
-	iss := payload["iss"]
+	if !VerifyAud(g.ClientId, payload["aud"]) {
+		return nil, errors.New("invalid token audience")
+	}
 
-	if iss == nil {
-		err := make(map[string]interface{})
-		err["detail"] = "Invalid Token"
-		return false, err
+	if sub, _ := payload["sub"].(string); sub == "" {
+		return nil, errors.New("token does not contain a subject")
 	}
 
-	valid := VerifyIss(googleAuth.Domain, iss.(string))
+	email, _ := payload["email"].(string)
+	if email == "" {
+		return nil, errors.New("token does not contain an email")
+	}
+
+	// tokeninfo returns the flag as a string, the ID token itself as a bool.
+	verified := fmt.Sprint(payload["email_verified"])
+	if verified != "true" {
+		return nil, errors.New("email is not verified by google")
+	}
 
-	return valid, payload
+	return payload, nil
 }
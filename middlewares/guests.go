@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const GUEST_BOARD_CLAIM = "board"
+
+var GUEST_TOKEN_LIFETIME = time.Hour * 12
+
+// GuestPermissions is everything a guest token allows, on top of the checks
+// the board services do against the board the guest joined.
+var GuestPermissions = []Permission{
+	ViewBoards,
+	ViewFeedbacks,
+	CreateFeedbacks,
+	UpdateFeedbacks,
+}
+
+func GuestScopes() []string {
+	scopes := make([]string, len(GuestPermissions))
+	for i, permission := range GuestPermissions {
+		scopes[i] = string(permission)
+	}
+	return scopes
+}
+
+// GenerateGuestToken issues the access token of a visitor who joined through
+// a board join link. The board claim narrows it down to that board.
+func GenerateGuestToken(id, email, boardId string, lifetime time.Duration) (string, error) {
+	keySet, err := SigningKeys()
+	if err != nil {
+		return "", err
+	}
+
+	claims := newClaims(id, email, ACCESS_TOKEN, lifetime)
+	claims[GUEST_BOARD_CLAIM] = boardId
+
+	return keySet.Sign(claims)
+}
+
+func GuestBoardIdFromClaims(claims jwt.MapClaims) (uuid.UUID, bool) {
+	if _, ok := claims[GUEST_BOARD_CLAIM]; !ok {
+		return uuid.Nil, false
+	}
+
+	// A board claim that does not parse still marks a guest token, it must
+	// not fall back to an unrestricted one.
+	boardId, _ := uuid.Parse(ClaimString(claims, GUEST_BOARD_CLAIM))
+	return boardId, true
+}
@@ -5,24 +5,31 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/Aakash-Pandit/reetro-golang/core"
 	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestGenerateJSONWebToken(t *testing.T) {
-	user := TestMockUserResponse()
-	token, err := middlewares.GenerateJSONWebToken(user.Id.String(), user.Email)
+// sessionTokens issues the tokens of a login for an active session the
+// storage knows about.
+func sessionTokens(mockStorage *MockMiddlewareStorage, user models.CreateUserResponse) *core.TokenResponse {
+	session := models.NewUserSession(user.Id, "test_user_agent", "127.0.0.1", time.Hour)
+	mockStorage.On("GetUserSession", session.Id).Return(session, nil)
 
-	assert.Nil(t, err)
-	assert.NotNil(t, token)
+	tokens, _ := middlewares.GenerateTokenPair(user.Id.String(), user.Email, session.Id.String())
+	return tokens
 }
 
 func TestValidateJWT(t *testing.T) {
 	user := TestMockUserResponse()
-	token, _ := middlewares.GenerateJSONWebToken(user.Id.String(), user.Email)
+	tokens, _ := middlewares.GenerateTokenPair(user.Id.String(), user.Email, uuid.New().String())
 
-	claims, err := middlewares.ValidateJWT(token)
+	claims, err := middlewares.ValidateJWT(tokens.Token)
 
 	assert.Nil(t, err)
 	assert.NotNil(t, claims)
@@ -30,10 +37,10 @@ func TestValidateJWT(t *testing.T) {
 
 func TestJWTAuthentication(t *testing.T) {
 	user := TestMockUserResponse()
-	token, _ := middlewares.GenerateJSONWebToken(user.Id.String(), user.Email)
-
 	mockStorage := new(MockMiddlewareStorage)
-	middleware := middlewares.NewMiddleware(mockStorage)
+	token := sessionTokens(mockStorage, user).Token
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	middleware := middlewares.NewMiddleware(mockStorage, mockRedisClient)
 	handler := middleware.JWTAuthentication(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -51,7 +58,8 @@ func TestJWTAuthentication(t *testing.T) {
 
 func TestJWTAuthenticationWithEmptyToken(t *testing.T) {
 	mockStorage := new(MockMiddlewareStorage)
-	middleware := middlewares.NewMiddleware(mockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	middleware := middlewares.NewMiddleware(mockStorage, mockRedisClient)
 	handler := middleware.JWTAuthentication(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -68,7 +76,8 @@ func TestJWTAuthenticationWithEmptyToken(t *testing.T) {
 func TestJWTAuthenticationWithIncorrectLength(t *testing.T) {
 
 	mockStorage := new(MockMiddlewareStorage)
-	middleware := middlewares.NewMiddleware(mockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	middleware := middlewares.NewMiddleware(mockStorage, mockRedisClient)
 	handler := middleware.JWTAuthentication(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -84,10 +93,10 @@ func TestJWTAuthenticationWithIncorrectLength(t *testing.T) {
 
 func TestJWTAuthenticationWithoutBearer(t *testing.T) {
 	user := TestMockUserResponse()
-	token, _ := middlewares.GenerateJSONWebToken(user.Id.String(), user.Email)
-
 	mockStorage := new(MockMiddlewareStorage)
-	middleware := middlewares.NewMiddleware(mockStorage)
+	token := sessionTokens(mockStorage, user).Token
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	middleware := middlewares.NewMiddleware(mockStorage, mockRedisClient)
 	handler := middleware.JWTAuthentication(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -100,3 +109,119 @@ func TestJWTAuthenticationWithoutBearer(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, rr.Code)
 }
+
+func TestGenerateTokenPair(t *testing.T) {
+	user := TestMockUserResponse()
+	tokens, err := middlewares.GenerateTokenPair(user.Id.String(), user.Email, uuid.New().String())
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, tokens.Token)
+	assert.NotEmpty(t, tokens.RefreshToken)
+
+	_, err = middlewares.ValidateTokenOfType(tokens.RefreshToken, middlewares.ACCESS_TOKEN)
+	assert.NotNil(t, err)
+
+	claims, err := middlewares.ValidateTokenOfType(tokens.RefreshToken, middlewares.REFRESH_TOKEN)
+	assert.Nil(t, err)
+	assert.Equal(t, user.Id.String(), middlewares.ClaimString(claims, "id"))
+}
+
+func TestJWTAuthenticationWithRefreshToken(t *testing.T) {
+	user := TestMockUserResponse()
+	mockStorage := new(MockMiddlewareStorage)
+	token := sessionTokens(mockStorage, user).RefreshToken
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	middleware := middlewares.NewMiddleware(mockStorage, mockRedisClient)
+	handler := middleware.JWTAuthentication(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestJWTAuthenticationWithRevokedToken(t *testing.T) {
+	user := TestMockUserResponse()
+	mockStorage := new(MockMiddlewareStorage)
+	token := sessionTokens(mockStorage, user).Token
+	mockRedisClient := new(MockRevokedRedisClient)
+	middleware := middlewares.NewMiddleware(mockStorage, mockRedisClient)
+	handler := middleware.JWTAuthentication(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestJWTAuthenticationSetsRequestUser(t *testing.T) {
+	user := TestMockUserResponse()
+	mockStorage := new(MockMiddlewareStorage)
+	token := sessionTokens(mockStorage, user).Token
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	middleware := middlewares.NewMiddleware(mockStorage, mockRedisClient)
+	handler := middleware.JWTAuthentication(func(w http.ResponseWriter, r *http.Request) {
+		requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+		assert.True(t, ok)
+		assert.Equal(t, user.Email, requestUser.Email)
+
+		claims, ok := middlewares.TokenClaimsFromContext(r.Context())
+		assert.True(t, ok)
+		assert.Equal(t, user.Id.String(), middlewares.ClaimString(claims, "id"))
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRequestUserFromEmptyContext(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/test", nil)
+
+	user, ok := middlewares.RequestUserFromContext(req.Context())
+
+	assert.False(t, ok)
+	assert.Nil(t, user)
+}
+
+func TestJWTAuthenticationWithGuestToken(t *testing.T) {
+	user := TestMockUserResponse()
+	boardId := uuid.New()
+	token, _ := middlewares.GenerateGuestToken(user.Id.String(), user.Email, boardId.String(), middlewares.GUEST_TOKEN_LIFETIME)
+
+	mockStorage := new(MockMiddlewareStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	middleware := middlewares.NewMiddleware(mockStorage, mockRedisClient)
+	handler := middleware.JWTAuthentication(func(w http.ResponseWriter, r *http.Request) {
+		guestBoardId, ok := middlewares.GuestBoardFromContext(r.Context())
+		assert.True(t, ok)
+		assert.Equal(t, boardId, guestBoardId)
+		assert.True(t, middlewares.ContextHasPermission(r.Context(), middlewares.ViewBoards))
+		assert.False(t, middlewares.ContextHasPermission(r.Context(), middlewares.ViewTeams))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
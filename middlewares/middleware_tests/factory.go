@@ -3,6 +3,8 @@ package middleware_tests
 import (
 	"time"
 
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
 	"github.com/Aakash-Pandit/reetro-golang/models"
 	"github.com/google/uuid"
 )
@@ -19,3 +21,12 @@ func TestMockUserResponse() models.CreateUserResponse {
 		ModifiedAt: time.Now().UTC(),
 	}
 }
+
+const TEST_PERSONAL_ACCESS_TOKEN = "rtp_test_personal_access_token"
+
+func TestMockPersonalAccessToken() models.PersonalAccessToken {
+	return *models.NewPersonalAccessToken(uuid.New(), common.HashToken(TEST_PERSONAL_ACCESS_TOKEN), &models.CreatePersonalAccessTokenRequest{
+		Name:   "test_token",
+		Scopes: []string{string(middlewares.ViewBoards)},
+	})
+}
@@ -1,7 +1,12 @@
 package middleware_tests
 
 import (
+	"database/sql"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
 	"github.com/Aakash-Pandit/reetro-golang/models"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
@@ -14,3 +19,45 @@ func (m *MockMiddlewareStorage) GetUserById(id uuid.UUID) (*models.CreateUserRes
 	user := TestMockUserResponse()
 	return &user, nil
 }
+
+func (m *MockMiddlewareStorage) GetPersonalAccessTokenByHash(tokenHash string) (*models.PersonalAccessToken, error) {
+	if tokenHash != common.HashToken(TEST_PERSONAL_ACCESS_TOKEN) {
+		return nil, sql.ErrNoRows
+	}
+
+	token := TestMockPersonalAccessToken()
+	return &token, nil
+}
+
+func (m *MockMiddlewareStorage) TouchPersonalAccessToken(id uuid.UUID, lastUsedAt time.Time) error {
+	return nil
+}
+
+func (m *MockMiddlewareStorage) GetUserSession(id uuid.UUID) (*models.UserSession, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.UserSession), args.Error(1)
+}
+
+func (m *MockMiddlewareStorage) TouchUserSession(id uuid.UUID, lastSeenAt, expiresAt time.Time) error {
+	return nil
+}
+
+// MockExpiredTokenStorage returns personal access tokens that expired.
+type MockExpiredTokenStorage struct {
+	MockMiddlewareStorage
+}
+
+func (m *MockExpiredTokenStorage) GetPersonalAccessTokenByHash(tokenHash string) (*models.PersonalAccessToken, error) {
+	token := TestMockPersonalAccessToken()
+	expiresAt := time.Now().UTC().Add(-time.Hour)
+	token.ExpiresAt = &expiresAt
+	return &token, nil
+}
+
+type MockRevokedRedisClient struct {
+	storages_tests.MockRedisClient
+}
+
+func (m *MockRevokedRedisClient) Exists(key string) (bool, error) {
+	return true, nil
+}
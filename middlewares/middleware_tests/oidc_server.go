@@ -0,0 +1,211 @@
+package middleware_tests
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	FAKE_OIDC_CLIENT_ID     = "test_oidc_client_id"
+	FAKE_OIDC_CLIENT_SECRET = "test_oidc_client_secret"
+	FAKE_OIDC_REDIRECT_URL  = "http://localhost:3000/login/oidc/callback"
+	FAKE_OIDC_EMAIL         = "oidc_user@email.com"
+)
+
+type fakeAuthorization struct {
+	codeChallenge string
+	nonce         string
+	redirectURL   string
+}
+
+// FakeOIDCServer is a local OpenID Connect provider with discovery, a JWKS
+// and a token endpoint that checks PKCE. Claims are added to, or override,
+// the claims of the ID tokens it issues.
+type FakeOIDCServer struct {
+	Server *httptest.Server
+	KeyId  string
+	Claims jwt.MapClaims
+	// DiscoveryIssuer replaces the issuer named in the discovery document.
+	DiscoveryIssuer string
+
+	key            *rsa.PrivateKey
+	mutex          sync.Mutex
+	authorizations map[string]fakeAuthorization
+}
+
+func NewFakeOIDCServer() *FakeOIDCServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	fake := &FakeOIDCServer{
+		KeyId:          "fake-oidc-key",
+		Claims:         jwt.MapClaims{},
+		key:            key,
+		authorizations: make(map[string]fakeAuthorization),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", fake.discoveryHandler)
+	mux.HandleFunc("/jwks", fake.jwksHandler)
+	mux.HandleFunc("/token", fake.tokenHandler)
+	fake.Server = httptest.NewServer(mux)
+
+	return fake
+}
+
+func (f *FakeOIDCServer) Close() {
+	f.Server.Close()
+}
+
+func (f *FakeOIDCServer) Issuer() string {
+	return f.Server.URL
+}
+
+// Provider is a client of the fake server mapping the groups claim with the
+// mapping given.
+func (f *FakeOIDCServer) Provider(roleMapping map[string]models.Role) *middlewares.OIDCProvider {
+	return middlewares.NewOIDCProvider(middlewares.OIDCProviderConfig{
+		Name:         "fake",
+		IssuerURL:    f.Issuer(),
+		ClientId:     FAKE_OIDC_CLIENT_ID,
+		ClientSecret: FAKE_OIDC_CLIENT_SECRET,
+		RedirectURL:  FAKE_OIDC_REDIRECT_URL,
+		RoleClaim:    "groups",
+		RoleMapping:  roleMapping,
+	})
+}
+
+// Authorize plays the user logging in at the authorization URL and returns
+// the code and state the provider would redirect back with.
+func (f *FakeOIDCServer) Authorize(authorizationURL string) (string, string, error) {
+	parsed, err := url.Parse(authorizationURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	query := parsed.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != FAKE_OIDC_CLIENT_ID {
+		return "", "", errors.New("invalid authorization request")
+	}
+
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		return "", "", errors.New("authorization request without PKCE")
+	}
+
+	code, err := common.GenerateSecureToken(16)
+	if err != nil {
+		return "", "", err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.authorizations[code] = fakeAuthorization{
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		redirectURL:   query.Get("redirect_uri"),
+	}
+
+	return code, query.Get("state"), nil
+}
+
+// IdToken signs an ID token for the fake user with the nonce.
+func (f *FakeOIDCServer) IdToken(nonce string) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            f.Issuer(),
+		"sub":            "fake-subject",
+		"aud":            FAKE_OIDC_CLIENT_ID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          nonce,
+		"email":          FAKE_OIDC_EMAIL,
+		"email_verified": true,
+		"given_name":     "oidc_first_name",
+		"family_name":    "oidc_last_name",
+	}
+
+	for key, value := range f.Claims {
+		claims[key] = value
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = f.KeyId
+	return token.SignedString(f.key)
+}
+
+func (f *FakeOIDCServer) discoveryHandler(w http.ResponseWriter, r *http.Request) {
+	issuer := f.DiscoveryIssuer
+	if issuer == "" {
+		issuer = f.Issuer()
+	}
+
+	json.NewEncoder(w).Encode(middlewares.OIDCDiscoveryDocument{
+		Issuer:                        issuer,
+		AuthorizationEndpoint:         f.Issuer() + "/authorize",
+		TokenEndpoint:                 f.Issuer() + "/token",
+		JWKSURI:                       f.Issuer() + "/jwks",
+		CodeChallengeMethodsSupported: []string{"S256"},
+	})
+}
+
+func (f *FakeOIDCServer) jwksHandler(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(middlewares.JSONWebKeySet{Keys: []middlewares.JSONWebKey{{
+		KeyType:   "RSA",
+		KeyId:     f.KeyId,
+		Use:       "sig",
+		Algorithm: "RS256",
+		N:         base64.RawURLEncoding.EncodeToString(f.key.N.Bytes()),
+		E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(f.key.E)).Bytes()),
+	}}})
+}
+
+func (f *FakeOIDCServer) tokenHandler(w http.ResponseWriter, r *http.Request) {
+	clientId, clientSecret, ok := r.BasicAuth()
+	if r.Method != http.MethodPost || !ok || clientId != FAKE_OIDC_CLIENT_ID || clientSecret != FAKE_OIDC_CLIENT_SECRET {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+
+	r.ParseForm()
+
+	f.mutex.Lock()
+	authorization, found := f.authorizations[r.PostForm.Get("code")]
+	delete(f.authorizations, r.PostForm.Get("code"))
+	f.mutex.Unlock()
+
+	if r.PostForm.Get("grant_type") != "authorization_code" || !found ||
+		r.PostForm.Get("redirect_uri") != authorization.redirectURL ||
+		middlewares.PKCEChallenge(r.PostForm.Get("code_verifier")) != authorization.codeChallenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	idToken, err := f.IdToken(authorization.nonce)
+	if err != nil {
+		http.Error(w, `{"error":"server_error"}`, http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(middlewares.OIDCTokenResponse{
+		AccessToken: "fake-access-token",
+		TokenType:   "Bearer",
+		IdToken:     idToken,
+	})
+}
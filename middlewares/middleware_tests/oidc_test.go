@@ -0,0 +1,195 @@
+package middleware_tests
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// authorizeFake runs the login at the fake provider and returns the code
+// together with the verifier and nonce the flow was started with.
+func authorizeFake(t *testing.T, fake *FakeOIDCServer, provider *middlewares.OIDCProvider) (string, string, string) {
+	codeVerifier, _ := middlewares.NewPKCEVerifier()
+	nonce := "test_nonce"
+
+	authorizationURL, err := provider.AuthorizationURL("test_state", nonce, codeVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, state, err := fake.Authorize(authorizationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "test_state", state)
+
+	return code, codeVerifier, nonce
+}
+
+func TestOIDCAuthorizationURL(t *testing.T) {
+	fake := NewFakeOIDCServer()
+	defer fake.Close()
+
+	authorizationURL, err := fake.Provider(nil).AuthorizationURL("test_state", "test_nonce", "test_verifier")
+	assert.NoError(t, err)
+
+	parsed, _ := url.Parse(authorizationURL)
+	assert.Equal(t, fake.Issuer()+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, FAKE_OIDC_CLIENT_ID, parsed.Query().Get("client_id"))
+	assert.Equal(t, "openid email profile", parsed.Query().Get("scope"))
+	assert.Equal(t, "S256", parsed.Query().Get("code_challenge_method"))
+	assert.Equal(t, middlewares.PKCEChallenge("test_verifier"), parsed.Query().Get("code_challenge"))
+}
+
+func TestOIDCDiscoveryWithOtherIssuer(t *testing.T) {
+	fake := NewFakeOIDCServer()
+	defer fake.Close()
+	fake.DiscoveryIssuer = "https://someone-else.example.com"
+
+	_, err := fake.Provider(nil).AuthorizationURL("test_state", "test_nonce", "test_verifier")
+	assert.Error(t, err)
+}
+
+func TestOIDCExchange(t *testing.T) {
+	fake := NewFakeOIDCServer()
+	defer fake.Close()
+	fake.Claims["groups"] = []string{"engineering", "retro-admins"}
+
+	provider := fake.Provider(map[string]models.Role{
+		"engineering":  models.TeamMember,
+		"retro-admins": models.SuperAdmin,
+	})
+	code, codeVerifier, nonce := authorizeFake(t, fake, provider)
+
+	identity, err := provider.Exchange(code, codeVerifier, nonce)
+	assert.NoError(t, err)
+	assert.Equal(t, FAKE_OIDC_EMAIL, identity.Email)
+	assert.Equal(t, "oidc_first_name", identity.FirstName)
+	assert.Equal(t, models.SuperAdmin, identity.Role)
+}
+
+func TestOIDCExchangeWithoutMappedRole(t *testing.T) {
+	fake := NewFakeOIDCServer()
+	defer fake.Close()
+	fake.Claims["groups"] = "marketing"
+
+	provider := fake.Provider(map[string]models.Role{"engineering": models.TeamMember})
+	code, codeVerifier, nonce := authorizeFake(t, fake, provider)
+
+	identity, err := provider.Exchange(code, codeVerifier, nonce)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Role(""), identity.Role)
+}
+
+func TestOIDCExchangeWithWrongCodeVerifier(t *testing.T) {
+	fake := NewFakeOIDCServer()
+	defer fake.Close()
+
+	provider := fake.Provider(nil)
+	code, _, nonce := authorizeFake(t, fake, provider)
+
+	_, err := provider.Exchange(code, "someone_elses_verifier", nonce)
+	assert.Error(t, err)
+}
+
+func TestOIDCExchangeUsesCodeOnce(t *testing.T) {
+	fake := NewFakeOIDCServer()
+	defer fake.Close()
+
+	provider := fake.Provider(nil)
+	code, codeVerifier, nonce := authorizeFake(t, fake, provider)
+
+	_, err := provider.Exchange(code, codeVerifier, nonce)
+	assert.NoError(t, err)
+
+	_, err = provider.Exchange(code, codeVerifier, nonce)
+	assert.Error(t, err)
+}
+
+func TestOIDCVerifyIdToken(t *testing.T) {
+	fake := NewFakeOIDCServer()
+	defer fake.Close()
+
+	provider := fake.Provider(nil)
+	idToken, _ := fake.IdToken("test_nonce")
+
+	identity, err := provider.VerifyIdToken(idToken, "test_nonce")
+	assert.NoError(t, err)
+	assert.Equal(t, FAKE_OIDC_EMAIL, identity.Email)
+
+	_, err = provider.VerifyIdToken(idToken, "other_nonce")
+	assert.Error(t, err)
+}
+
+func TestOIDCVerifyIdTokenRejectsInvalidClaims(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"other audience":   {"aud": "someone_elses_client_id"},
+		"other issuer":     {"iss": "https://someone-else.example.com"},
+		"expired":          {"exp": 1},
+		"unverified email": {"email_verified": false},
+		"no email":         {"email": ""},
+		"no subject":       {"sub": ""},
+		"other party":      {"aud": []string{FAKE_OIDC_CLIENT_ID, "other"}, "azp": "other"},
+	}
+
+	for name, claims := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := NewFakeOIDCServer()
+			defer fake.Close()
+
+			for key, value := range claims {
+				fake.Claims[key] = value
+			}
+
+			idToken, _ := fake.IdToken("test_nonce")
+			_, err := fake.Provider(nil).VerifyIdToken(idToken, "test_nonce")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestOIDCVerifyIdTokenWithUnknownKey(t *testing.T) {
+	fake := NewFakeOIDCServer()
+	defer fake.Close()
+
+	other := NewFakeOIDCServer()
+	defer other.Close()
+	other.Claims["iss"] = fake.Issuer()
+
+	idToken, _ := other.IdToken("test_nonce")
+	_, err := fake.Provider(nil).VerifyIdToken(idToken, "test_nonce")
+	assert.Error(t, err)
+}
+
+func TestParseOIDCRoleMapping(t *testing.T) {
+	mapping := middlewares.ParseOIDCRoleMapping("retro-admins=super_admin, engineering = team_member,sales=owner,broken")
+
+	assert.Equal(t, map[string]models.Role{
+		"retro-admins": models.SuperAdmin,
+		"engineering":  models.TeamMember,
+	}, mapping)
+}
+
+func TestLoadOIDCProviders(t *testing.T) {
+	t.Setenv("OIDC_PROVIDERS", "okta, keycloak,incomplete")
+	t.Setenv("OIDC_OKTA_ISSUER_URL", "https://okta.example.com/oauth2/default")
+	t.Setenv("OIDC_OKTA_CLIENT_ID", "okta_client_id")
+	t.Setenv("OIDC_OKTA_REDIRECT_URL", "http://localhost:3000/callback")
+	t.Setenv("OIDC_OKTA_ROLE_MAPPING", "engineering=team_member")
+	t.Setenv("OIDC_KEYCLOAK_ISSUER_URL", "https://keycloak.example.com/realms/retro")
+	t.Setenv("OIDC_KEYCLOAK_CLIENT_ID", "keycloak_client_id")
+	t.Setenv("OIDC_KEYCLOAK_REDIRECT_URL", "http://localhost:3000/callback")
+	t.Setenv("OIDC_KEYCLOAK_SCOPES", "openid,email")
+	t.Setenv("OIDC_INCOMPLETE_CLIENT_ID", "incomplete_client_id")
+
+	providers := middlewares.LoadOIDCProviders()
+
+	assert.Len(t, providers, 2)
+	assert.Equal(t, "okta_client_id", providers["okta"].Config.ClientId)
+	assert.Equal(t, models.TeamMember, providers["okta"].Config.RoleMapping["engineering"])
+	assert.Equal(t, []string{"openid", "email"}, providers["keycloak"].Config.Scopes)
+	assert.Equal(t, middlewares.OIDC_DEFAULT_SCOPES, providers["okta"].Config.Scopes)
+}
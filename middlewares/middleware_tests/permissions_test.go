@@ -0,0 +1,91 @@
+package middleware_tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasPermission(t *testing.T) {
+	assert.True(t, middlewares.HasPermission(models.GuestUser, middlewares.ViewBoards))
+	assert.False(t, middlewares.HasPermission(models.GuestUser, middlewares.DeleteBoards))
+	assert.True(t, middlewares.HasPermission(models.GuestUser, middlewares.DeleteFeedbacks))
+	assert.True(t, middlewares.HasPermission(models.TeamMember, middlewares.DeleteFeedbacks))
+	assert.False(t, middlewares.HasPermission(models.TeamMember, middlewares.ListUsers))
+	assert.True(t, middlewares.HasPermission(models.SuperAdmin, middlewares.ClearCache))
+	assert.False(t, middlewares.HasPermission(models.Role("unknown"), middlewares.ViewBoards))
+}
+
+func TestContextHasTeamPermission(t *testing.T) {
+	user := TestMockUserResponse()
+	user.UserType = models.TeamMember
+	ctx := middlewares.WithRequestUser(context.Background(), &user)
+
+	owner := models.NewTeamMembership(uuid.New(), user.Id, models.TeamRoleOwner)
+	admin := models.NewTeamMembership(owner.TeamId, user.Id, models.TeamRoleAdmin)
+	member := models.NewTeamMembership(owner.TeamId, user.Id, models.TeamRoleMember)
+
+	assert.True(t, middlewares.ContextHasTeamPermission(ctx, owner, middlewares.DeleteTeams))
+	assert.False(t, middlewares.ContextHasTeamPermission(ctx, admin, middlewares.DeleteTeams))
+	assert.True(t, middlewares.ContextHasTeamPermission(ctx, admin, middlewares.CreateBoards))
+	assert.False(t, middlewares.ContextHasTeamPermission(ctx, member, middlewares.CreateBoards))
+	assert.False(t, middlewares.ContextHasTeamPermission(ctx, nil, middlewares.CreateBoards))
+	assert.True(t, middlewares.ContextHasTeamPermission(ctx, nil, middlewares.DeleteFeedbacks))
+
+	scoped := middlewares.WithTokenScopes(ctx, []string{string(middlewares.ViewBoards)})
+	assert.False(t, middlewares.ContextHasTeamPermission(scoped, owner, middlewares.CreateBoards))
+}
+
+func TestRequirePermission(t *testing.T) {
+	user := TestMockUserResponse()
+	mockStorage := new(MockMiddlewareStorage)
+	token := sessionTokens(mockStorage, user).Token
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	middleware := middlewares.NewMiddleware(mockStorage, mockRedisClient)
+	handler := middlewares.ChainOfMiddleware(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		},
+		middleware.RequirePermission(middlewares.ViewBoards),
+		middleware.JWTAuthentication,
+	)
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRequirePermissionDenied(t *testing.T) {
+	user := TestMockUserResponse()
+	mockStorage := new(MockMiddlewareStorage)
+	token := sessionTokens(mockStorage, user).Token
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	middleware := middlewares.NewMiddleware(mockStorage, mockRedisClient)
+	handler := middlewares.ChainOfMiddleware(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		},
+		middleware.RequirePermission(middlewares.DeleteBoards),
+		middleware.JWTAuthentication,
+	)
+
+	req, _ := http.NewRequest("DELETE", "/test", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
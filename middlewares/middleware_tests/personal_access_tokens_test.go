@@ -0,0 +1,71 @@
+package middleware_tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func personalAccessTokenHandler(store middlewares.MiddlewareInterface, permission middlewares.Permission) http.HandlerFunc {
+	middleware := middlewares.NewMiddleware(store, new(storages_tests.MockRedisClient))
+	return middlewares.ChainOfMiddleware(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		},
+		middleware.RequirePermission(permission),
+		middleware.JWTAuthentication,
+	)
+}
+
+func TestPersonalAccessTokenAuthentication(t *testing.T) {
+	handler := personalAccessTokenHandler(new(MockMiddlewareStorage), middlewares.ViewBoards)
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Token %s", TEST_PERSONAL_ACCESS_TOKEN))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestPersonalAccessTokenAuthenticationOutsideScope(t *testing.T) {
+	handler := personalAccessTokenHandler(new(MockMiddlewareStorage), middlewares.ViewFeedbacks)
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Token %s", TEST_PERSONAL_ACCESS_TOKEN))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestPersonalAccessTokenAuthenticationWithUnknownToken(t *testing.T) {
+	handler := personalAccessTokenHandler(new(MockMiddlewareStorage), middlewares.ViewBoards)
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Token rtp_unknown")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestPersonalAccessTokenAuthenticationWithExpiredToken(t *testing.T) {
+	handler := personalAccessTokenHandler(new(MockExpiredTokenStorage), middlewares.ViewBoards)
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Token %s", TEST_PERSONAL_ACCESS_TOKEN))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
@@ -0,0 +1,62 @@
+package middleware_tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func sessionRequest(t *testing.T, session *models.UserSession) *httptest.ResponseRecorder {
+	tokens, _ := middlewares.GenerateTokenPair(session.UserId.String(), "test@email.com", session.Id.String())
+
+	mockStorage := new(MockMiddlewareStorage)
+	mockStorage.On("GetUserSession", session.Id).Return(session, nil)
+
+	middleware := middlewares.NewMiddleware(mockStorage, new(storages_tests.MockRedisClient))
+	handler := middleware.JWTAuthentication(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tokens.Token))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	mockStorage.AssertCalled(t, "GetUserSession", session.Id)
+	return rr
+}
+
+func TestJWTAuthenticationWithActiveSession(t *testing.T) {
+	session := models.NewUserSession(uuid.New(), "test_user_agent", "127.0.0.1", time.Hour)
+
+	rr := sessionRequest(t, session)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestJWTAuthenticationWithRevokedSession(t *testing.T) {
+	session := models.NewUserSession(uuid.New(), "test_user_agent", "127.0.0.1", time.Hour)
+	revokedAt := time.Now().UTC()
+	session.RevokedAt = &revokedAt
+
+	rr := sessionRequest(t, session)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestJWTAuthenticationWithExpiredSession(t *testing.T) {
+	session := models.NewUserSession(uuid.New(), "test_user_agent", "127.0.0.1", -time.Minute)
+
+	rr := sessionRequest(t, session)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
@@ -0,0 +1,134 @@
+package middleware_tests
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func useKeySet(t *testing.T, keySet *middlewares.KeySet) {
+	previous, _ := middlewares.SigningKeys()
+	middlewares.UseKeySet(keySet)
+	t.Cleanup(func() { middlewares.UseKeySet(previous) })
+}
+
+// rotationKeys writes an RSA key and an Ed25519 key and returns the directory
+// together with the public part of the RSA key.
+func rotationKeys(t *testing.T) (string, []byte) {
+	dir := t.TempDir()
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	writePEM(t, filepath.Join(dir, "2024-01.pem"), "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	edDER, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	writePEM(t, filepath.Join(dir, "2024-06.pem"), "PRIVATE KEY", edDER)
+
+	rsaPublic, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	return dir, rsaPublic
+}
+
+func TestLoadKeySetFromDir(t *testing.T) {
+	dir, _ := rotationKeys(t)
+
+	keySet, err := middlewares.LoadKeySetFromDir(dir, "2024-01")
+	assert.Nil(t, err)
+	useKeySet(t, keySet)
+
+	user := TestMockUserResponse()
+	tokens, _ := middlewares.GenerateTokenPair(user.Id.String(), user.Email, uuid.New().String())
+
+	parsed, err := middlewares.ValidateJWT(tokens.Token)
+	assert.Nil(t, err)
+	assert.Equal(t, "RS256", parsed.Method.Alg())
+	assert.Equal(t, "2024-01", parsed.Header["kid"])
+
+	jwks := keySet.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "RSA", jwks.Keys[0].KeyType)
+	assert.Equal(t, "OKP", jwks.Keys[1].KeyType)
+}
+
+func TestLoadKeySetFromDirWithoutSigningKeyId(t *testing.T) {
+	dir, _ := rotationKeys(t)
+
+	_, err := middlewares.LoadKeySetFromDir(dir, "")
+	assert.NotNil(t, err)
+}
+
+func TestKeyRotationKeepsOldTokensValid(t *testing.T) {
+	dir, rsaPublic := rotationKeys(t)
+
+	keySet, _ := middlewares.LoadKeySetFromDir(dir, "2024-01")
+	useKeySet(t, keySet)
+
+	user := TestMockUserResponse()
+	oldTokens, _ := middlewares.GenerateTokenPair(user.Id.String(), user.Email, uuid.New().String())
+
+	// The retired key is only kept as a public key after the rotation.
+	writePEM(t, filepath.Join(dir, "2024-01.pem"), "PUBLIC KEY", rsaPublic)
+	rotated, err := middlewares.LoadKeySetFromDir(dir, "2024-06")
+	assert.Nil(t, err)
+	middlewares.UseKeySet(rotated)
+
+	_, err = middlewares.ValidateJWT(oldTokens.Token)
+	assert.Nil(t, err)
+
+	newTokens, _ := middlewares.GenerateTokenPair(user.Id.String(), user.Email, uuid.New().String())
+	parsed, err := middlewares.ValidateJWT(newTokens.Token)
+	assert.Nil(t, err)
+	assert.Equal(t, "EdDSA", parsed.Method.Alg())
+}
+
+func TestValidateJWTRejectsHMAC(t *testing.T) {
+	keySet, _ := middlewares.NewEphemeralKeySet()
+	useKeySet(t, keySet)
+
+	claims := jwt.MapClaims{"id": "test_id", "exp": time.Now().Add(time.Hour).Unix()}
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(""))
+
+	_, err := middlewares.ValidateJWT(token)
+	assert.NotNil(t, err)
+}
+
+func TestLoadKeySetFromEnvWithoutKeysDir(t *testing.T) {
+	t.Setenv("JWT_KEYS_DIR", "")
+	t.Setenv("JWT_EPHEMERAL_KEYS", "")
+	_, err := middlewares.LoadKeySetFromEnv()
+	assert.NotNil(t, err)
+
+	t.Setenv("JWT_EPHEMERAL_KEYS", "true")
+	_, err = middlewares.LoadKeySetFromEnv()
+	assert.Nil(t, err)
+}
+
+func TestValidateJWTRejectsUnknownKeyId(t *testing.T) {
+	keySet, _ := middlewares.NewEphemeralKeySet()
+	useKeySet(t, keySet)
+
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{"id": "test_id", "exp": time.Now().Add(time.Hour).Unix()})
+	token.Header["kid"] = "unknown"
+	tokenString, _ := token.SignedString(otherKey)
+
+	_, err := middlewares.ValidateJWT(tokenString)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,483 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var OIDC_DEFAULT_SCOPES = []string{"openid", "email", "profile"}
+
+// OIDC_JWKS_MIN_REFRESH limits how often the keys of a provider are fetched
+// again when an ID token names a kid we do not know yet.
+const OIDC_JWKS_MIN_REFRESH = time.Minute
+
+// oidcSigningMethods are the ID token algorithms we accept, the same key
+// types we sign our own tokens with.
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "EdDSA"}
+
+// OIDCProviderConfig describes one identity provider. RoleMapping maps values
+// of the RoleClaim, e.g. the groups of the user, to our roles.
+type OIDCProviderConfig struct {
+	Name         string
+	IssuerURL    string
+	ClientId     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	RoleClaim    string
+	RoleMapping  map[string]models.Role
+}
+
+type OIDCDiscoveryDocument struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	JWKSURI                       string   `json:"jwks_uri"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported"`
+}
+
+type OIDCTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IdToken     string `json:"id_token"`
+}
+
+// OIDCIdentity is the user described by a verified ID token. Role is empty
+// when none of the values of the role claim is mapped.
+type OIDCIdentity struct {
+	Subject   string
+	Email     string
+	FirstName string
+	LastName  string
+	Role      models.Role
+}
+
+// OIDCProvider is an OpenID Connect client for the authorization code flow
+// with PKCE. The discovery document and the keys are fetched on first use.
+type OIDCProvider struct {
+	Config OIDCProviderConfig
+	Client *http.Client
+
+	mutex         sync.Mutex
+	discovery     *OIDCDiscoveryDocument
+	keys          map[string]JSONWebKey
+	keysFetchedAt time.Time
+}
+
+func NewOIDCProvider(config OIDCProviderConfig) *OIDCProvider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = OIDC_DEFAULT_SCOPES
+	}
+
+	return &OIDCProvider{
+		Config: config,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// LoadOIDCProviders reads the providers named in OIDC_PROVIDERS, e.g.
+// "okta,keycloak". Each one is configured through OIDC_<NAME>_ISSUER_URL,
+// _CLIENT_ID, _CLIENT_SECRET, _REDIRECT_URL, _SCOPES, _ROLE_CLAIM and
+// _ROLE_MAPPING, the mapping being "group=role" pairs separated by commas.
+func LoadOIDCProviders() map[string]*OIDCProvider {
+	providers := make(map[string]*OIDCProvider)
+
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		config := OIDCProviderConfig{
+			Name:         name,
+			IssuerURL:    os.Getenv(prefix + "ISSUER_URL"),
+			ClientId:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(strings.ReplaceAll(os.Getenv(prefix+"SCOPES"), ",", " ")),
+			RoleClaim:    os.Getenv(prefix + "ROLE_CLAIM"),
+			RoleMapping:  ParseOIDCRoleMapping(os.Getenv(prefix + "ROLE_MAPPING")),
+		}
+
+		if config.IssuerURL == "" || config.ClientId == "" || config.RedirectURL == "" {
+			log.Printf("OIDC provider %s needs %sISSUER_URL, %sCLIENT_ID and %sREDIRECT_URL, skipping it", name, prefix, prefix, prefix)
+			continue
+		}
+
+		providers[name] = NewOIDCProvider(config)
+	}
+
+	return providers
+}
+
+// ParseOIDCRoleMapping reads "group=role" pairs separated by commas, pairs
+// naming an unknown role are ignored.
+func ParseOIDCRoleMapping(value string) map[string]models.Role {
+	mapping := make(map[string]models.Role)
+
+	for _, pair := range strings.Split(value, ",") {
+		claimValue, role, found := strings.Cut(pair, "=")
+		claimValue, role = strings.TrimSpace(claimValue), strings.TrimSpace(role)
+		if !found || claimValue == "" {
+			continue
+		}
+
+		if !isKnownRole(models.Role(role)) {
+			log.Printf("Ignoring the OIDC role mapping of %q to unknown role %q", claimValue, role)
+			continue
+		}
+
+		mapping[claimValue] = models.Role(role)
+	}
+
+	return mapping
+}
+
+func isKnownRole(role models.Role) bool {
+	return roleRank(role) >= 0
+}
+
+// roleRank orders the roles from the least to the most privileged.
+func roleRank(role models.Role) int {
+	for index, known := range models.ValidUserType {
+		if known == role {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// NewPKCEVerifier returns a random code verifier, RFC 7636 asks for 43 to
+// 128 characters of the url safe alphabet.
+func NewPKCEVerifier() (string, error) {
+	return common.GenerateSecureToken(32)
+}
+
+// PKCEChallenge is the S256 code challenge of the verifier.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (p *OIDCProvider) getJSON(endpoint string, target any) error {
+	response, err := p.Client.Get(endpoint)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", endpoint, response.StatusCode)
+	}
+
+	return json.NewDecoder(io.LimitReader(response.Body, 1<<20)).Decode(target)
+}
+
+// Discovery returns the discovery document of the issuer. Its issuer has to
+// be the configured one, otherwise tokens of another tenant would be trusted.
+func (p *OIDCProvider) Discovery() (*OIDCDiscoveryDocument, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	issuer := strings.TrimSuffix(p.Config.IssuerURL, "/")
+
+	var document OIDCDiscoveryDocument
+	if err := p.getJSON(issuer+"/.well-known/openid-configuration", &document); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(document.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("discovery document of %s names issuer %q", issuer, document.Issuer)
+	}
+
+	if document.AuthorizationEndpoint == "" || document.TokenEndpoint == "" || document.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery document of %s is missing endpoints", issuer)
+	}
+
+	if len(document.CodeChallengeMethodsSupported) > 0 && !containsString(document.CodeChallengeMethodsSupported, "S256") {
+		return nil, fmt.Errorf("%s does not support the S256 code challenge method", issuer)
+	}
+
+	p.discovery = &document
+	return p.discovery, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, data := range values {
+		if data == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AuthorizationURL is where the user is sent to log in with the provider.
+func (p *OIDCProvider) AuthorizationURL(state, nonce, codeVerifier string) (string, error) {
+	discovery, err := p.Discovery()
+	if err != nil {
+		return "", err
+	}
+
+	authorizationURL, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+
+	query := authorizationURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.Config.ClientId)
+	query.Set("redirect_uri", p.Config.RedirectURL)
+	query.Set("scope", strings.Join(p.Config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", PKCEChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+	authorizationURL.RawQuery = query.Encode()
+
+	return authorizationURL.String(), nil
+}
+
+// Exchange redeems the authorization code and verifies the ID token that
+// comes back with it.
+func (p *OIDCProvider) Exchange(code, codeVerifier, nonce string) (*OIDCIdentity, error) {
+	discovery, err := p.Discovery()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.Config.RedirectURL},
+		"client_id":     {p.Config.ClientId},
+		"code_verifier": {codeVerifier},
+	}
+
+	request, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if p.Config.ClientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(p.Config.ClientId), url.QueryEscape(p.Config.ClientSecret))
+	}
+
+	response, err := p.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned status %d", response.StatusCode)
+	}
+
+	var tokens OIDCTokenResponse
+	if err := json.NewDecoder(io.LimitReader(response.Body, 1<<20)).Decode(&tokens); err != nil {
+		return nil, err
+	}
+
+	if tokens.IdToken == "" {
+		return nil, errors.New("token response does not contain an id token")
+	}
+
+	return p.VerifyIdToken(tokens.IdToken, nonce)
+}
+
+// VerifyIdToken checks the signature of the ID token against the keys of the
+// provider, its issuer, audience, expiry and nonce, and that the email in it
+// was verified by the provider.
+func (p *OIDCProvider) VerifyIdToken(rawToken, nonce string) (*OIDCIdentity, error) {
+	discovery, err := p.Discovery()
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawToken, claims, p.verificationKey,
+		jwt.WithValidMethods(oidcSigningMethods),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.Config.ClientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// With several audiences the token has to be issued to us, not to one of
+	// the others.
+	if azp := ClaimString(claims, "azp"); azp != "" && azp != p.Config.ClientId {
+		return nil, errors.New("id token was issued to another client")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(ClaimString(claims, "nonce")), []byte(nonce)) != 1 || nonce == "" {
+		return nil, errors.New("invalid id token nonce")
+	}
+
+	// Identities are linked by the subject, a token without one could be
+	// taken for any account that was linked with an empty subject.
+	subject := ClaimString(claims, "sub")
+	if subject == "" {
+		return nil, errors.New("id token does not contain a subject")
+	}
+
+	email := ClaimString(claims, "email")
+	if email == "" {
+		return nil, errors.New("id token does not contain an email")
+	}
+
+	if fmt.Sprint(claims["email_verified"]) != "true" {
+		return nil, errors.New("email is not verified by the provider")
+	}
+
+	return &OIDCIdentity{
+		Subject:   subject,
+		Email:     email,
+		FirstName: ClaimString(claims, "given_name"),
+		LastName:  ClaimString(claims, "family_name"),
+		Role:      p.RoleFromClaims(claims),
+	}, nil
+}
+
+// RoleFromClaims maps the values of the role claim, a string or a list, to
+// the most privileged role they are mapped to.
+func (p *OIDCProvider) RoleFromClaims(claims jwt.MapClaims) models.Role {
+	if p.Config.RoleClaim == "" {
+		return ""
+	}
+
+	var values []string
+	switch value := claims[p.Config.RoleClaim].(type) {
+	case string:
+		values = append(values, value)
+	case []interface{}:
+		for _, data := range value {
+			if text, ok := data.(string); ok {
+				values = append(values, text)
+			}
+		}
+	}
+
+	var role models.Role
+	for _, value := range values {
+		mapped, ok := p.Config.RoleMapping[value]
+		if ok && roleRank(mapped) > roleRank(role) {
+			role = mapped
+		}
+	}
+
+	return role
+}
+
+// verificationKey is the jwt.Keyfunc for ID tokens. The keys are fetched
+// again when the kid is unknown, providers rotate them without notice.
+func (p *OIDCProvider) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, err := p.signingKey(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.Algorithm != "" && key.Algorithm != token.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	publicKey, err := key.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := signingMethodFor(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// signingMethodFor names RS256 for RSA keys, RS384 and RS512 use the
+	// same keys.
+	if _, isRSA := method.(*jwt.SigningMethodRSA); isRSA {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+	} else if method.Alg() != token.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return publicKey, nil
+}
+
+func (p *OIDCProvider) signingKey(kid string) (JSONWebKey, error) {
+	discovery, err := p.Discovery()
+	if err != nil {
+		return JSONWebKey{}, err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	if p.keys != nil && time.Since(p.keysFetchedAt) < OIDC_JWKS_MIN_REFRESH {
+		return JSONWebKey{}, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	var jwks JSONWebKeySet
+	if err := p.getJSON(discovery.JWKSURI, &jwks); err != nil {
+		return JSONWebKey{}, err
+	}
+
+	p.keys = make(map[string]JSONWebKey, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		if key.Use == "" || key.Use == "sig" {
+			p.keys[key.KeyId] = key
+		}
+	}
+	p.keysFetchedAt = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	return JSONWebKey{}, fmt.Errorf("unknown key id %q", kid)
+}
+
+// lookupKey finds the key by kid, tokens without one are accepted when the
+// provider publishes a single key.
+func (p *OIDCProvider) lookupKey(kid string) (JSONWebKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[kid]
+	return key, ok
+}
@@ -0,0 +1,257 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+)
+
+type Permission string
+
+const (
+	ManageOwnAccount Permission = "account:manage"
+
+	ViewUsers       Permission = "users:view"
+	ListUsers       Permission = "users:list"
+	ManageUsers     Permission = "users:manage"
+	ManageUserRoles Permission = "users:manage_roles"
+	DeleteUsers     Permission = "users:delete"
+	UnlockUsers     Permission = "users:unlock"
+	ResetUserMFA    Permission = "users:reset_mfa"
+
+	ViewBoards   Permission = "boards:view"
+	CreateBoards Permission = "boards:create"
+	UpdateBoards Permission = "boards:update"
+	DeleteBoards Permission = "boards:delete"
+
+	ManageBoardMembers Permission = "boards:manage_members"
+
+	ViewFeedbacks   Permission = "feedbacks:view"
+	CreateFeedbacks Permission = "feedbacks:create"
+	UpdateFeedbacks Permission = "feedbacks:update"
+	DeleteFeedbacks Permission = "feedbacks:delete"
+
+	ModerateFeedbacks Permission = "feedbacks:moderate"
+
+	ViewTeams         Permission = "teams:view"
+	ViewAllTeams      Permission = "teams:view_all"
+	CreateTeams       Permission = "teams:create"
+	UpdateTeams       Permission = "teams:update"
+	DeleteTeams       Permission = "teams:delete"
+	ManageTeamMembers Permission = "teams:manage_members"
+
+	ClearCache Permission = "cache:clear"
+
+	ViewAuditEvents Permission = "audit:view"
+)
+
+var guestUserPermissions = []Permission{
+	ManageOwnAccount,
+	ViewUsers,
+	ViewTeams,
+	ViewBoards,
+	ViewFeedbacks,
+	CreateFeedbacks,
+	UpdateFeedbacks,
+	DeleteFeedbacks,
+}
+
+var teamMemberPermissions = append(append([]Permission{}, guestUserPermissions...),
+	CreateTeams,
+)
+
+var superAdminPermissions = append(append([]Permission{}, teamMemberPermissions...),
+	ListUsers,
+	ManageUsers,
+	ManageUserRoles,
+	DeleteUsers,
+	UnlockUsers,
+	ResetUserMFA,
+	CreateBoards,
+	UpdateBoards,
+	DeleteBoards,
+	ManageBoardMembers,
+	ModerateFeedbacks,
+	ViewAllTeams,
+	UpdateTeams,
+	DeleteTeams,
+	ManageTeamMembers,
+	ClearCache,
+	ViewAuditEvents,
+)
+
+// RolePermissions is the single place that decides what each role may do,
+// routes declare the permission they need through RequirePermission.
+var RolePermissions = map[models.Role][]Permission{
+	models.GuestUser:  guestUserPermissions,
+	models.TeamMember: teamMemberPermissions,
+	models.SuperAdmin: superAdminPermissions,
+}
+
+var teamAdminPermissions = []Permission{
+	UpdateTeams,
+	ManageTeamMembers,
+	CreateBoards,
+	UpdateBoards,
+	DeleteBoards,
+	ManageBoardMembers,
+}
+
+var teamOwnerPermissions = append(append([]Permission{}, teamAdminPermissions...),
+	DeleteTeams,
+)
+
+// TeamRolePermissions grants permissions inside a single team on top of the
+// global role, so a team owner can run the boards of their own squad
+// without being a super admin.
+var TeamRolePermissions = map[models.TeamRole][]Permission{
+	models.TeamRoleMember: {},
+	models.TeamRoleAdmin:  teamAdminPermissions,
+	models.TeamRoleOwner:  teamOwnerPermissions,
+}
+
+// BoardRolePermissions is what membership of a single board allows, posting
+// feedback always needs it whatever the global role is. Facilitators can
+// also edit and remove the feedback of others on their board.
+var BoardRolePermissions = map[models.BoardRole][]Permission{
+	models.BoardViewer:      {},
+	models.BoardParticipant: {CreateFeedbacks},
+	models.BoardFacilitator: {CreateFeedbacks, ManageBoardMembers, ModerateFeedbacks},
+}
+
+func HasPermission(role models.Role, permission Permission) bool {
+	for _, allowed := range RolePermissions[role] {
+		if allowed == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+func HasTeamPermission(role models.TeamRole, permission Permission) bool {
+	for _, allowed := range TeamRolePermissions[role] {
+		if allowed == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+func HasBoardPermission(role models.BoardRole, permission Permission) bool {
+	for _, allowed := range BoardRolePermissions[role] {
+		if allowed == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsKnownPermission reports whether the value names one of the permissions
+// above, it is used to validate personal access token scopes.
+func IsKnownPermission(value string) bool {
+	for _, permission := range superAdminPermissions {
+		if string(permission) == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ContextHasPermission is the check RequirePermission does, for services that
+// decide on a permission inside the handler.
+func ContextHasPermission(ctx context.Context, permission Permission) bool {
+	user, ok := RequestUserFromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	return userHasPermission(ctx, user, permission)
+}
+
+// ContextHasTeamPermission reports whether the request user may act with the
+// permission inside the team, either through their global role or through
+// their role in the team. membership is nil when they are not a member.
+func ContextHasTeamPermission(ctx context.Context, membership *models.TeamMembership, permission Permission) bool {
+	if ContextHasPermission(ctx, permission) {
+		return true
+	}
+
+	if membership == nil || !HasTeamPermission(membership.Role, permission) {
+		return false
+	}
+
+	return scopesAllow(ctx, permission)
+}
+
+// ContextHasBoardPermission reports whether the role of the request user on
+// the board allows the permission. member is nil when they are not on it.
+func ContextHasBoardPermission(ctx context.Context, member *models.BoardMember, permission Permission) bool {
+	if member == nil || !HasBoardPermission(member.Role, permission) {
+		return false
+	}
+
+	return scopesAllow(ctx, permission)
+}
+
+func userHasPermission(ctx context.Context, user *models.CreateUserResponse, permission Permission) bool {
+	if !HasPermission(user.UserType, permission) {
+		return false
+	}
+
+	return scopesAllow(ctx, permission)
+}
+
+// scopesAllow reports whether a personal access token used for the request
+// was scoped to the permission, requests authenticated otherwise always are.
+func scopesAllow(ctx context.Context, permission Permission) bool {
+	scopes, scoped := TokenScopesFromContext(ctx)
+	if !scoped {
+		return true
+	}
+
+	for _, scope := range scopes {
+		if scope == string(permission) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RequirePermission rejects the request unless the role of the authenticated
+// user has every one of the given permissions, and for personal access tokens
+// unless the token was scoped to them as well. It reads the user put in the
+// context by JWTAuthentication, so it has to be listed before it in
+// ChainOfMiddleware.
+func (m *Middleware) RequirePermission(permissions ...Permission) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			user, ok := RequestUserFromContext(r.Context())
+			if !ok {
+				core.APIResponse(w, &core.Response{
+					Status: http.StatusUnauthorized,
+					Data:   &core.APIError{Detail: "Unauthorized"},
+				})
+				return
+			}
+
+			for _, permission := range permissions {
+				if !userHasPermission(r.Context(), user, permission) {
+					core.APIResponse(w, &core.Response{
+						Status: http.StatusForbidden,
+						Data:   &core.APIError{Detail: "Permission denied"},
+					})
+					return
+				}
+			}
+
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+)
+
+const PERSONAL_ACCESS_TOKEN_SCHEME = "Token"
+
+// Last used timestamps are only written once per interval so busy CI jobs do
+// not turn every request into a database write.
+var PERSONAL_ACCESS_TOKEN_TOUCH_INTERVAL = time.Minute
+
+func IsPersonalAccessTokenRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Authorization"), PERSONAL_ACCESS_TOKEN_SCHEME+" ")
+}
+
+func PersonalAccessToken(r *http.Request) (string, error) {
+	token_info := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(token_info) != 2 || token_info[0] != PERSONAL_ACCESS_TOKEN_SCHEME {
+		return "", errors.New("Invalid token")
+	}
+
+	return token_info[1], nil
+}
+
+// PersonalAccessTokenAuthentication authenticates `Authorization: Token
+// <value>` requests. The token scopes are put in the context so
+// RequirePermission can narrow the permissions of the user down to them.
+func (m *Middleware) PersonalAccessTokenAuthentication(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tokenString, err := PersonalAccessToken(r)
+		if err != nil {
+			core.APIResponse(w, &core.Response{
+				Status: http.StatusUnauthorized,
+				Data:   &core.APIError{Detail: err.Error()},
+			})
+			return
+		}
+
+		token, err := m.Store.GetPersonalAccessTokenByHash(common.HashToken(tokenString))
+		if err != nil || !token.IsValid() {
+			core.APIResponse(w, &core.Response{
+				Status: http.StatusUnauthorized,
+				Data:   &core.APIError{Detail: "Invalid token"},
+			})
+			return
+		}
+
+		user, err := m.Store.GetUserById(token.UserId)
+		if err != nil {
+			core.APIResponse(w, &core.Response{
+				Status: http.StatusUnauthorized,
+				Data:   &core.APIError{Detail: "Unauthorized"},
+			})
+			return
+		}
+
+		now := time.Now().UTC()
+		if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= PERSONAL_ACCESS_TOKEN_TOUCH_INTERVAL {
+			if err := m.Store.TouchPersonalAccessToken(token.Id, now); err != nil {
+				log.Println("Error while updating the personal access token last used time", err)
+			}
+		}
+
+		ctx := WithTokenScopes(WithRequestUser(r.Context(), user), token.Scopes)
+		next(w, r.WithContext(ctx))
+	}
+}
@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const REVOKED_TOKEN_PREFIX = "revoked_token:"
+
+// RevokeToken puts the jti of the token into the Redis denylist. The entry
+// only lives as long as the token itself, after that the expiry check in
+// ValidateJWT rejects it anyway.
+func RevokeToken(client storages.RedisStoreInterface, claims jwt.MapClaims) error {
+	expiry, err := claims.GetExpirationTime()
+	if err != nil || expiry == nil {
+		return err
+	}
+
+	ttl := time.Until(expiry.Time)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return client.SetWithExpiry(REVOKED_TOKEN_PREFIX+ClaimString(claims, "jti"), true, ttl)
+}
+
+// ClaimToken revokes the token and reports whether this call did it, so of
+// several requests presenting the same single use token only one wins.
+func ClaimToken(client storages.RedisStoreInterface, claims jwt.MapClaims) (bool, error) {
+	expiry, err := claims.GetExpirationTime()
+	if err != nil || expiry == nil {
+		return false, err
+	}
+
+	ttl := time.Until(expiry.Time)
+	if ttl <= 0 {
+		return false, nil
+	}
+
+	return client.SetNX(REVOKED_TOKEN_PREFIX+ClaimString(claims, "jti"), true, ttl)
+}
+
+func IsTokenRevoked(client storages.RedisStoreInterface, jti string) (bool, error) {
+	return client.Exists(REVOKED_TOKEN_PREFIX + jti)
+}
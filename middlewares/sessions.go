@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Last seen timestamps are written at most once per interval for the same
+// reason as the personal access token ones.
+var SESSION_TOUCH_INTERVAL = time.Minute
+
+// SessionIdFromClaims returns the session the token was issued for. Tokens
+// issued before sessions were tracked have none.
+func SessionIdFromClaims(claims jwt.MapClaims) (uuid.UUID, bool) {
+	sessionId, err := uuid.Parse(ClaimString(claims, "sid"))
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return sessionId, true
+}
+
+// validateSession rejects tokens whose session was revoked or expired and
+// records the activity on the session. Access tokens without a session are
+// short lived and cannot be refreshed, they are let through until they
+// expire.
+func (m *Middleware) validateSession(claims jwt.MapClaims) error {
+	sessionId, ok := SessionIdFromClaims(claims)
+	if !ok {
+		return nil
+	}
+
+	session, err := m.Store.GetUserSession(sessionId)
+	if err != nil {
+		return err
+	}
+
+	if !session.IsActive() || session.UserId.String() != ClaimString(claims, "id") {
+		return errors.New("Session has been revoked")
+	}
+
+	now := time.Now().UTC()
+	if now.Sub(session.LastSeenAt) >= SESSION_TOUCH_INTERVAL {
+		if err := m.Store.TouchUserSession(session.Id, now, time.Time{}); err != nil {
+			log.Println("Error while updating the session last seen time", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,326 @@
+package middlewares
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is one entry of the key set. Keys without a private key are
+// retired ones that are only kept to verify tokens issued before a rotation.
+type SigningKey struct {
+	Id         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+}
+
+type KeySet struct {
+	signingKey *SigningKey
+	keys       map[string]*SigningKey
+}
+
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyId     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// PublicKey reads the RSA or Ed25519 public key of the JSON web key, used to
+// verify tokens signed by other issuers.
+func (k JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", k.KeyId, err)
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", k.KeyId, err)
+		}
+
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("key %s is not a valid RSA key", k.KeyId)
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", k.KeyId, err)
+		}
+
+		if k.Curve != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("key %s is not a valid Ed25519 key", k.KeyId)
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("key %s has unsupported key type %q", k.KeyId, k.KeyType)
+	}
+}
+
+var (
+	keySetMutex  sync.Mutex
+	currentKeys  *KeySet
+	keySetLoaded bool
+)
+
+func signingMethodFor(publicKey crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", publicKey)
+	}
+}
+
+// ParseSigningKeyPEM reads a PKCS8 or PKCS1 private key, or a PKIX or PKCS1
+// public key for retired keys.
+func ParseSigningKeyPEM(id string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s is not PEM encoded", id)
+	}
+
+	key := &SigningKey{Id: id}
+
+	switch block.Type {
+	case "PRIVATE KEY", "RSA PRIVATE KEY":
+		var parsed any
+		var err error
+		if block.Type == "RSA PRIVATE KEY" {
+			parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		} else {
+			parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("key %s can not sign", id)
+		}
+		key.PrivateKey = signer
+		key.PublicKey = signer.Public()
+	case "PUBLIC KEY", "RSA PUBLIC KEY":
+		var parsed any
+		var err error
+		if block.Type == "RSA PUBLIC KEY" {
+			parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		} else {
+			parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		key.PublicKey = parsed
+	default:
+		return nil, fmt.Errorf("key %s has unsupported PEM type %q", id, block.Type)
+	}
+
+	method, err := signingMethodFor(key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", id, err)
+	}
+	key.Method = method
+
+	return key, nil
+}
+
+// NewKeySet builds a key set signing with signingKeyId. It can be left empty
+// when exactly one of the keys has a private key.
+func NewKeySet(keys []*SigningKey, signingKeyId string) (*KeySet, error) {
+	keySet := &KeySet{keys: make(map[string]*SigningKey)}
+
+	var privateKeys []*SigningKey
+	for _, key := range keys {
+		if _, exists := keySet.keys[key.Id]; exists {
+			return nil, fmt.Errorf("duplicate key id %s", key.Id)
+		}
+		keySet.keys[key.Id] = key
+
+		if key.PrivateKey != nil {
+			privateKeys = append(privateKeys, key)
+		}
+	}
+
+	if signingKeyId == "" && len(privateKeys) == 1 {
+		signingKeyId = privateKeys[0].Id
+	}
+
+	signingKey, ok := keySet.keys[signingKeyId]
+	if !ok || signingKey.PrivateKey == nil {
+		return nil, errors.New("JWT_SIGNING_KEY_ID must name a private key when several are configured")
+	}
+	keySet.signingKey = signingKey
+
+	return keySet, nil
+}
+
+// LoadKeySetFromDir loads every .pem file of the directory, the file name
+// without the extension is used as the kid.
+func LoadKeySetFromDir(dir, signingKeyId string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no .pem files found in %s", dir)
+	}
+
+	sort.Strings(paths)
+
+	keys := make([]*SigningKey, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := ParseSigningKeyPEM(strings.TrimSuffix(filepath.Base(path), ".pem"), data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(keys, signingKeyId)
+}
+
+// NewEphemeralKeySet generates an Ed25519 key that only lives as long as the
+// process, tokens stop working on restart and can not be shared between
+// replicas. It is meant for development and tests.
+func NewEphemeralKeySet() (*KeySet, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &SigningKey{Id: "ephemeral", Method: jwt.SigningMethodEdDSA, PrivateKey: privateKey, PublicKey: publicKey}
+	return NewKeySet([]*SigningKey{key}, key.Id)
+}
+
+// LoadKeySetFromEnv loads the keys of JWT_KEYS_DIR. Without it the ephemeral
+// key is only used when JWT_EPHEMERAL_KEYS is true, which is meant for
+// development, so a missing directory does not go unnoticed in production.
+func LoadKeySetFromEnv() (*KeySet, error) {
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		if os.Getenv("JWT_EPHEMERAL_KEYS") != "true" {
+			return nil, errors.New("JWT_KEYS_DIR is not set, set JWT_EPHEMERAL_KEYS=true to sign with an ephemeral key in development")
+		}
+
+		log.Println("JWT_EPHEMERAL_KEYS is set, signing tokens with an ephemeral key")
+		return NewEphemeralKeySet()
+	}
+
+	return LoadKeySetFromDir(dir, os.Getenv("JWT_SIGNING_KEY_ID"))
+}
+
+// SigningKeys returns the key set, loading it from the environment the first
+// time it is needed.
+func SigningKeys() (*KeySet, error) {
+	keySetMutex.Lock()
+	defer keySetMutex.Unlock()
+
+	if !keySetLoaded {
+		keySet, err := LoadKeySetFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		currentKeys, keySetLoaded = keySet, true
+	}
+
+	return currentKeys, nil
+}
+
+func UseKeySet(keySet *KeySet) {
+	keySetMutex.Lock()
+	defer keySetMutex.Unlock()
+
+	currentKeys, keySetLoaded = keySet, true
+}
+
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signingKey.Method, claims)
+	token.Header["kid"] = k.signingKey.Id
+	return token.SignedString(k.signingKey.PrivateKey)
+}
+
+// VerificationKey is the jwt.Keyfunc of the key set. The algorithm of the
+// token has to match the key named by its kid, so a public key can never be
+// used as an HMAC secret.
+func (k *KeySet) VerificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.PublicKey, nil
+}
+
+func (k *KeySet) JWKS() JSONWebKeySet {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	jwks := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(ids))}
+	for _, id := range ids {
+		key := k.keys[id]
+		jwk := JSONWebKey{KeyId: key.Id, Use: "sig", Algorithm: key.Method.Alg()}
+
+		switch publicKey := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditCacheCleared AuditAction = "cache.cleared"
+
+	AuditUserUpdated     AuditAction = "user.updated"
+	AuditUserRoleChanged AuditAction = "user.role_changed"
+	AuditUserDeleted     AuditAction = "user.deleted"
+	AuditUserUnlocked    AuditAction = "user.unlocked"
+	AuditUserMFAReset    AuditAction = "user.mfa_reset"
+
+	AuditPersonalAccessTokenCreated AuditAction = "personal_access_token.created"
+	AuditPersonalAccessTokenRevoked AuditAction = "personal_access_token.revoked"
+	AuditSessionRevoked             AuditAction = "session.revoked"
+	AuditAllSessionsRevoked         AuditAction = "session.revoked_all"
+
+	AuditTeamDeleted           AuditAction = "team.deleted"
+	AuditTeamMemberAdded       AuditAction = "team.member_added"
+	AuditTeamMemberRoleChanged AuditAction = "team.member_role_changed"
+	AuditTeamMemberRemoved     AuditAction = "team.member_removed"
+
+	AuditBoardDeleted           AuditAction = "board.deleted"
+	AuditBoardClosed            AuditAction = "board.closed"
+	AuditBoardMemberRemoved     AuditAction = "board.member_removed"
+	AuditBoardInvitationCreated AuditAction = "board.invitation_created"
+	AuditBoardJoinLinkCreated   AuditAction = "board.join_link_created"
+	AuditBoardJoinLinkRevoked   AuditAction = "board.join_link_revoked"
+
+	AuditFeedbackRemoved AuditAction = "feedback.removed"
+)
+
+type AuditTargetType string
+
+const (
+	AuditTargetCache               AuditTargetType = "cache"
+	AuditTargetUser                AuditTargetType = "user"
+	AuditTargetPersonalAccessToken AuditTargetType = "personal_access_token"
+	AuditTargetSession             AuditTargetType = "session"
+	AuditTargetTeam                AuditTargetType = "team"
+	AuditTargetBoard               AuditTargetType = "board"
+	AuditTargetFeedback            AuditTargetType = "feedback"
+)
+
+// AuditEvent is an append only record of a security relevant action. Before
+// and After hold the target as JSON around the change, either is null when
+// the target did not exist on that side of it.
+type AuditEvent struct {
+	Id         uuid.UUID       `json:"id"`
+	ActorId    *uuid.UUID      `json:"actor_id"`
+	Action     AuditAction     `json:"action"`
+	TargetType AuditTargetType `json:"target_type"`
+	TargetId   string          `json:"target_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	IPAddress  string          `json:"ip_address"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditEventFilter narrows down the audit events, zero values match all.
+type AuditEventFilter struct {
+	ActorId    *uuid.UUID
+	Action     AuditAction
+	TargetType AuditTargetType
+	TargetId   string
+	From       *time.Time
+	To         *time.Time
+}
+
+func NewAuditEvent(actorId *uuid.UUID, action AuditAction, targetType AuditTargetType, targetId string, before, after any, ipAddress string) (*AuditEvent, error) {
+	beforeJSON, err := auditSnapshot(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterJSON, err := auditSnapshot(after)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditEvent{
+		Id:         uuid.New(),
+		ActorId:    actorId,
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		IPAddress:  ipAddress,
+		CreatedAt:  time.Now().UTC(),
+	}, nil
+}
+
+func auditSnapshot(value any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(value)
+}
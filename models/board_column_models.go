@@ -0,0 +1,128 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+const BOARD_COLUMNS_MAX = 20
+
+// BoardColumn is a lane of a board. The key is what feedback is filed under
+// and never changes, the title, description, color and position are only
+// how the lane is shown.
+type BoardColumn struct {
+	Id          uuid.UUID  `json:"id"`
+	BoardId     uuid.UUID  `json:"board_id"`
+	Key         ColumnType `json:"key"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Color       string     `json:"color"`
+	Position    int        `json:"position"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ModifiedAt  time.Time  `json:"modified_at"`
+}
+
+// CreateBoardColumnRequest derives the key from the title when it is left
+// out, "Puzzles and risks" becomes puzzles_and_risks.
+type CreateBoardColumnRequest struct {
+	Key         ColumnType `json:"key" validate:"required,column_key"`
+	Title       string     `json:"title" validate:"required,max=100"`
+	Description string     `json:"description" validate:"max=500"`
+	Color       string     `json:"color" validate:"omitempty,hexcolor"`
+}
+
+// UpdateBoardColumnRequest only changes the fields that are sent, an empty
+// description or color clears it.
+type UpdateBoardColumnRequest struct {
+	Title       string  `json:"title" validate:"max=100"`
+	Description *string `json:"description" validate:"omitempty,max=500"`
+	Color       *string `json:"color" validate:"omitempty,hexcolor|len=0"`
+}
+
+type ReorderBoardColumnsRequest struct {
+	ColumnIds []string `json:"column_ids" validate:"required,min=1,unique,dive,uuid"`
+}
+
+// ColumnKeyFromTitle turns a title into a column key, an empty key means the
+// title has no letters or digits to build one from.
+func ColumnKeyFromTitle(title string) ColumnType {
+	var builder strings.Builder
+	separate := false
+	for _, char := range strings.ToLower(title) {
+		if char < unicode.MaxASCII && (unicode.IsLetter(char) || unicode.IsDigit(char)) {
+			if separate && builder.Len() > 0 {
+				builder.WriteRune('_')
+			}
+			builder.WriteRune(char)
+			separate = false
+			continue
+		}
+		separate = true
+	}
+
+	key := builder.String()
+	if len(key) > KEY_MAX_LEN {
+		key = strings.TrimRight(key[:KEY_MAX_LEN], "_")
+	}
+
+	return ColumnType(key)
+}
+
+func NewBoardColumn(boardId uuid.UUID, position int, request *CreateBoardColumnRequest) *BoardColumn {
+	return &BoardColumn{
+		Id:          uuid.New(),
+		BoardId:     boardId,
+		Key:         request.Key,
+		Title:       strings.TrimSpace(request.Title),
+		Description: request.Description,
+		Color:       strings.ToLower(request.Color),
+		Position:    position,
+		CreatedAt:   time.Now().UTC(),
+		ModifiedAt:  time.Now().UTC(),
+	}
+}
+
+func UpdateBoardColumn(column *BoardColumn, request *UpdateBoardColumnRequest) *BoardColumn {
+	if title := strings.TrimSpace(request.Title); title != "" {
+		column.Title = title
+	}
+
+	if request.Description != nil {
+		column.Description = *request.Description
+	}
+
+	if request.Color != nil {
+		column.Color = strings.ToLower(*request.Color)
+	}
+
+	column.ModifiedAt = time.Now().UTC()
+
+	return column
+}
+
+// Column returns the column of the board with the id, nil when the board has
+// none.
+func (b *Board) Column(id uuid.UUID) *BoardColumn {
+	for _, column := range b.Columns {
+		if column.Id == id {
+			return column
+		}
+	}
+
+	return nil
+}
+
+// HasColumn reports whether the column is one of the columns of the board,
+// feedback can only be added to those.
+func (b *Board) HasColumn(key ColumnType) bool {
+	for _, column := range b.Columns {
+		if column.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BoardRole string
+
+const (
+	BoardFacilitator BoardRole = "facilitator"
+	BoardParticipant BoardRole = "participant"
+	BoardViewer      BoardRole = "viewer"
+)
+
+var ValidBoardRole = []BoardRole{BoardFacilitator, BoardParticipant, BoardViewer}
+
+const BOARD_INVITATION_LIFETIME = time.Hour * 24 * 7
+
+type BoardMember struct {
+	BoardId   uuid.UUID           `json:"board_id"`
+	UserId    uuid.UUID           `json:"user_id"`
+	User      *CreateUserResponse `json:"user"`
+	Role      BoardRole           `json:"role"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+type BoardInvitation struct {
+	Id          uuid.UUID  `json:"id"`
+	BoardId     uuid.UUID  `json:"board_id"`
+	Email       string     `json:"email"`
+	Role        BoardRole  `json:"role"`
+	TokenHash   string     `json:"-"`
+	InvitedById uuid.UUID  `json:"invited_by_id"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	AcceptedAt  *time.Time `json:"accepted_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type CreateBoardInvitationRequest struct {
+	Email string    `json:"email" validate:"required,email"`
+	Role  BoardRole `json:"role" validate:"omitempty,oneof=facilitator participant viewer"`
+}
+
+type AcceptBoardInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func NewBoardMember(boardId, userId uuid.UUID, role BoardRole) *BoardMember {
+	if role == "" {
+		role = BoardParticipant
+	}
+
+	return &BoardMember{
+		BoardId:   boardId,
+		UserId:    userId,
+		Role:      role,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func NewBoardInvitation(boardId, invitedById uuid.UUID, tokenHash string, request *CreateBoardInvitationRequest) *BoardInvitation {
+	role := request.Role
+	if role == "" {
+		role = BoardParticipant
+	}
+
+	return &BoardInvitation{
+		Id:          uuid.New(),
+		BoardId:     boardId,
+		Email:       strings.ToLower(request.Email),
+		Role:        role,
+		TokenHash:   tokenHash,
+		InvitedById: invitedById,
+		ExpiresAt:   time.Now().UTC().Add(BOARD_INVITATION_LIFETIME),
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+func (i *BoardInvitation) IsValid() bool {
+	return i.AcceptedAt == nil && time.Now().UTC().Before(i.ExpiresAt)
+}
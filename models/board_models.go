@@ -9,6 +9,8 @@ import (
 type ColumnType string
 type TemplateType string
 
+// The keys of the columns of the built-in templates, boards can have columns
+// with any other key as well.
 const (
 	GoodThing ColumnType = "good_thing"
 	Learned   ColumnType = "learned"
@@ -24,43 +26,48 @@ const (
 	Pacman TemplateType = "pacman"
 )
 
-var ValidColumn = []ColumnType{GoodThing, Learned, ShoutOut, WentWell, ToImprove, Action}
-var ValidTemplate = []TemplateType{Agile, Kanban, Pacman}
-
 type Board struct {
-	Id           uuid.UUID           `json:"id"`
-	Name         string              `json:"name"`
-	Template     TemplateType        `json:"template"`
-	Columns      []ColumnType        `json:"columns"`
-	CreatedById  uuid.UUID           `json:"created_by_id"`
-	CreatedBy    *CreateUserResponse `json:"created_by"`
-	ModifiedById uuid.UUID           `json:"modified_by_id"`
-	ModifiedBy   *CreateUserResponse `json:"modified_by"`
-	CreatedAt    time.Time           `json:"created_at"`
-	ModifiedAt   time.Time           `json:"modified_at"`
+	Id              uuid.UUID           `json:"id"`
+	Name            string              `json:"name"`
+	Template        TemplateType        `json:"template"`
+	Columns         []*BoardColumn      `json:"columns"`
+	Settings        BoardSettings       `json:"settings"`
+	Phase           BoardPhase          `json:"phase"`
+	PreviousBoardId *uuid.UUID          `json:"previous_board_id"`
+	TeamId          uuid.UUID           `json:"team_id"`
+	CreatedById     uuid.UUID           `json:"created_by_id"`
+	CreatedBy       *CreateUserResponse `json:"created_by"`
+	ModifiedById    uuid.UUID           `json:"modified_by_id"`
+	ModifiedBy      *CreateUserResponse `json:"modified_by"`
+	ClosedAt        *time.Time          `json:"closed_at"`
+	CreatedAt       time.Time           `json:"created_at"`
+	ModifiedAt      time.Time           `json:"modified_at"`
 }
 
 type CreateBoardRequest struct {
-	Name       string              `json:"name" validate:"required"`
-	Template   TemplateType        `json:"template" validate:"oneof=agile kanban pacman"`
-	Columns    []ColumnType        `json:"columns" validate:"required,min=1,dive,oneof=good_thing learned shout_out went_well to_improve action"`
-	CreatedBy  *CreateUserResponse `json:"created_by"`
-	ModifiedBy *CreateUserResponse `json:"modified_by"`
+	Name       string                     `json:"name" validate:"required"`
+	Template   TemplateType               `json:"template" validate:"required,template_key"`
+	Columns    []CreateBoardColumnRequest `json:"columns" validate:"omitempty,max=20,unique=Key,dive"`
+	Settings   *BoardSettings             `json:"settings"`
+	TeamId     string                     `json:"team_id" validate:"required"`
+	Team       *Team                      `json:"team"`
+	CreatedBy  *CreateUserResponse        `json:"created_by"`
+	ModifiedBy *CreateUserResponse        `json:"modified_by"`
 }
 
 type UpdateBoardRequest struct {
 	Name       string              `json:"name"`
-	Template   TemplateType        `json:"template" validate:"oneof=agile kanban pacman"`
-	Columns    []ColumnType        `json:"columns" validate:"required,min=1,dive,oneof=good_thing learned shout_out went_well to_improve action"`
+	Settings   *BoardSettings      `json:"settings"`
 	ModifiedBy *CreateUserResponse `json:"modified_by"`
 }
 
 func NewBoard(boardRequest *CreateBoardRequest) *Board {
-	return &Board{
+	board := &Board{
 		Id:           uuid.New(),
 		Name:         boardRequest.Name,
 		Template:     boardRequest.Template,
-		Columns:      boardRequest.Columns,
+		Phase:        PhaseDraft,
+		TeamId:       boardRequest.Team.Id,
 		CreatedById:  boardRequest.CreatedBy.Id,
 		CreatedBy:    boardRequest.CreatedBy,
 		ModifiedById: boardRequest.ModifiedBy.Id,
@@ -68,15 +75,30 @@ func NewBoard(boardRequest *CreateBoardRequest) *Board {
 		CreatedAt:    time.Now().UTC(),
 		ModifiedAt:   time.Now().UTC(),
 	}
+
+	if boardRequest.Settings != nil {
+		board.Settings = *boardRequest.Settings
+	}
+
+	for position, columnRequest := range boardRequest.Columns {
+		board.Columns = append(board.Columns, NewBoardColumn(board.Id, position, &columnRequest))
+	}
+
+	return board
 }
 
 func UpdateBoard(board *Board, boardRequest *UpdateBoardRequest) *Board {
 	board.Name = boardRequest.Name
-	board.Template = boardRequest.Template
-	board.Columns = boardRequest.Columns
+	if boardRequest.Settings != nil {
+		board.Settings = *boardRequest.Settings
+	}
 	board.ModifiedById = boardRequest.ModifiedBy.Id
 	board.ModifiedBy = boardRequest.ModifiedBy
 	board.ModifiedAt = time.Now().UTC()
 
 	return board
 }
+
+func (b *Board) IsClosed() bool {
+	return b.ClosedAt != nil
+}
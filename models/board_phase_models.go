@@ -0,0 +1,142 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BoardPhase string
+
+// The phases of a retro in the order they are run, a board can only move on
+// to the next one or be closed early.
+const (
+	PhaseDraft      BoardPhase = "draft"
+	PhaseCollecting BoardPhase = "collecting"
+	PhaseGrouping   BoardPhase = "grouping"
+	PhaseVoting     BoardPhase = "voting"
+	PhaseDiscussing BoardPhase = "discussing"
+	PhaseClosed     BoardPhase = "closed"
+)
+
+var boardPhases = []BoardPhase{PhaseDraft, PhaseCollecting, PhaseGrouping, PhaseVoting, PhaseDiscussing, PhaseClosed}
+
+// BoardActivity is something done with the feedback of a board, each phase
+// allows only some of them.
+type BoardActivity string
+
+const (
+	ActivityAddFeedback      BoardActivity = "add_feedback"
+	ActivityActionItems      BoardActivity = "action_items"
+	ActivityEditFeedback     BoardActivity = "edit_feedback"
+	ActivityMoveFeedback     BoardActivity = "move_feedback"
+	ActivityDeleteFeedback   BoardActivity = "delete_feedback"
+	ActivityGroupFeedback    BoardActivity = "group_feedback"
+	ActivityVote             BoardActivity = "vote"
+	ActivityModerateFeedback BoardActivity = "moderate_feedback"
+	ActivityResolveFeedback  BoardActivity = "resolve_feedback"
+)
+
+// Moderators can remove feedback and action items can be resolved in every
+// phase but closed, those are not listed here.
+var phaseActivities = map[BoardPhase][]BoardActivity{
+	PhaseCollecting: {ActivityAddFeedback, ActivityEditFeedback, ActivityMoveFeedback, ActivityDeleteFeedback},
+	PhaseGrouping:   {ActivityMoveFeedback, ActivityGroupFeedback},
+	PhaseVoting:     {ActivityVote},
+	PhaseDiscussing: {ActivityActionItems},
+}
+
+type BoardPhaseRequest struct {
+	Phase BoardPhase `json:"phase" validate:"required,oneof=draft collecting grouping voting discussing closed"`
+}
+
+// NextPhase returns the phase that follows, an empty phase when there is
+// none.
+func (p BoardPhase) NextPhase() BoardPhase {
+	for index, phase := range boardPhases[:len(boardPhases)-1] {
+		if phase == p {
+			return boardPhases[index+1]
+		}
+	}
+
+	return ""
+}
+
+// CanMoveTo reports whether the board may go to the phase from the one it is
+// in.
+func (b *Board) CanMoveTo(phase BoardPhase) bool {
+	if b.IsClosed() {
+		return false
+	}
+
+	return phase == PhaseClosed || phase == b.Phase.NextPhase()
+}
+
+// AllowsSettingsChange reports whether the settings of the board may still
+// be changed, once the feedback is being grouped the votes and the action
+// column are fixed.
+func (b *Board) AllowsSettingsChange() bool {
+	return b.Phase == PhaseDraft || b.Phase == PhaseCollecting
+}
+
+// Allows reports whether the current phase of the board allows the activity,
+// a closed board allows nothing.
+func (b *Board) Allows(activity BoardActivity) bool {
+	if b.IsClosed() {
+		return false
+	}
+
+	if activity == ActivityModerateFeedback || activity == ActivityResolveFeedback {
+		return true
+	}
+
+	for _, allowed := range phaseActivities[b.Phase] {
+		if allowed == activity {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsIn reports whether the activity is allowed on feedback in the
+// column. While discussing, action items can still be added, edited and
+// deleted in the action column.
+func (b *Board) AllowsIn(activity BoardActivity, column ColumnType) bool {
+	if b.Allows(activity) {
+		return true
+	}
+
+	switch activity {
+	case ActivityAddFeedback, ActivityEditFeedback, ActivityDeleteFeedback:
+		return column != "" && column == b.Settings.ActionColumn && b.Allows(ActivityActionItems)
+	}
+
+	return false
+}
+
+// FeedbackVote is one vote of a participant on a feedback, they can give
+// several to the same feedback as long as they have votes left.
+type FeedbackVote struct {
+	Id         uuid.UUID `json:"id"`
+	FeedbackId uuid.UUID `json:"feedback_id"`
+	BoardId    uuid.UUID `json:"board_id"`
+	UserId     uuid.UUID `json:"user_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// GroupFeedbackRequest stacks a feedback onto another feedback of the same
+// board, or onto the group that one is in.
+type GroupFeedbackRequest struct {
+	GroupId string `json:"group_id" validate:"required,uuid"`
+}
+
+func NewFeedbackVote(feedback *Feedback, userId uuid.UUID) *FeedbackVote {
+	return &FeedbackVote{
+		Id:         uuid.New(),
+		FeedbackId: feedback.Id,
+		BoardId:    feedback.BoardId,
+		UserId:     userId,
+		CreatedAt:  time.Now().UTC(),
+	}
+}
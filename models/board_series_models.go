@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var boardNumberPattern = regexp.MustCompile(`(\d+)(\D*)$`)
+
+// CloneBoardRequest starts the next board of a retro series. Without a name
+// the number in the name of the previous board is counted up, "Sprint 41
+// retro" is followed by "Sprint 42 retro".
+type CloneBoardRequest struct {
+	Name                 string `json:"name" validate:"max=100"`
+	CarryOverActionItems bool   `json:"carry_over_action_items"`
+}
+
+// NextBoardName counts up the last number in the name, keeping its leading
+// zeros, and adds a 2 to a name without one.
+func NextBoardName(name string) string {
+	match := boardNumberPattern.FindStringSubmatchIndex(name)
+	if match == nil {
+		return name + " 2"
+	}
+
+	digits := name[match[2]:match[3]]
+	number, err := strconv.ParseUint(digits, 10, 63)
+	if err != nil {
+		return name + " 2"
+	}
+
+	return name[:match[2]] + fmt.Sprintf("%0*d", len(digits), number+1) + name[match[3]:]
+}
+
+// CloneBoard copies the template, settings and columns of the board into a
+// new draft board that follows it in the series.
+func CloneBoard(previous *Board, request *CloneBoardRequest, createdBy *CreateUserResponse) *Board {
+	name := request.Name
+	if name == "" {
+		name = NextBoardName(previous.Name)
+	}
+
+	previousId := previous.Id
+	board := &Board{
+		Id:              uuid.New(),
+		Name:            name,
+		Template:        previous.Template,
+		Settings:        previous.Settings,
+		Phase:           PhaseDraft,
+		PreviousBoardId: &previousId,
+		TeamId:          previous.TeamId,
+		CreatedById:     createdBy.Id,
+		CreatedBy:       createdBy,
+		ModifiedById:    createdBy.Id,
+		ModifiedBy:      createdBy,
+		CreatedAt:       time.Now().UTC(),
+		ModifiedAt:      time.Now().UTC(),
+	}
+
+	for position, column := range previous.Columns {
+		board.Columns = append(board.Columns, NewBoardColumn(board.Id, position, &CreateBoardColumnRequest{
+			Key:         column.Key,
+			Title:       column.Title,
+			Description: column.Description,
+			Color:       column.Color,
+		}))
+	}
+
+	return board
+}
@@ -0,0 +1,203 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StartStopContinue TemplateType = "start_stop_continue"
+	FourLs            TemplateType = "four_ls"
+	MadSadGlad        TemplateType = "mad_sad_glad"
+	Sailboat          TemplateType = "sailboat"
+)
+
+// BoardSettings are the rules a board runs with. VotesPerParticipant of 0
+// means votes are not limited, ActionColumn is the key of the column that
+// holds the action items of the retro.
+type BoardSettings struct {
+	VotesPerParticipant int        `json:"votes_per_participant" validate:"min=0,max=100"`
+	ActionColumn        ColumnType `json:"action_column" validate:"omitempty,column_key"`
+}
+
+// BoardTemplate is what a board is created from. Built-in templates are part
+// of the catalog for everyone, custom templates belong to a team.
+type BoardTemplate struct {
+	Id          uuid.UUID                  `json:"id"`
+	Key         TemplateType               `json:"key"`
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	BuiltIn     bool                       `json:"built_in"`
+	TeamId      *uuid.UUID                 `json:"team_id"`
+	Columns     []CreateBoardColumnRequest `json:"columns"`
+	Settings    BoardSettings              `json:"settings"`
+	CreatedById uuid.UUID                  `json:"created_by_id"`
+	CreatedAt   time.Time                  `json:"created_at"`
+	ModifiedAt  time.Time                  `json:"modified_at"`
+}
+
+type CreateBoardTemplateRequest struct {
+	Key         TemplateType               `json:"key" validate:"required,template_key"`
+	Name        string                     `json:"name" validate:"required,max=100"`
+	Description string                     `json:"description" validate:"max=500"`
+	Columns     []CreateBoardColumnRequest `json:"columns" validate:"required,min=1,max=20,unique=Key,dive"`
+	Settings    BoardSettings              `json:"settings"`
+}
+
+type UpdateBoardTemplateRequest struct {
+	Name        string                     `json:"name" validate:"required,max=100"`
+	Description string                     `json:"description" validate:"max=500"`
+	Columns     []CreateBoardColumnRequest `json:"columns" validate:"required,min=1,max=20,unique=Key,dive"`
+	Settings    BoardSettings              `json:"settings"`
+}
+
+func builtInTemplate(key TemplateType, name, description string, actionColumn ColumnType, columns ...CreateBoardColumnRequest) *BoardTemplate {
+	return &BoardTemplate{
+		Key:         key,
+		Name:        name,
+		Description: description,
+		BuiltIn:     true,
+		Columns:     columns,
+		Settings:    BoardSettings{VotesPerParticipant: 5, ActionColumn: actionColumn},
+	}
+}
+
+var builtInTemplates = []*BoardTemplate{
+	builtInTemplate(Agile, "Agile", "What went well, what to improve and what to do about it.", Action,
+		CreateBoardColumnRequest{Key: WentWell, Title: "Went well", Description: "What went well this sprint?", Color: "#2e7d32"},
+		CreateBoardColumnRequest{Key: ToImprove, Title: "To improve", Description: "What could have gone better?", Color: "#c62828"},
+		CreateBoardColumnRequest{Key: Action, Title: "Action items", Description: "What will we do differently?", Color: "#1565c0"},
+	),
+	builtInTemplate(Kanban, "Kanban", "A look at the flow of work since the last retro.", Action,
+		CreateBoardColumnRequest{Key: GoodThing, Title: "Good things", Description: "What helped the work flow?", Color: "#2e7d32"},
+		CreateBoardColumnRequest{Key: ToImprove, Title: "Bottlenecks", Description: "Where did work get stuck?", Color: "#ef6c00"},
+		CreateBoardColumnRequest{Key: Action, Title: "Action items", Description: "What will we change in the process?", Color: "#1565c0"},
+	),
+	builtInTemplate(Pacman, "Pacman", "What we learned, what was good and who deserves a shout out.", "",
+		CreateBoardColumnRequest{Key: GoodThing, Title: "Good things", Description: "What was good?", Color: "#f9a825"},
+		CreateBoardColumnRequest{Key: Learned, Title: "Learned", Description: "What did you learn?", Color: "#6a1b9a"},
+		CreateBoardColumnRequest{Key: ShoutOut, Title: "Shout outs", Description: "Who deserves a thank you?", Color: "#00838f"},
+	),
+	builtInTemplate(StartStopContinue, "Start, Stop, Continue", "What the team should start, stop and keep doing.", "",
+		CreateBoardColumnRequest{Key: "start", Title: "Start", Description: "What should we start doing?", Color: "#2e7d32"},
+		CreateBoardColumnRequest{Key: "stop", Title: "Stop", Description: "What should we stop doing?", Color: "#c62828"},
+		CreateBoardColumnRequest{Key: "continue", Title: "Continue", Description: "What should we keep doing?", Color: "#1565c0"},
+	),
+	builtInTemplate(FourLs, "4Ls", "Liked, learned, lacked and longed for.", "",
+		CreateBoardColumnRequest{Key: "liked", Title: "Liked", Description: "What did you enjoy?", Color: "#2e7d32"},
+		CreateBoardColumnRequest{Key: Learned, Title: "Learned", Description: "What did you learn?", Color: "#6a1b9a"},
+		CreateBoardColumnRequest{Key: "lacked", Title: "Lacked", Description: "What was missing?", Color: "#c62828"},
+		CreateBoardColumnRequest{Key: "longed_for", Title: "Longed for", Description: "What do you wish we had?", Color: "#ef6c00"},
+	),
+	builtInTemplate(MadSadGlad, "Mad, Sad, Glad", "How the sprint made the team feel.", "",
+		CreateBoardColumnRequest{Key: "mad", Title: "Mad", Description: "What drove you crazy?", Color: "#c62828"},
+		CreateBoardColumnRequest{Key: "sad", Title: "Sad", Description: "What disappointed you?", Color: "#1565c0"},
+		CreateBoardColumnRequest{Key: "glad", Title: "Glad", Description: "What made you happy?", Color: "#2e7d32"},
+	),
+	builtInTemplate(Sailboat, "Sailboat", "What moves the team towards its goal and what holds it back.", Action,
+		CreateBoardColumnRequest{Key: "wind", Title: "Wind", Description: "What pushes us forward?", Color: "#2e7d32"},
+		CreateBoardColumnRequest{Key: "anchors", Title: "Anchors", Description: "What slows us down?", Color: "#6d4c41"},
+		CreateBoardColumnRequest{Key: "rocks", Title: "Rocks", Description: "What risks lie ahead?", Color: "#c62828"},
+		CreateBoardColumnRequest{Key: "island", Title: "Island", Description: "Where are we heading?", Color: "#f9a825"},
+		CreateBoardColumnRequest{Key: Action, Title: "Action items", Description: "What will we do about it?", Color: "#1565c0"},
+	),
+}
+
+// BuiltInTemplates returns the templates every team can create boards from.
+func BuiltInTemplates() []*BoardTemplate {
+	templates := make([]*BoardTemplate, 0, len(builtInTemplates))
+	for _, template := range builtInTemplates {
+		templates = append(templates, template.copy())
+	}
+
+	return templates
+}
+
+// BuiltInTemplate returns the built-in template with the key, nil when there
+// is none.
+func BuiltInTemplate(key TemplateType) *BoardTemplate {
+	for _, template := range builtInTemplates {
+		if template.Key == key {
+			return template.copy()
+		}
+	}
+
+	return nil
+}
+
+// copy keeps callers from changing the columns of the catalog.
+func (t *BoardTemplate) copy() *BoardTemplate {
+	template := *t
+	template.Columns = append([]CreateBoardColumnRequest{}, t.Columns...)
+	return &template
+}
+
+func hasColumnKey(columns []CreateBoardColumnRequest, key ColumnType) bool {
+	for _, column := range columns {
+		if column.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasColumn reports whether the template creates a column with the key.
+func (t *BoardTemplate) HasColumn(key ColumnType) bool {
+	return hasColumnKey(t.Columns, key)
+}
+
+// HasValidSettings reports whether the action column, when set, is one of
+// the columns of the template.
+func (t *BoardTemplate) HasValidSettings() bool {
+	return t.Settings.ActionColumn == "" || t.HasColumn(t.Settings.ActionColumn)
+}
+
+// HasValidSettings reports whether the action column, when set, is one of
+// the columns of the board.
+func (b *Board) HasValidSettings() bool {
+	return b.Settings.ActionColumn == "" || b.HasColumn(b.Settings.ActionColumn)
+}
+
+func NewBoardTemplate(teamId, createdById uuid.UUID, request *CreateBoardTemplateRequest) *BoardTemplate {
+	return &BoardTemplate{
+		Id:          uuid.New(),
+		Key:         request.Key,
+		Name:        request.Name,
+		Description: request.Description,
+		TeamId:      &teamId,
+		Columns:     request.Columns,
+		Settings:    request.Settings,
+		CreatedById: createdById,
+		CreatedAt:   time.Now().UTC(),
+		ModifiedAt:  time.Now().UTC(),
+	}
+}
+
+func UpdateBoardTemplate(template *BoardTemplate, request *UpdateBoardTemplateRequest) *BoardTemplate {
+	template.Name = request.Name
+	template.Description = request.Description
+	template.Columns = request.Columns
+	template.Settings = request.Settings
+	template.ModifiedAt = time.Now().UTC()
+
+	return template
+}
+
+// ApplyBoardTemplate fills in the columns and settings the request leaves
+// out from the template. The action column of the template is dropped when
+// the request brings columns without it.
+func ApplyBoardTemplate(request *CreateBoardRequest, template *BoardTemplate) {
+	if len(request.Columns) == 0 {
+		request.Columns = append([]CreateBoardColumnRequest{}, template.Columns...)
+	}
+
+	if request.Settings == nil {
+		settings := template.Settings
+		if !hasColumnKey(request.Columns, settings.ActionColumn) {
+			settings.ActionColumn = ""
+		}
+		request.Settings = &settings
+	}
+}
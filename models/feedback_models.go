@@ -11,6 +11,11 @@ type Feedback struct {
 	Message     string              `json:"message" validate:"required"`
 	BoardId     uuid.UUID           `json:"board_id"`
 	Board       *Board              `json:"board"`
+	Column      ColumnType          `json:"column"`
+	GroupId     *uuid.UUID          `json:"group_id"`
+	Votes       int                 `json:"votes"`
+	ResolvedAt  *time.Time          `json:"resolved_at"`
+	OriginId    *uuid.UUID          `json:"origin_id"`
 	CreatedById uuid.UUID           `json:"created_by_id"`
 	CreatedBy   *CreateUserResponse `json:"created_by"`
 	CreatedAt   time.Time           `json:"created_at"`
@@ -20,6 +25,7 @@ type Feedback struct {
 type CreateFeedbackRequest struct {
 	Message   string              `json:"message" validate:"required"`
 	BoardId   string              `json:"board_id" validate:"required"`
+	Column    ColumnType          `json:"column" validate:"required"`
 	Board     *Board              `json:"board"`
 	CreatedBy *CreateUserResponse `json:"created_by"`
 }
@@ -28,12 +34,36 @@ type UpdateFeedbackRequest struct {
 	Message string `json:"message" validate:"required"`
 }
 
+// MoveFeedbackRequest moves a feedback to another column of its board.
+type MoveFeedbackRequest struct {
+	Column ColumnType `json:"column" validate:"required"`
+}
+
+// FeedbackRemoval records a feedback removed by a moderator rather than its
+// author, with a copy of the message since the feedback itself is gone.
+type FeedbackRemoval struct {
+	Id          uuid.UUID           `json:"id"`
+	FeedbackId  uuid.UUID           `json:"feedback_id"`
+	BoardId     uuid.UUID           `json:"board_id"`
+	Message     string              `json:"message"`
+	CreatedById uuid.UUID           `json:"created_by_id"`
+	RemovedById uuid.UUID           `json:"removed_by_id"`
+	RemovedBy   *CreateUserResponse `json:"removed_by"`
+	Reason      string              `json:"reason"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+type RemoveFeedbackRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
 func NewFeedback(feedbackRequest *CreateFeedbackRequest) *Feedback {
 	return &Feedback{
 		Id:          uuid.New(),
 		Message:     feedbackRequest.Message,
 		BoardId:     feedbackRequest.Board.Id,
 		Board:       feedbackRequest.Board,
+		Column:      feedbackRequest.Column,
 		CreatedById: feedbackRequest.CreatedBy.Id,
 		CreatedBy:   feedbackRequest.CreatedBy,
 		CreatedAt:   time.Now().UTC(),
@@ -47,3 +77,24 @@ func UpdateFeedback(feedback *Feedback, feedbackRequest *UpdateFeedbackRequest)
 
 	return feedback
 }
+
+func MoveFeedback(feedback *Feedback, column ColumnType) *Feedback {
+	feedback.Column = column
+	feedback.ModifiedAt = time.Now().UTC()
+
+	return feedback
+}
+
+func NewFeedbackRemoval(feedback *Feedback, removedBy *CreateUserResponse, request *RemoveFeedbackRequest) *FeedbackRemoval {
+	return &FeedbackRemoval{
+		Id:          uuid.New(),
+		FeedbackId:  feedback.Id,
+		BoardId:     feedback.BoardId,
+		Message:     feedback.Message,
+		CreatedById: feedback.CreatedById,
+		RemovedById: removedBy.Id,
+		RemovedBy:   removedBy,
+		Reason:      request.Reason,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/google/uuid"
+)
+
+const (
+	BOARD_JOIN_LINK_LIFETIME = time.Hour * 24
+	BOARD_JOIN_PIN_LENGTH    = 8
+	GUEST_EMAIL_DOMAIN       = "guest.invalid"
+)
+
+// BoardJoinLink lets visitors without an account join a single board, either
+// through the token of the link or by typing the pin.
+type BoardJoinLink struct {
+	Id          uuid.UUID  `json:"id"`
+	BoardId     uuid.UUID  `json:"board_id"`
+	TokenHash   string     `json:"-"`
+	PinHash     string     `json:"-"`
+	CreatedById uuid.UUID  `json:"created_by_id"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type CreateBoardJoinLinkRequest struct {
+	ExpiresInHours int `json:"expires_in_hours" validate:"omitempty,min=1,max=168"`
+}
+
+// CreateBoardJoinLinkResponse is the only time the token and the pin are
+// shown, afterwards only their hashes are kept.
+type CreateBoardJoinLinkResponse struct {
+	*BoardJoinLink
+	Token string `json:"token"`
+	Pin   string `json:"pin"`
+	URL   string `json:"url"`
+}
+
+type JoinBoardRequest struct {
+	Token       string `json:"token" validate:"required_without=Pin"`
+	Pin         string `json:"pin" validate:"required_without=Token"`
+	DisplayName string `json:"display_name" validate:"required,max=50"`
+}
+
+type JoinBoardResponse struct {
+	Token     string              `json:"token"`
+	ExpiresAt time.Time           `json:"expires_at"`
+	BoardId   uuid.UUID           `json:"board_id"`
+	User      *CreateUserResponse `json:"user"`
+}
+
+func NewBoardJoinLink(boardId, createdById uuid.UUID, tokenHash, pinHash string, request *CreateBoardJoinLinkRequest) *BoardJoinLink {
+	lifetime := BOARD_JOIN_LINK_LIFETIME
+	if request.ExpiresInHours > 0 {
+		lifetime = time.Duration(request.ExpiresInHours) * time.Hour
+	}
+
+	return &BoardJoinLink{
+		Id:          uuid.New(),
+		BoardId:     boardId,
+		TokenHash:   tokenHash,
+		PinHash:     pinHash,
+		CreatedById: createdById,
+		ExpiresAt:   time.Now().UTC().Add(lifetime),
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+func (l *BoardJoinLink) IsValid() bool {
+	return l.RevokedAt == nil && time.Now().UTC().Before(l.ExpiresAt)
+}
+
+// GenerateBoardJoinPin returns a random numeric pin that is easy to read out
+// in a meeting.
+func GenerateBoardJoinPin() (string, error) {
+	pin := make([]byte, BOARD_JOIN_PIN_LENGTH)
+	for i := range pin {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		pin[i] = byte('0' + digit.Int64())
+	}
+
+	return string(pin), nil
+}
+
+// NewGuestUser builds the temporary identity of a visitor joining through a
+// link. Like Google users it gets a random password nobody knows, and the
+// email can not receive mail so it never collides with a real account.
+func NewGuestUser(displayName string) (*User, error) {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+
+	randomPassword, err := common.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	username := "guest_" + hex.EncodeToString(suffix)
+	return NewUser(&CreateUserRequest{
+		FirstName: displayName,
+		Username:  username,
+		Password:  randomPassword,
+		Email:     username + "@" + GUEST_EMAIL_DOMAIN,
+	}, GuestUser)
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LockoutType string
+
+const (
+	UsernameLockout LockoutType = "username"
+	IPLockout       LockoutType = "ip"
+	// MFALockout counts wrong second factor codes of a username, a correct
+	// password does not reset it.
+	MFALockout LockoutType = "mfa"
+)
+
+type AccountLockout struct {
+	Id             uuid.UUID   `json:"id"`
+	Username       string      `json:"username"`
+	IPAddress      string      `json:"ip_address"`
+	LockoutType    LockoutType `json:"lockout_type"`
+	FailedAttempts int         `json:"failed_attempts"`
+	LockedUntil    time.Time   `json:"locked_until"`
+	UnlockedById   *uuid.UUID  `json:"unlocked_by_id"`
+	UnlockedAt     *time.Time  `json:"unlocked_at"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+func NewAccountLockout(username, ipAddress string, lockoutType LockoutType, failedAttempts int, lockout time.Duration) *AccountLockout {
+	return &AccountLockout{
+		Id:             uuid.New(),
+		Username:       username,
+		IPAddress:      ipAddress,
+		LockoutType:    lockoutType,
+		FailedAttempts: failedAttempts,
+		LockedUntil:    time.Now().UTC().Add(lockout),
+		CreatedAt:      time.Now().UTC(),
+	}
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const MFA_RECOVERY_CODE_COUNT = 10
+
+type UserMFA struct {
+	UserId       uuid.UUID  `json:"user_id"`
+	Secret       string     `json:"-"`
+	Enabled      bool       `json:"enabled"`
+	LastUsedStep int64      `json:"-"`
+	ConfirmedAt  *time.Time `json:"confirmed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ModifiedAt   time.Time  `json:"modified_at"`
+}
+
+// NewUserMFA starts an enrollment, it is only enabled once the user confirms
+// a code generated from the secret.
+func NewUserMFA(userId uuid.UUID, secret string) *UserMFA {
+	return &UserMFA{
+		UserId:     userId,
+		Secret:     secret,
+		CreatedAt:  time.Now().UTC(),
+		ModifiedAt: time.Now().UTC(),
+	}
+}
+
+func (m *UserMFA) Confirm(step int64) {
+	confirmedAt := time.Now().UTC()
+	m.Enabled = true
+	m.LastUsedStep = step
+	m.ConfirmedAt = &confirmedAt
+	m.ModifiedAt = confirmedAt
+}
+
+type MFARecoveryCode struct {
+	Id        uuid.UUID  `json:"id"`
+	UserId    uuid.UUID  `json:"user_id"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func NewMFARecoveryCode(userId uuid.UUID, codeHash string) *MFARecoveryCode {
+	return &MFARecoveryCode{
+		Id:        uuid.New(),
+		UserId:    userId,
+		CodeHash:  codeHash,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
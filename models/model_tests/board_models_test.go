@@ -2,6 +2,7 @@ package model_tests
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Aakash-Pandit/reetro-golang/models"
 )
@@ -10,15 +11,216 @@ func TestNewBoard(t *testing.T) {
 	createBoardRequest := &models.CreateBoardRequest{
 		Name:     "test_board_name",
 		Template: models.Agile,
-		Columns:  []models.ColumnType{models.WentWell, models.ToImprove, models.Action},
+		Columns: []models.CreateBoardColumnRequest{
+			{Key: models.WentWell, Title: "Went well"},
+			{Key: models.ToImprove, Title: "To improve"},
+		},
 	}
 
 	user := TestMockCreateUserResponse()
 	createBoardRequest.CreatedBy = user
 	createBoardRequest.ModifiedBy = user
+	createBoardRequest.Team = models.NewTeam(&models.CreateTeamRequest{Name: "test_team"}, user.Id)
 	board := models.NewBoard(createBoardRequest)
 
 	if board.Name != createBoardRequest.Name {
 		t.Errorf("returned unexpected output: got %v want %v", board.Name, createBoardRequest.Name)
 	}
+
+	if board.TeamId != createBoardRequest.Team.Id {
+		t.Errorf("returned unexpected output: got %v want %v", board.TeamId, createBoardRequest.Team.Id)
+	}
+
+	if len(board.Columns) != 2 || board.Columns[1].Key != models.ToImprove || board.Columns[1].Position != 1 || board.Columns[1].BoardId != board.Id {
+		t.Errorf("returned unexpected output: got %v columns", len(board.Columns))
+	}
+}
+
+func TestBoardHasColumn(t *testing.T) {
+	board := TestMockBoard()
+
+	if !board.HasColumn(models.WentWell) {
+		t.Errorf("returned unexpected output: %v is a column of the board", models.WentWell)
+	}
+
+	if board.HasColumn(models.ShoutOut) {
+		t.Errorf("returned unexpected output: %v is not a column of the board", models.ShoutOut)
+	}
+}
+
+func TestColumnKeyFromTitle(t *testing.T) {
+	tests := map[string]models.ColumnType{
+		"Went well":          models.WentWell,
+		"  Puzzles & Risks ": "puzzles_risks",
+		"Q3 goals!":          "q3_goals",
+		"???":                "",
+	}
+
+	for title, want := range tests {
+		if got := models.ColumnKeyFromTitle(title); got != want {
+			t.Errorf("returned unexpected output for %q: got %v want %v", title, got, want)
+		}
+	}
+}
+
+func TestUpdateBoardColumn(t *testing.T) {
+	column := TestMockBoard().Columns[0]
+	description := "What made you proud?"
+
+	column = models.UpdateBoardColumn(column, &models.UpdateBoardColumnRequest{Title: "Wins", Description: &description})
+
+	if column.Title != "Wins" || column.Description != description {
+		t.Errorf("returned unexpected output: got %v and %v", column.Title, column.Description)
+	}
+
+	if column.Key != models.WentWell {
+		t.Errorf("returned unexpected output: got %v want %v", column.Key, models.WentWell)
+	}
+}
+
+func TestBuiltInTemplates(t *testing.T) {
+	for _, template := range models.BuiltInTemplates() {
+		request := &models.CreateBoardTemplateRequest{Key: template.Key, Name: template.Name, Description: template.Description, Columns: template.Columns, Settings: template.Settings}
+		if err := models.ValidateStruct(request); err != nil {
+			t.Errorf("returned unexpected output for %v: got %v want %v", template.Key, err, nil)
+		}
+
+		if !template.HasValidSettings() {
+			t.Errorf("returned unexpected output: %v has an unknown action column", template.Key)
+		}
+	}
+
+	if models.BuiltInTemplate(models.MadSadGlad) == nil {
+		t.Errorf("returned unexpected output: %v is a built-in template", models.MadSadGlad)
+	}
+}
+
+func TestApplyBoardTemplate(t *testing.T) {
+	template := models.BuiltInTemplate(models.Agile)
+
+	request := &models.CreateBoardRequest{Template: models.Agile}
+	models.ApplyBoardTemplate(request, template)
+
+	if len(request.Columns) != len(template.Columns) || request.Settings.ActionColumn != models.Action {
+		t.Errorf("returned unexpected output: got %v columns and action column %v", len(request.Columns), request.Settings.ActionColumn)
+	}
+
+	request = &models.CreateBoardRequest{Template: models.Agile, Columns: []models.CreateBoardColumnRequest{{Key: "risks", Title: "Risks"}}}
+	models.ApplyBoardTemplate(request, template)
+
+	if len(request.Columns) != 1 || request.Settings.ActionColumn != "" {
+		t.Errorf("returned unexpected output: got %v columns and action column %v", len(request.Columns), request.Settings.ActionColumn)
+	}
+}
+
+func TestBoardPhaseTransitions(t *testing.T) {
+	board := TestMockBoard()
+	board.Phase = models.PhaseDraft
+
+	for _, phase := range []models.BoardPhase{models.PhaseCollecting, models.PhaseGrouping, models.PhaseVoting, models.PhaseDiscussing} {
+		if !board.CanMoveTo(models.PhaseClosed) {
+			t.Errorf("returned unexpected output: %v cannot be closed", board.Phase)
+		}
+
+		if !board.CanMoveTo(phase) {
+			t.Errorf("returned unexpected output: %v cannot move to %v", board.Phase, phase)
+		}
+
+		if phase.NextPhase() != models.PhaseClosed && board.CanMoveTo(phase.NextPhase()) {
+			t.Errorf("returned unexpected output: %v can skip to %v", board.Phase, phase.NextPhase())
+		}
+
+		board.Phase = phase
+	}
+
+	closedAt := time.Now().UTC()
+	board.ClosedAt = &closedAt
+	board.Phase = models.PhaseClosed
+
+	if board.CanMoveTo(models.PhaseClosed) || board.CanMoveTo(models.PhaseDraft) {
+		t.Errorf("returned unexpected output: a closed board can change its phase")
+	}
+}
+
+func TestBoardAllowsIn(t *testing.T) {
+	board := TestMockBoard()
+	board.Settings.ActionColumn = models.Action
+
+	tests := []struct {
+		phase    models.BoardPhase
+		activity models.BoardActivity
+		column   models.ColumnType
+		want     bool
+	}{
+		{models.PhaseDraft, models.ActivityAddFeedback, models.WentWell, false},
+		{models.PhaseCollecting, models.ActivityAddFeedback, models.WentWell, true},
+		{models.PhaseCollecting, models.ActivityVote, models.WentWell, false},
+		{models.PhaseGrouping, models.ActivityGroupFeedback, models.WentWell, true},
+		{models.PhaseGrouping, models.ActivityEditFeedback, models.WentWell, false},
+		{models.PhaseVoting, models.ActivityVote, models.WentWell, true},
+		{models.PhaseVoting, models.ActivityModerateFeedback, models.WentWell, true},
+		{models.PhaseDiscussing, models.ActivityAddFeedback, models.WentWell, false},
+		{models.PhaseDiscussing, models.ActivityAddFeedback, models.Action, true},
+		{models.PhaseDiscussing, models.ActivityEditFeedback, models.Action, true},
+		{models.PhaseDiscussing, models.ActivityMoveFeedback, models.Action, false},
+	}
+
+	for _, test := range tests {
+		board.Phase = test.phase
+		if got := board.AllowsIn(test.activity, test.column); got != test.want {
+			t.Errorf("returned unexpected output for %v in %v: got %v want %v", test.activity, test.phase, got, test.want)
+		}
+	}
+
+	closedAt := time.Now().UTC()
+	board.ClosedAt = &closedAt
+	board.Phase = models.PhaseClosed
+
+	if board.Allows(models.ActivityModerateFeedback) {
+		t.Errorf("returned unexpected output: a closed board allows moderating feedback")
+	}
+}
+
+func TestNextBoardName(t *testing.T) {
+	tests := map[string]string{
+		"Sprint 41 retro": "Sprint 42 retro",
+		"Sprint 9":        "Sprint 10",
+		"Retro 2024-07":   "Retro 2024-08",
+		"Week 009":        "Week 010",
+		"Team retro":      "Team retro 2",
+	}
+
+	for name, want := range tests {
+		if got := models.NextBoardName(name); got != want {
+			t.Errorf("returned unexpected output for %v: got %v want %v", name, got, want)
+		}
+	}
+}
+
+func TestCloneBoard(t *testing.T) {
+	previous := TestMockBoard()
+	previous.Phase = models.PhaseClosed
+	previous.Settings.ActionColumn = models.Action
+	closedAt := time.Now().UTC()
+	previous.ClosedAt = &closedAt
+
+	board := models.CloneBoard(previous, &models.CloneBoardRequest{}, TestMockCreateUserResponse())
+
+	if board.Id == previous.Id || board.PreviousBoardId == nil || *board.PreviousBoardId != previous.Id {
+		t.Errorf("returned unexpected output: got previous board %v want %v", board.PreviousBoardId, previous.Id)
+	}
+
+	if board.IsClosed() || board.Phase != models.PhaseDraft || board.Settings != previous.Settings {
+		t.Errorf("returned unexpected output: got phase %v and settings %v", board.Phase, board.Settings)
+	}
+
+	if len(board.Columns) != len(previous.Columns) {
+		t.Fatalf("returned unexpected output: got %v columns want %v", len(board.Columns), len(previous.Columns))
+	}
+
+	for index, column := range board.Columns {
+		if column.BoardId != board.Id || column.Id == previous.Columns[index].Id || column.Key != previous.Columns[index].Key {
+			t.Errorf("returned unexpected output: column %v was not copied", previous.Columns[index].Key)
+		}
+	}
 }
@@ -21,10 +21,15 @@ func TestMockCreateUserResponse() *models.CreateUserResponse {
 }
 
 func TestMockBoard() *models.Board {
-	return &models.Board{
+	board := &models.Board{
 		Id:       uuid.New(),
 		Name:     "test_board_name",
 		Template: models.Agile,
-		Columns:  []models.ColumnType{models.WentWell, models.ToImprove, models.Action},
 	}
+
+	for position, key := range []models.ColumnType{models.WentWell, models.ToImprove, models.Action} {
+		board.Columns = append(board.Columns, models.NewBoardColumn(board.Id, position, &models.CreateBoardColumnRequest{Key: key, Title: string(key)}))
+	}
+
+	return board
 }
@@ -9,6 +9,7 @@ import (
 func TestNewFeedback(t *testing.T) {
 	createFeedbackRequest := &models.CreateFeedbackRequest{
 		Message: "This is a feedback",
+		Column:  models.WentWell,
 	}
 
 	createFeedbackRequest.CreatedBy = TestMockCreateUserResponse()
@@ -18,4 +19,8 @@ func TestNewFeedback(t *testing.T) {
 	if feedback.Message != createFeedbackRequest.Message {
 		t.Errorf("returned unexpected output: got %v want %v", feedback.Message, createFeedbackRequest.Message)
 	}
+
+	if feedback.Column != createFeedbackRequest.Column {
+		t.Errorf("returned unexpected output: got %v want %v", feedback.Column, createFeedbackRequest.Column)
+	}
 }
@@ -0,0 +1,32 @@
+package model_tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+func TestPasswordResetTokenIsValid(t *testing.T) {
+	token := models.NewPasswordResetToken(uuid.New(), "test_token_hash")
+
+	if !token.IsValid() {
+		t.Errorf("returned unexpected output: got %v want %v", token.IsValid(), true)
+	}
+
+	usedAt := time.Now().UTC()
+	token.UsedAt = &usedAt
+	if token.IsValid() {
+		t.Errorf("returned unexpected output for used token: got %v want %v", token.IsValid(), false)
+	}
+}
+
+func TestExpiredPasswordResetToken(t *testing.T) {
+	token := models.NewPasswordResetToken(uuid.New(), "test_token_hash")
+	token.ExpiresAt = time.Now().UTC().Add(-time.Minute)
+
+	if token.IsValid() {
+		t.Errorf("returned unexpected output: got %v want %v", token.IsValid(), false)
+	}
+}
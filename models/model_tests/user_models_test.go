@@ -13,10 +13,9 @@ func TestNewUser(t *testing.T) {
 		Username:  "test_username",
 		Password:  "password",
 		Email:     "test@email.com",
-		UserType:  "guest_user",
 	}
 
-	user, err := models.NewUser(createUserRequest)
+	user, err := models.NewUser(createUserRequest, models.GuestUser)
 
 	if err != nil {
 		t.Errorf("returned unexpected error: got %v", err)
@@ -29,4 +28,58 @@ func TestNewUser(t *testing.T) {
 	if user.Email != createUserRequest.Email {
 		t.Errorf("returned unexpected output: got %v want %v", user.Email, createUserRequest.Email)
 	}
+
+	if user.UserType != models.GuestUser {
+		t.Errorf("returned unexpected output: got %v want %v", user.UserType, models.GuestUser)
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	user := TestMockCreateUserResponse()
+	updateUserRequest := &models.UpdateUserRequest{
+		FirstName: "updated_first_name",
+		UserType:  models.TeamMember,
+	}
+
+	user = models.UpdateUser(user, updateUserRequest)
+
+	if user.FirstName != updateUserRequest.FirstName {
+		t.Errorf("returned unexpected output: got %v want %v", user.FirstName, updateUserRequest.FirstName)
+	}
+
+	if user.LastName != "test_last_name" {
+		t.Errorf("returned unexpected output: got %v want %v", user.LastName, "test_last_name")
+	}
+
+	if user.UserType != models.TeamMember {
+		t.Errorf("returned unexpected output: got %v want %v", user.UserType, models.TeamMember)
+	}
+}
+
+func TestDeletedUser(t *testing.T) {
+	user := models.DeletedUser()
+
+	if user.FirstName != models.DELETED_USER_NAME {
+		t.Errorf("returned unexpected output: got %v want %v", user.FirstName, models.DELETED_USER_NAME)
+	}
+
+	if user.Email != "" || user.Username != "" {
+		t.Errorf("returned personal data for a deleted user: got %v", user)
+	}
+}
+
+func TestNewExternalUser(t *testing.T) {
+	user, err := models.NewExternalUser("test@email.com", "", "test_last_name", models.TeamMember)
+
+	if err != nil {
+		t.Errorf("returned unexpected error: got %v", err)
+	}
+
+	if user.FirstName != "test" {
+		t.Errorf("returned unexpected output: got %v want %v", user.FirstName, "test")
+	}
+
+	if user.UserType != models.TeamMember || !user.EmailVerified {
+		t.Errorf("returned unexpected output: got %v %v", user.UserType, user.EmailVerified)
+	}
 }
@@ -11,9 +11,8 @@ func TestValidateUserStruct(t *testing.T) {
 		FirstName: "test_first_name",
 		LastName:  "test_last_name",
 		Username:  "test_username",
-		Password:  "password",
+		Password:  "Retro-Board-42",
 		Email:     "test@email.com",
-		UserType:  "guest_user",
 	}
 
 	err := models.ValidateStruct(createUserRequest)
@@ -22,6 +21,28 @@ func TestValidateUserStruct(t *testing.T) {
 	}
 }
 
+func TestValidateAdminCreateUserStruct(t *testing.T) {
+	createUserRequest := models.CreateUserRequest{
+		FirstName: "test_first_name",
+		LastName:  "test_last_name",
+		Username:  "test_username",
+		Password:  "Retro-Board-42",
+		Email:     "test@email.com",
+	}
+
+	for userType, valid := range map[models.Role]bool{
+		models.TeamMember: true,
+		models.SuperAdmin: true,
+		"":                false,
+		"owner":           false,
+	} {
+		err := models.ValidateStruct(&models.AdminCreateUserRequest{CreateUserRequest: createUserRequest, UserType: userType})
+		if (err == nil) != valid {
+			t.Errorf("returned unexpected output for %q: got %v", userType, err)
+		}
+	}
+}
+
 func TestValidateUserStructWithEmptyFirstName(t *testing.T) {
 	createUserRequest := &models.CreateUserRequest{
 		FirstName: "",
@@ -38,6 +59,7 @@ func TestValidateFeedbackStruct(t *testing.T) {
 	createFeedbackRequest := &models.CreateFeedbackRequest{
 		Message: "This is a feedback",
 		BoardId: "992c4b2b-f83f-45bc-a258-8b1074fb7a8e",
+		Column:  models.WentWell,
 	}
 
 	err := models.ValidateStruct(createFeedbackRequest)
@@ -59,3 +81,146 @@ func TestFeedbackWithEmptyMessage(t *testing.T) {
 		t.Errorf("returned unexpected output: got %v want %v", feedback.Message, createFeedbackRequest.Message)
 	}
 }
+
+func passwordViolations(errs []*models.ErrorResponse) []string {
+	violations := []string{}
+	for _, err := range errs {
+		if err.Tag == "password" {
+			violations = append(violations, err.Value)
+		}
+	}
+	return violations
+}
+
+func TestValidateWeakPassword(t *testing.T) {
+	createUserRequest := &models.CreateUserRequest{
+		FirstName: "test_first_name",
+		LastName:  "test_last_name",
+		Username:  "test_username",
+		Password:  "abc",
+		Email:     "test@email.com",
+	}
+
+	violations := passwordViolations(models.ValidateStruct(createUserRequest))
+	expected := []string{"min_length=8", "uppercase", "digit"}
+	if len(violations) != len(expected) {
+		t.Fatalf("returned unexpected output: got %v want %v", violations, expected)
+	}
+
+	for i := range expected {
+		if violations[i] != expected[i] {
+			t.Errorf("returned unexpected output: got %v want %v", violations[i], expected[i])
+		}
+	}
+}
+
+func TestValidatePasswordMatchingUsername(t *testing.T) {
+	createUserRequest := &models.CreateUserRequest{
+		FirstName: "test_first_name",
+		LastName:  "test_last_name",
+		Username:  "Test_Username1",
+		Password:  "Test_Username1",
+		Email:     "test@email.com",
+	}
+
+	violations := passwordViolations(models.ValidateStruct(createUserRequest))
+	if len(violations) != 1 || violations[0] != "not_username" {
+		t.Errorf("returned unexpected output: got %v want %v", violations, []string{"not_username"})
+	}
+}
+
+func TestValidatePasswordMatchingEmail(t *testing.T) {
+	createUserRequest := &models.CreateUserRequest{
+		FirstName: "test_first_name",
+		LastName:  "test_last_name",
+		Username:  "test_username",
+		Password:  "Jane.Doe2024",
+		Email:     "jane.doe2024@email.com",
+	}
+
+	violations := passwordViolations(models.ValidateStruct(createUserRequest))
+	if len(violations) != 1 || violations[0] != "not_email" {
+		t.Errorf("returned unexpected output: got %v want %v", violations, []string{"not_email"})
+	}
+}
+
+func TestValidateCommonPassword(t *testing.T) {
+	createUserRequest := &models.CreateUserRequest{
+		FirstName: "test_first_name",
+		LastName:  "test_last_name",
+		Username:  "test_username",
+		Password:  "Password123",
+		Email:     "test@email.com",
+	}
+
+	violations := passwordViolations(models.ValidateStruct(createUserRequest))
+	if len(violations) != 1 || violations[0] != "common_password" {
+		t.Errorf("returned unexpected output: got %v want %v", violations, []string{"common_password"})
+	}
+}
+
+func TestValidatePasswordMinLengthFromEnv(t *testing.T) {
+	t.Setenv("PASSWORD_MIN_LENGTH", "16")
+
+	violations := models.CurrentPasswordPolicy().PasswordViolations("Retro-Board-42", "", "")
+	if len(violations) != 1 || violations[0] != "min_length=16" {
+		t.Errorf("returned unexpected output: got %v want %v", violations, []string{"min_length=16"})
+	}
+}
+
+func TestValidateBoardColumnKey(t *testing.T) {
+	tests := map[models.ColumnType]bool{
+		"went_well":  true,
+		"risks2":     true,
+		"Went_Well":  false,
+		"went well":  false,
+		"_went_well": false,
+		"went__well": false,
+		"went_well_": false,
+		"très_bien":  false,
+		"a_very_long_column_key_that_keeps_going_past_fifty_characters": false,
+	}
+
+	for key, valid := range tests {
+		err := models.ValidateStruct(&models.CreateBoardColumnRequest{Key: key, Title: "test_title"})
+		if (err == nil) != valid {
+			t.Errorf("returned unexpected output for %v: got %v want valid %v", key, err, valid)
+		}
+	}
+}
+
+func TestValidateBoardStructWithDuplicateColumns(t *testing.T) {
+	createBoardRequest := &models.CreateBoardRequest{
+		Name:   "test_board_name",
+		TeamId: "992c4b2b-f83f-45bc-a258-8b1074fb7a8e",
+		Columns: []models.CreateBoardColumnRequest{
+			{Title: "Went well"},
+			{Key: models.WentWell, Title: "Wins"},
+		},
+	}
+
+	err := models.ValidateStruct(createBoardRequest)
+	if err == nil {
+		t.Errorf("returned unexpected output: got %v want %v", err, "error")
+	}
+
+	createBoardRequest.Columns[1].Key = "wins"
+	err = models.ValidateStruct(createBoardRequest)
+	if err != nil {
+		t.Errorf("returned unexpected output: got %v want %v", err, nil)
+	}
+}
+
+func TestValidateUpdateBoardColumnStruct(t *testing.T) {
+	empty, invalid := "", "orange"
+
+	err := models.ValidateStruct(&models.UpdateBoardColumnRequest{Color: &empty})
+	if err != nil {
+		t.Errorf("returned unexpected output: got %v want %v", err, nil)
+	}
+
+	err = models.ValidateStruct(&models.UpdateBoardColumnRequest{Color: &invalid})
+	if err == nil {
+		t.Errorf("returned unexpected output: got %v want %v", err, "error")
+	}
+}
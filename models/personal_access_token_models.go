@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Personal access tokens start with this prefix so they are easy to spot in
+// logs and secret scanners.
+const PERSONAL_ACCESS_TOKEN_PREFIX = "rtp_"
+
+type PersonalAccessToken struct {
+	Id         uuid.UUID  `json:"id"`
+	UserId     uuid.UUID  `json:"user_id"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
+}
+
+// CreatePersonalAccessTokenResponse is the only response that carries the
+// token value, it can not be read back afterwards.
+type CreatePersonalAccessTokenResponse struct {
+	*PersonalAccessToken
+	Token string `json:"token"`
+}
+
+func NewPersonalAccessToken(userId uuid.UUID, tokenHash string, request *CreatePersonalAccessTokenRequest) *PersonalAccessToken {
+	token := &PersonalAccessToken{
+		Id:        uuid.New(),
+		UserId:    userId,
+		Name:      request.Name,
+		TokenHash: tokenHash,
+		Scopes:    request.Scopes,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if request.ExpiresInDays > 0 {
+		expiresAt := token.CreatedAt.Add(time.Duration(request.ExpiresInDays) * 24 * time.Hour)
+		token.ExpiresAt = &expiresAt
+	}
+
+	return token
+}
+
+func (t *PersonalAccessToken) IsValid() bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+
+	return t.ExpiresAt == nil || time.Now().UTC().Before(*t.ExpiresAt)
+}
+
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	for _, allowed := range t.Scopes {
+		if allowed == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserSession struct {
+	Id         uuid.UUID  `json:"id"`
+	UserId     uuid.UUID  `json:"user_id"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	Current    bool       `json:"current"`
+}
+
+const USER_AGENT_MAX_LENGTH = 255
+
+func NewUserSession(userId uuid.UUID, userAgent, ipAddress string, lifetime time.Duration) *UserSession {
+	if len(userAgent) > USER_AGENT_MAX_LENGTH {
+		userAgent = userAgent[:USER_AGENT_MAX_LENGTH]
+	}
+
+	now := time.Now().UTC()
+	return &UserSession{
+		Id:         uuid.New(),
+		UserId:     userId,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(lifetime),
+	}
+}
+
+func (s *UserSession) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().UTC().Before(s.ExpiresAt)
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TeamRole string
+
+const (
+	TeamRoleOwner  TeamRole = "owner"
+	TeamRoleAdmin  TeamRole = "admin"
+	TeamRoleMember TeamRole = "member"
+)
+
+var ValidTeamRole = []TeamRole{TeamRoleOwner, TeamRoleAdmin, TeamRoleMember}
+
+// DEFAULT_TEAM_ID is the team the migration moved the boards created before
+// teams existed into.
+const DEFAULT_TEAM_ID = "00000000-0000-0000-0000-000000000001"
+
+type Team struct {
+	Id          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedById uuid.UUID `json:"created_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	ModifiedAt  time.Time `json:"modified_at"`
+}
+
+type TeamMembership struct {
+	TeamId    uuid.UUID           `json:"team_id"`
+	UserId    uuid.UUID           `json:"user_id"`
+	User      *CreateUserResponse `json:"user"`
+	Role      TeamRole            `json:"role"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+type CreateTeamRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+type UpdateTeamRequest struct {
+	Name        string `json:"name" validate:"omitempty,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+type AddTeamMemberRequest struct {
+	UserId string   `json:"user_id" validate:"required"`
+	Role   TeamRole `json:"role" validate:"omitempty,oneof=owner admin member"`
+}
+
+type UpdateTeamMemberRequest struct {
+	Role TeamRole `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+func NewTeam(request *CreateTeamRequest, createdById uuid.UUID) *Team {
+	return &Team{
+		Id:          uuid.New(),
+		Name:        request.Name,
+		Description: request.Description,
+		CreatedById: createdById,
+		CreatedAt:   time.Now().UTC(),
+		ModifiedAt:  time.Now().UTC(),
+	}
+}
+
+func UpdateTeam(team *Team, request *UpdateTeamRequest) *Team {
+	if request.Name != "" {
+		team.Name = request.Name
+	}
+	team.Description = request.Description
+	team.ModifiedAt = time.Now().UTC()
+
+	return team
+}
+
+func NewTeamMembership(teamId, userId uuid.UUID, role TeamRole) *TeamMembership {
+	if role == "" {
+		role = TeamRoleMember
+	}
+
+	return &TeamMembership{
+		TeamId:    teamId,
+		UserId:    userId,
+		Role:      role,
+		CreatedAt: time.Now().UTC(),
+	}
+}
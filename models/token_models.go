@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const PASSWORD_RESET_TOKEN_LIFETIME = time.Hour
+
+type PasswordResetToken struct {
+	Id        uuid.UUID  `json:"id"`
+	UserId    uuid.UUID  `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func NewPasswordResetToken(userId uuid.UUID, tokenHash string) *PasswordResetToken {
+	return &PasswordResetToken{
+		Id:        uuid.New(),
+		UserId:    userId,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().UTC().Add(PASSWORD_RESET_TOKEN_LIFETIME),
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func (t *PasswordResetToken) IsValid() bool {
+	return t.UsedAt == nil && time.Now().UTC().Before(t.ExpiresAt)
+}
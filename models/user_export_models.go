@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// UserDataExport is the archive of everything stored about a user, returned
+// by the data export endpoint.
+type UserDataExport struct {
+	ExportedAt           time.Time              `json:"exported_at"`
+	Profile              *CreateUserResponse    `json:"profile"`
+	Teams                []*Team                `json:"teams"`
+	Boards               []*Board               `json:"boards"`
+	Feedbacks            []*Feedback            `json:"feedbacks"`
+	Sessions             []*UserSession         `json:"sessions"`
+	PersonalAccessTokens []*PersonalAccessToken `json:"personal_access_tokens"`
+}
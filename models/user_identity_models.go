@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to the subject of an OpenID Connect provider,
+// the subject stays the same when the email at the provider changes.
+type UserIdentity struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	UserId    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewUserIdentity(provider, subject string, userId uuid.UUID) *UserIdentity {
+	return &UserIdentity{
+		Provider:  provider,
+		Subject:   subject,
+		UserId:    userId,
+		CreatedAt: time.Now().UTC(),
+	}
+}
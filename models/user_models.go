@@ -2,8 +2,10 @@ package models
 
 import (
 	"log"
+	"strings"
 	"time"
 
+	"github.com/Aakash-Pandit/reetro-golang/common"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -18,36 +20,57 @@ const (
 
 var ValidUserType = []Role{GuestUser, TeamMember, SuperAdmin}
 
+// DELETED_USER_NAME is shown as the author of boards and feedback whose
+// account was deleted.
+const DELETED_USER_NAME = "Deleted user"
+
 type User struct {
-	Id         uuid.UUID `json:"id"`
-	FirstName  string    `json:"first_name"`
-	LastName   string    `json:"last_name"`
-	Username   string    `json:"username"`
-	Password   string    `json:"password"`
-	Email      string    `json:"email"`
-	UserType   Role      `json:"user_type"`
-	CreatedAt  time.Time `json:"created_at"`
-	ModifiedAt time.Time `json:"modified_at"`
+	Id            uuid.UUID `json:"id"`
+	FirstName     string    `json:"first_name"`
+	LastName      string    `json:"last_name"`
+	Username      string    `json:"username"`
+	Password      string    `json:"password"`
+	Email         string    `json:"email"`
+	UserType      Role      `json:"user_type"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+	ModifiedAt    time.Time `json:"modified_at"`
 }
 
+// CreateUserRequest is what anyone signing up sends, the role is not part
+// of it. Signed up users are always guest users.
 type CreateUserRequest struct {
 	FirstName string `json:"first_name" validate:"required"`
 	LastName  string `json:"last_name" validate:"required"`
 	Username  string `json:"username" validate:"required"`
-	Password  string `json:"password" validate:"required"`
+	Password  string `json:"password" validate:"required,password"`
 	Email     string `json:"email" validate:"required,email"`
-	UserType  Role   `json:"user_type"`
+}
+
+// AdminCreateUserRequest is how an admin creates a user with a role.
+type AdminCreateUserRequest struct {
+	CreateUserRequest
+	UserType Role `json:"user_type" validate:"required,oneof=guest_user team_member super_admin"`
 }
 
 type CreateUserResponse struct {
-	Id         uuid.UUID `json:"id"`
-	FirstName  string    `json:"first_name"`
-	LastName   string    `json:"last_name"`
-	Username   string    `json:"username"`
-	Email      string    `json:"email"`
-	UserType   Role      `json:"user_type"`
-	CreatedAt  time.Time `json:"created_at"`
-	ModifiedAt time.Time `json:"modified_at"`
+	Id            uuid.UUID `json:"id"`
+	FirstName     string    `json:"first_name"`
+	LastName      string    `json:"last_name"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email"`
+	UserType      Role      `json:"user_type"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+	ModifiedAt    time.Time `json:"modified_at"`
+}
+
+type UpdateUserRequest struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Username  string `json:"username"`
+	Email     string `json:"email" validate:"omitempty,email"`
+	UserType  Role   `json:"user_type" validate:"omitempty,oneof=guest_user team_member super_admin"`
 }
 
 type LoginRequest struct {
@@ -55,7 +78,7 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
-func NewUser(userRequest *CreateUserRequest) (*User, error) {
+func NewUser(userRequest *CreateUserRequest, role Role) (*User, error) {
 	password, err := bcrypt.GenerateFromPassword([]byte(userRequest.Password), bcrypt.DefaultCost)
 	if err != nil {
 		log.Println("Error while hashing password: ", err)
@@ -69,8 +92,65 @@ func NewUser(userRequest *CreateUserRequest) (*User, error) {
 		Username:   userRequest.Username,
 		Password:   string(password),
 		Email:      userRequest.Email,
-		UserType:   userRequest.UserType,
+		UserType:   role,
 		CreatedAt:  time.Now().UTC(),
 		ModifiedAt: time.Now().UTC(),
 	}, nil
 }
+
+func UpdateUser(user *CreateUserResponse, userRequest *UpdateUserRequest) *CreateUserResponse {
+	if userRequest.FirstName != "" {
+		user.FirstName = userRequest.FirstName
+	}
+	if userRequest.LastName != "" {
+		user.LastName = userRequest.LastName
+	}
+	if userRequest.Username != "" {
+		user.Username = userRequest.Username
+	}
+	if userRequest.Email != "" && userRequest.Email != user.Email {
+		user.Email = userRequest.Email
+		user.EmailVerified = false
+	}
+	if userRequest.UserType != "" {
+		user.UserType = userRequest.UserType
+	}
+	user.ModifiedAt = time.Now().UTC()
+
+	return user
+}
+
+// DeletedUser is the placeholder author returned in place of an account that
+// no longer exists, it carries no personal data.
+func DeletedUser() *CreateUserResponse {
+	return &CreateUserResponse{FirstName: DELETED_USER_NAME}
+}
+
+// NewExternalUser builds a user that logs in through an identity provider.
+// The account gets a random password nobody knows, so it can only log in
+// through the provider until the user resets it. The provider already
+// verified the email address.
+func NewExternalUser(email, firstName, lastName string, role Role) (*User, error) {
+	if firstName == "" {
+		firstName = strings.Split(email, "@")[0]
+	}
+
+	randomPassword, err := common.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := NewUser(&CreateUserRequest{
+		FirstName: firstName,
+		LastName:  lastName,
+		Username:  email,
+		Password:  randomPassword,
+		Email:     email,
+	}, role)
+	if err != nil {
+		return nil, err
+	}
+
+	user.EmailVerified = true
+	return user, nil
+}
@@ -1,25 +1,193 @@
 package models
 
 import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/Aakash-Pandit/reetro-golang/config"
 	"github.com/go-playground/validator"
 )
 
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+var commonPasswords = loadCommonPasswords(commonPasswordList)
+
+var validation = newValidator()
+
+// KEY_MAX_LEN is the longest column or template key.
+const KEY_MAX_LEN = 50
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
 type ErrorResponse struct {
 	FailedField string
 	Tag         string
 	Value       string
 }
 
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// bcrypt refuses anything longer, so the policy never allows more.
+const PASSWORD_MAX_LENGTH = 72
+
+func CurrentPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:     config.EnvInt("PASSWORD_MIN_LENGTH", 8),
+		MaxLength:     PASSWORD_MAX_LENGTH,
+		RequireUpper:  config.EnvBool("PASSWORD_REQUIRE_UPPERCASE", true),
+		RequireLower:  config.EnvBool("PASSWORD_REQUIRE_LOWERCASE", true),
+		RequireDigit:  config.EnvBool("PASSWORD_REQUIRE_DIGIT", true),
+		RequireSymbol: config.EnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+	}
+}
+
+func loadCommonPasswords(list string) map[string]bool {
+	passwords := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		if password := strings.TrimSpace(scanner.Text()); password != "" {
+			passwords[strings.ToLower(password)] = true
+		}
+	}
+	return passwords
+}
+
+// PasswordViolations returns the name of every rule of the policy the
+// password breaks, an empty slice means the password is accepted.
+func (p PasswordPolicy) PasswordViolations(password, username, email string) []string {
+	violations := []string{}
+
+	if len(password) < p.MinLength {
+		violations = append(violations, fmt.Sprintf("min_length=%d", p.MinLength))
+	}
+
+	if len(password) > p.MaxLength {
+		violations = append(violations, fmt.Sprintf("max_length=%d", p.MaxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsDigit(char):
+			hasDigit = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char) || unicode.IsSpace(char):
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, "uppercase")
+	}
+	if p.RequireLower && !hasLower {
+		violations = append(violations, "lowercase")
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, "digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, "symbol")
+	}
+
+	lowered := strings.ToLower(password)
+	if username != "" && lowered == strings.ToLower(username) {
+		violations = append(violations, "not_username")
+	}
+
+	if email != "" && (lowered == strings.ToLower(email) || lowered == strings.ToLower(strings.Split(email, "@")[0])) {
+		violations = append(violations, "not_email")
+	}
+
+	if commonPasswords[lowered] {
+		violations = append(violations, "common_password")
+	}
+
+	return violations
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterValidation("password", validatePassword)
+	v.RegisterValidation("column_key", validateKey)
+	v.RegisterValidation("template_key", validateKey)
+	return v
+}
+
+// validateKey accepts lower case words joined by underscores, column keys are
+// stored on feedback and template keys on boards so they stay short and
+// readable.
+func validateKey(fl validator.FieldLevel) bool {
+	key := fl.Field().String()
+	return len(key) <= KEY_MAX_LEN && keyPattern.MatchString(key)
+}
+
+func validatePassword(fl validator.FieldLevel) bool {
+	username, email := passwordContext(fl.Parent())
+	return len(CurrentPasswordPolicy().PasswordViolations(fl.Field().String(), username, email)) == 0
+}
+
+// passwordContext reads the Username and Email fields of the struct holding
+// the password, when it has them, so the password can be compared to them.
+func passwordContext(value reflect.Value) (string, string) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", ""
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return "", ""
+	}
+
+	var username, email string
+	if field := value.FieldByName("Username"); field.IsValid() && field.Kind() == reflect.String {
+		username = field.String()
+	}
+	if field := value.FieldByName("Email"); field.IsValid() && field.Kind() == reflect.String {
+		email = field.String()
+	}
+
+	return username, email
+}
+
 func SetDefaultValue(s interface{}) {
 	switch model := s.(type) {
-	case *CreateUserRequest:
-		if model.UserType == "" {
-			model.UserType = GuestUser
-		}
 	case *CreateBoardRequest:
 		if model.Template == "" {
 			model.Template = Agile
 		}
+		for i := range model.Columns {
+			SetDefaultValue(&model.Columns[i])
+		}
+	case *CreateBoardTemplateRequest:
+		for i := range model.Columns {
+			SetDefaultValue(&model.Columns[i])
+		}
+	case *UpdateBoardTemplateRequest:
+		for i := range model.Columns {
+			SetDefaultValue(&model.Columns[i])
+		}
+	case *CreateBoardColumnRequest:
+		if model.Key == "" {
+			model.Key = ColumnKeyFromTitle(model.Title)
+		}
 	}
 }
 
@@ -28,10 +196,14 @@ func ValidateStruct(s interface{}) []*ErrorResponse {
 
 	SetDefaultValue(s)
 
-	validation := validator.New()
 	err := validation.Struct(s)
 	if err != nil {
 		for _, err := range err.(validator.ValidationErrors) {
+			if err.Tag() == "password" {
+				errors = append(errors, passwordErrors(s, err)...)
+				continue
+			}
+
 			var element ErrorResponse
 			element.FailedField = err.StructNamespace()
 			element.Tag = err.Tag()
@@ -41,3 +213,29 @@ func ValidateStruct(s interface{}) []*ErrorResponse {
 	}
 	return errors
 }
+
+// passwordErrors turns a failed password tag into one entry per broken rule,
+// so the client can tell the user exactly what to change.
+func passwordErrors(s interface{}, err validator.FieldError) []*ErrorResponse {
+	password, _ := err.Value().(string)
+	username, email := passwordContext(reflect.ValueOf(s))
+
+	return PasswordErrors(err.StructNamespace(), password, username, email)
+}
+
+// PasswordErrors checks the password of the field against the policy for the
+// user, for payloads that do not carry the username and email themselves.
+// It returns nil when the password is accepted.
+func PasswordErrors(field, password, username, email string) []*ErrorResponse {
+	var errors []*ErrorResponse
+
+	for _, violation := range CurrentPasswordPolicy().PasswordViolations(password, username, email) {
+		errors = append(errors, &ErrorResponse{
+			FailedField: field,
+			Tag:         "password",
+			Value:       violation,
+		})
+	}
+
+	return errors
+}
@@ -17,40 +17,46 @@ type Router struct {
 	Port            string
 	BasicService    services.BasicService
 	UserService     services.UserService
+	TeamService     services.TeamService
 	BoardService    services.BoardService
 	FeedbackService services.FeedbackService
+	AuditService    services.AuditService
 	Middleware      middlewares.Middleware
 }
 
 func NewRouter(route *mux.Router, port string, db *storages.PostgresStore, client *storages.RedisStore, emailInterface common.EmailInterface) *Router {
-	requestUser := services.RequestUser{
-		Store: services.ServiceStorage(db),
-	}
-
 	return &Router{
 		Route: route,
 		Port:  port,
 		BasicService: services.BasicService{
+			Store:       storages.Storage(db),
 			RedisClient: client,
 		},
 		UserService: services.UserService{
-			Store:       storages.Storage(db),
-			User:        requestUser,
-			RedisClient: client,
-			Email:       emailInterface,
+			Store:         storages.Storage(db),
+			RedisClient:   client,
+			Email:         emailInterface,
+			GoogleAuth:    middlewares.NewGoogleAuth(),
+			OIDCProviders: middlewares.LoadOIDCProviders(),
+		},
+		TeamService: services.TeamService{
+			Store: storages.Storage(db),
 		},
 		BoardService: services.BoardService{
 			Store:       storages.Storage(db),
-			User:        requestUser,
 			RedisClient: client,
+			Email:       emailInterface,
 		},
 		FeedbackService: services.FeedbackService{
 			Store:       storages.Storage(db),
-			User:        requestUser,
 			RedisClient: client,
 		},
+		AuditService: services.AuditService{
+			Store: storages.Storage(db),
+		},
 		Middleware: middlewares.Middleware{
-			Store: middlewares.MiddlewareInterface(db),
+			Store:       middlewares.MiddlewareInterface(db),
+			RedisClient: client,
 		},
 	}
 }
@@ -70,10 +76,19 @@ func (r *Router) Run() {
 		),
 	).Methods(http.MethodGet)
 
+	r.Route.HandleFunc(
+		"/.well-known/jwks.json",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(services.JWKSHandler),
+		),
+	).Methods(http.MethodGet)
+
 	r.Route.HandleFunc(
 		"/clear_redis/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.BasicService.ClearRedisCache),
+			r.Middleware.RequirePermission(middlewares.ClearCache),
+			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodPost)
 
@@ -84,10 +99,80 @@ func (r *Router) Run() {
 		),
 	).Methods(http.MethodPost)
 
+	r.Route.HandleFunc(
+		"/login/mfa/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.LoginMFAHandler),
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/login/google/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.GoogleLoginHandler),
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/login/oidc/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.GetOIDCProvidersHandler),
+		),
+	).Methods(http.MethodGet)
+
+	r.Route.HandleFunc(
+		"/login/oidc/{provider}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.OIDCLoginHandler),
+		),
+	).Methods(http.MethodGet)
+
+	r.Route.HandleFunc(
+		"/login/oidc/{provider}/callback/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.OIDCCallbackHandler),
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/token/refresh/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.RefreshTokenHandler),
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/logout/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.LogoutHandler),
+			r.Middleware.RequirePermission(middlewares.ManageOwnAccount),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
 	r.Route.HandleFunc(
 		"/users/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.UserService.GetAllUsersHandler),
+			r.Middleware.RequirePermission(middlewares.ListUsers),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodGet)
+
+	r.Route.HandleFunc(
+		"/users/me/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.DeleteOwnAccountHandler),
+			r.Middleware.RequirePermission(middlewares.ManageOwnAccount),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
+	r.Route.HandleFunc(
+		"/users/me/export/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.ExportUserDataHandler),
+			r.Middleware.RequirePermission(middlewares.ManageOwnAccount),
 			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodGet)
@@ -96,6 +181,7 @@ func (r *Router) Run() {
 		"/users/{id}/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.UserService.GetUserByIdHandler),
+			r.Middleware.RequirePermission(middlewares.ViewUsers),
 			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodGet)
@@ -103,7 +189,9 @@ func (r *Router) Run() {
 	r.Route.HandleFunc(
 		"/users/",
 		middlewares.ChainOfMiddleware(
-			core.HTTPHandleFunc(r.UserService.CreateUserHandler),
+			core.HTTPHandleFunc(r.UserService.CreateUserAsAdminHandler),
+			r.Middleware.RequirePermission(middlewares.ManageUserRoles),
+			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodPost)
 
@@ -114,14 +202,128 @@ func (r *Router) Run() {
 		),
 	).Methods(http.MethodPost)
 
+	r.Route.HandleFunc(
+		"/users/{id}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.UpdateUserHandler),
+			r.Middleware.RequirePermission(middlewares.ManageOwnAccount),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPatch)
+
 	r.Route.HandleFunc(
 		"/users/{id}/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.UserService.DeleteUserHandler),
+			r.Middleware.RequirePermission(middlewares.DeleteUsers),
 			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodDelete)
 
+	r.Route.HandleFunc(
+		"/users/{id}/unlock/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.UnlockUserHandler),
+			r.Middleware.RequirePermission(middlewares.UnlockUsers),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/users/me/tokens/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.GetPersonalAccessTokensHandler),
+			r.Middleware.RequirePermission(middlewares.ManageOwnAccount),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodGet)
+
+	r.Route.HandleFunc(
+		"/users/me/tokens/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.CreatePersonalAccessTokenHandler),
+			r.Middleware.RequirePermission(middlewares.ManageOwnAccount),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/users/me/tokens/{id}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.RevokePersonalAccessTokenHandler),
+			r.Middleware.RequirePermission(middlewares.ManageOwnAccount),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
+	r.Route.HandleFunc(
+		"/users/me/sessions/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.GetSessionsHandler),
+			r.Middleware.RequirePermission(middlewares.ManageOwnAccount),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodGet)
+
+	r.Route.HandleFunc(
+		"/users/me/sessions/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.RevokeAllSessionsHandler),
+			r.Middleware.RequirePermission(middlewares.ManageOwnAccount),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
+	r.Route.HandleFunc(
+		"/users/me/sessions/{id}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.RevokeSessionHandler),
+			r.Middleware.RequirePermission(middlewares.ManageOwnAccount),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
+	r.Route.HandleFunc(
+		"/users/me/mfa/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.EnrollMFAHandler),
+			r.Middleware.RequirePermission(middlewares.ManageOwnAccount),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/users/me/mfa/confirm/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.ConfirmMFAHandler),
+			r.Middleware.RequirePermission(middlewares.ManageOwnAccount),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/users/{id}/mfa/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.ResetUserMFAHandler),
+			r.Middleware.RequirePermission(middlewares.ResetUserMFA),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
+	r.Route.HandleFunc(
+		"/verify_email/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.VerifyEmailHandler),
+		),
+	).Methods(http.MethodGet)
+
+	r.Route.HandleFunc(
+		"/verify_email/resend/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.ResendVerificationEmailHandler),
+		),
+	).Methods(http.MethodPost)
+
 	r.Route.HandleFunc(
 		"/forgot_password/",
 		middlewares.ChainOfMiddleware(
@@ -136,10 +338,135 @@ func (r *Router) Run() {
 		),
 	).Methods(http.MethodPost)
 
+	r.Route.HandleFunc(
+		"/reset_password/confirm/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.UserService.ResetPasswordConfirmHandler),
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/teams/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.TeamService.GetAllTeamsHandler),
+			r.Middleware.RequirePermission(middlewares.ViewTeams),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodGet)
+
+	r.Route.HandleFunc(
+		"/teams/{id}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.TeamService.GetTeamByIdHandler),
+			r.Middleware.RequirePermission(middlewares.ViewTeams),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodGet)
+
+	r.Route.HandleFunc(
+		"/teams/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.TeamService.CreateTeamHandler),
+			r.Middleware.RequirePermission(middlewares.CreateTeams),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/teams/{id}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.TeamService.UpdateTeamHandler),
+			r.Middleware.RequirePermission(middlewares.ViewTeams),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPatch)
+
+	r.Route.HandleFunc(
+		"/teams/{id}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.TeamService.DeleteTeamHandler),
+			r.Middleware.RequirePermission(middlewares.ViewTeams),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
+	r.Route.HandleFunc(
+		"/teams/{id}/members/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.TeamService.GetTeamMembersHandler),
+			r.Middleware.RequirePermission(middlewares.ViewTeams),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodGet)
+
+	r.Route.HandleFunc(
+		"/teams/{id}/members/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.TeamService.AddTeamMemberHandler),
+			r.Middleware.RequirePermission(middlewares.ViewTeams),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/teams/{id}/members/{user_id}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.TeamService.UpdateTeamMemberHandler),
+			r.Middleware.RequirePermission(middlewares.ViewTeams),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPatch)
+
+	r.Route.HandleFunc(
+		"/teams/{id}/members/{user_id}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.TeamService.RemoveTeamMemberHandler),
+			r.Middleware.RequirePermission(middlewares.ViewTeams),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
+	r.Route.HandleFunc(
+		"/teams/{id}/templates/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.CreateBoardTemplateHandler),
+			r.Middleware.RequirePermission(middlewares.ViewTeams),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/teams/{id}/templates/{key}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.UpdateBoardTemplateHandler),
+			r.Middleware.RequirePermission(middlewares.ViewTeams),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPut)
+
+	r.Route.HandleFunc(
+		"/teams/{id}/templates/{key}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.DeleteBoardTemplateHandler),
+			r.Middleware.RequirePermission(middlewares.ViewTeams),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
+	r.Route.HandleFunc(
+		"/templates/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.GetBoardTemplatesHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodGet)
+
 	r.Route.HandleFunc(
 		"/boards/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.BoardService.GetAllBoardsHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
 			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodGet)
@@ -148,6 +475,7 @@ func (r *Router) Run() {
 		"/boards/{id}/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.BoardService.GetBoardByIdHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
 			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodGet)
@@ -156,6 +484,7 @@ func (r *Router) Run() {
 		"/boards/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.BoardService.CreateBoardHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
 			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodPost)
@@ -164,6 +493,7 @@ func (r *Router) Run() {
 		"/boards/{id}/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.BoardService.UpdateBoardHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
 			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodPatch)
@@ -172,14 +502,167 @@ func (r *Router) Run() {
 		"/boards/{id}/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.BoardService.DeleteBoardHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/members/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.GetBoardMembersHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodGet)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/members/{user_id}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.RemoveBoardMemberHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
 			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodDelete)
 
+	r.Route.HandleFunc(
+		"/boards/{id}/invitations/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.CreateBoardInvitationHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/boards/invitations/accept/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.AcceptBoardInvitationHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/join_links/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.CreateBoardJoinLinkHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/join_links/{link_id}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.RevokeBoardJoinLinkHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/close/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.CloseBoardHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/clone/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.CloneBoardHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/series/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.GetBoardSeriesHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodGet)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/phase/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.ChangeBoardPhaseHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/columns/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.GetBoardColumnsHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodGet)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/columns/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.CreateBoardColumnHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/columns/order/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.ReorderBoardColumnsHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPut)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/columns/{column_id}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.UpdateBoardColumnHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPatch)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/columns/{column_id}/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.RemoveBoardColumnHandler),
+			r.Middleware.RequirePermission(middlewares.ViewBoards),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
+	r.Route.HandleFunc(
+		"/join/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.BoardService.JoinBoardHandler),
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/boards/{id}/feedback_removals/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.FeedbackService.GetFeedbackRemovalsHandler),
+			r.Middleware.RequirePermission(middlewares.ViewFeedbacks),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodGet)
+
 	r.Route.HandleFunc(
 		"/feedbacks/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.FeedbackService.GetAllFeedbacksHandler),
+			r.Middleware.RequirePermission(middlewares.ViewFeedbacks),
 			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodGet)
@@ -188,6 +671,7 @@ func (r *Router) Run() {
 		"/feedbacks/{id}/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.FeedbackService.GetFeedbackByIdHandler),
+			r.Middleware.RequirePermission(middlewares.ViewFeedbacks),
 			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodGet)
@@ -196,6 +680,7 @@ func (r *Router) Run() {
 		"/feedbacks/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.FeedbackService.CreateFeedbackHandler),
+			r.Middleware.RequirePermission(middlewares.CreateFeedbacks),
 			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodPost)
@@ -204,18 +689,101 @@ func (r *Router) Run() {
 		"/feedbacks/{id}/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.FeedbackService.UpdateFeedbackHandler),
+			r.Middleware.RequirePermission(middlewares.UpdateFeedbacks),
 			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodPatch)
 
+	r.Route.HandleFunc(
+		"/feedbacks/{id}/move/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.FeedbackService.MoveFeedbackHandler),
+			r.Middleware.RequirePermission(middlewares.UpdateFeedbacks),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/feedbacks/{id}/votes/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.FeedbackService.VoteFeedbackHandler),
+			r.Middleware.RequirePermission(middlewares.CreateFeedbacks),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/feedbacks/{id}/votes/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.FeedbackService.UnvoteFeedbackHandler),
+			r.Middleware.RequirePermission(middlewares.CreateFeedbacks),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
+	r.Route.HandleFunc(
+		"/feedbacks/{id}/group/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.FeedbackService.GroupFeedbackHandler),
+			r.Middleware.RequirePermission(middlewares.UpdateFeedbacks),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPut)
+
+	r.Route.HandleFunc(
+		"/feedbacks/{id}/group/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.FeedbackService.UngroupFeedbackHandler),
+			r.Middleware.RequirePermission(middlewares.UpdateFeedbacks),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
+	r.Route.HandleFunc(
+		"/feedbacks/{id}/resolve/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.FeedbackService.ResolveFeedbackHandler),
+			r.Middleware.RequirePermission(middlewares.UpdateFeedbacks),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodPost)
+
+	r.Route.HandleFunc(
+		"/feedbacks/{id}/resolve/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.FeedbackService.ReopenFeedbackHandler),
+			r.Middleware.RequirePermission(middlewares.UpdateFeedbacks),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodDelete)
+
 	r.Route.HandleFunc(
 		"/feedbacks/{id}/",
 		middlewares.ChainOfMiddleware(
 			core.HTTPHandleFunc(r.FeedbackService.DeleteFeedbackHandler),
+			r.Middleware.RequirePermission(middlewares.DeleteFeedbacks),
 			r.Middleware.JWTAuthentication,
 		),
 	).Methods(http.MethodDelete)
 
+	r.Route.HandleFunc(
+		"/audit/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.AuditService.GetAuditEventsHandler),
+			r.Middleware.RequirePermission(middlewares.ViewAuditEvents),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodGet)
+
+	r.Route.HandleFunc(
+		"/audit/export/",
+		middlewares.ChainOfMiddleware(
+			core.HTTPHandleFunc(r.AuditService.ExportAuditEventsHandler),
+			r.Middleware.RequirePermission(middlewares.ViewAuditEvents),
+			r.Middleware.JWTAuthentication,
+		),
+	).Methods(http.MethodGet)
+
 	http.Handle("/", r.Route)
 	log.Fatal(http.ListenAndServe(r.Port, nil))
 }
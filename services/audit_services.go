@@ -0,0 +1,205 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	"github.com/google/uuid"
+)
+
+const AUDIT_EXPORT_BATCH_SIZE = 500
+
+var auditExportHeader = []string{"id", "created_at", "actor_id", "action", "target_type", "target_id", "ip_address", "before", "after"}
+
+type AuditService struct {
+	Store storages.Storage
+}
+
+func NewAuditService(store storages.Storage) *AuditService {
+	return &AuditService{Store: store}
+}
+
+// recordAuditEvent stores who did what to which target from where. Failing
+// to record does not fail the request, the action already happened.
+func recordAuditEvent(r *http.Request, store storages.Storage, action models.AuditAction, targetType models.AuditTargetType, targetId string, before, after any) {
+	var actorId *uuid.UUID
+	if user, ok := middlewares.RequestUserFromContext(r.Context()); ok {
+		actorId = &user.Id
+	}
+
+	event, err := models.NewAuditEvent(actorId, action, targetType, targetId, before, after, core.ClientIP(r))
+	if err != nil {
+		log.Println("Error while building the audit event", action, err)
+		return
+	}
+
+	if err := store.CreateAuditEvent(*event); err != nil {
+		log.Println("Error while recording the audit event", action, err)
+	}
+}
+
+// auditEventFilter reads the filter from the actor_id, action, target_type,
+// target_id, from and to query parameters, times are in RFC 3339 format.
+func auditEventFilter(r *http.Request) (models.AuditEventFilter, error) {
+	query := r.URL.Query()
+	filter := models.AuditEventFilter{
+		Action:     models.AuditAction(query.Get("action")),
+		TargetType: models.AuditTargetType(query.Get("target_type")),
+		TargetId:   query.Get("target_id"),
+	}
+
+	if value := query.Get("actor_id"); value != "" {
+		actorId, err := uuid.Parse(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid actor_id: %w", err)
+		}
+		filter.ActorId = &actorId
+	}
+
+	for name, field := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s, expected RFC 3339 time: %w", name, err)
+		}
+
+		parsed = parsed.UTC()
+		*field = &parsed
+	}
+
+	return filter, nil
+}
+
+func (a *AuditService) GetAuditEventsHandler(w http.ResponseWriter, r *http.Request) error {
+	filter, err := auditEventFilter(r)
+	if err != nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	limit, offset := core.Pagination(r)
+
+	events, err := a.Store.GetAuditEvents(filter, limit, offset)
+	if err != nil {
+		log.Println("Error in fetching the audit events", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to fetch audit events"},
+		})
+	}
+
+	return core.ListAPIResponse(w, &core.ListAPI{
+		Status: http.StatusOK,
+		Result: &core.ListAPIResponseBody{
+			Count:  len(events),
+			Result: events,
+		},
+	})
+}
+
+// ExportAuditEventsHandler streams every event matching the filter as CSV,
+// reading them from the database in batches. The export stops at the time it
+// started, so events recorded meanwhile do not shift the batches.
+func (a *AuditService) ExportAuditEventsHandler(w http.ResponseWriter, r *http.Request) error {
+	filter, err := auditEventFilter(r)
+	if err != nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	if startedAt := time.Now().UTC(); filter.To == nil || filter.To.After(startedAt) {
+		filter.To = &startedAt
+	}
+
+	events, err := a.Store.GetAuditEvents(filter, AUDIT_EXPORT_BATCH_SIZE, 0)
+	if err != nil {
+		log.Println("Error in fetching the audit events", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to fetch audit events"},
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"audit_events_%s.csv\"", time.Now().UTC().Format("20060102T150405Z")))
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	writer.Write(auditExportHeader)
+
+	offset := 0
+	for {
+		for _, event := range events {
+			writer.Write(auditEventRecord(event))
+		}
+
+		if len(events) < AUDIT_EXPORT_BATCH_SIZE {
+			break
+		}
+
+		offset += AUDIT_EXPORT_BATCH_SIZE
+		events, err = a.Store.GetAuditEvents(filter, AUDIT_EXPORT_BATCH_SIZE, offset)
+		if err != nil {
+			// The status line is already sent, all that is left is to stop.
+			log.Println("Error in fetching the audit events for the export", err)
+			break
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Println("Error while writing the audit events export", err)
+	}
+
+	return nil
+}
+
+func auditEventRecord(event *models.AuditEvent) []string {
+	actorId := ""
+	if event.ActorId != nil {
+		actorId = event.ActorId.String()
+	}
+
+	record := []string{
+		event.Id.String(),
+		event.CreatedAt.Format(time.RFC3339Nano),
+		actorId,
+		string(event.Action),
+		string(event.TargetType),
+		event.TargetId,
+		event.IPAddress,
+		string(event.Before),
+		string(event.After),
+	}
+
+	for i, value := range record {
+		record[i] = csvSafe(value)
+	}
+
+	return record
+}
+
+// csvSafe keeps spreadsheets from evaluating values as formulas, the ip
+// address can come from a header the client controls.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
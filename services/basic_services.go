@@ -1,15 +1,13 @@
 package services
 
 import (
+	"log"
 	"net/http"
-	"strings"
 
 	"github.com/Aakash-Pandit/reetro-golang/core"
 	"github.com/Aakash-Pandit/reetro-golang/middlewares"
 	"github.com/Aakash-Pandit/reetro-golang/models"
 	"github.com/Aakash-Pandit/reetro-golang/storages"
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/google/uuid"
 )
 
 type AboutPageResponse struct {
@@ -24,58 +22,52 @@ type ForgotPasswordPayload struct {
 type ResetPasswordPayload struct {
 	Username    string `json:"username" validate:"required"`
 	OldPassword string `json:"old_password" validate:"required"`
-	NewPassword string `json:"new_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,password"`
 }
 
-type APISuccessResponse struct {
-	Detail string `json:"detail"`
+type ResendVerificationPayload struct {
+	Email string `json:"email" validate:"required,email"`
 }
 
-type ServiceStorage interface {
-	GetUserById(uuid.UUID) (*models.CreateUserResponse, error)
+type ResetPasswordConfirmPayload struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,password"`
 }
 
-type RequestUser struct {
-	Store ServiceStorage
+type GoogleLoginPayload struct {
+	IdToken string `json:"id_token" validate:"required"`
 }
 
-func NewRequestUser(store ServiceStorage) *RequestUser {
-	return &RequestUser{Store: store}
+type OIDCCallbackPayload struct {
+	Code  string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
 }
 
-func (u *RequestUser) GetRequestUser(r *http.Request) *models.CreateUserResponse {
-	tokenString := r.Header.Get("Authorization")
-
-	if tokenString == "" {
-		return nil
-	}
-
-	token_info := strings.Split(tokenString, " ")
-	if len(token_info) != 2 {
-		return nil
-	}
-
-	if token_info[0] != "Bearer" {
-		return nil
-	}
+type RefreshTokenPayload struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
 
-	token, err := middlewares.ValidateJWT(token_info[1])
-	if err != nil {
-		return nil
-	}
+type MFACodePayload struct {
+	Code string `json:"code" validate:"required"`
+}
 
-	claims := token.Claims.(jwt.MapClaims)
-	id, _ := uuid.Parse(claims["id"].(string))
-	user, err := u.Store.GetUserById(id)
+// MFALoginPayload takes either a code from the authenticator app or one of
+// the recovery codes in Code.
+type MFALoginPayload struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
 
-	if err != nil {
-		return nil
-	}
+type LogoutPayload struct {
+	RefreshToken string `json:"refresh_token"`
+}
 
-	return user
+type APISuccessResponse struct {
+	Detail string `json:"detail"`
 }
 
 type BasicService struct {
+	Store       storages.Storage
 	RedisClient storages.RedisStoreInterface
 }
 
@@ -86,6 +78,25 @@ func HomeHandler(w http.ResponseWriter, r *http.Request) error {
 	})
 }
 
+// JWKSHandler publishes the public keys tokens are signed with, so other
+// services can verify them without sharing a secret.
+func JWKSHandler(w http.ResponseWriter, r *http.Request) error {
+	keySet, err := middlewares.SigningKeys()
+	if err != nil {
+		log.Println("Error while loading the signing keys", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to load the signing keys"},
+		})
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   keySet.JWKS(),
+	})
+}
+
 func AboutHandler(w http.ResponseWriter, r *http.Request) error {
 	return core.APIResponse(w, &core.Response{
 		Status: http.StatusOK,
@@ -96,8 +107,15 @@ func AboutHandler(w http.ResponseWriter, r *http.Request) error {
 	})
 }
 
+// CACHE_KEY_PATTERN matches the keys of cached users, boards and feedback,
+// which are stored under their id. Revoked tokens, login states and lockouts
+// live under prefixed keys and are left alone.
+const CACHE_KEY_PATTERN = "????????-????-????-????-????????????"
+
+// ClearRedisCache drops the cached records only, flushing everything would
+// also forget which tokens were revoked.
 func (b *BasicService) ClearRedisCache(w http.ResponseWriter, r *http.Request) error {
-	err := b.RedisClient.FlushAll()
+	err := b.RedisClient.DelMatching(CACHE_KEY_PATTERN)
 	if err != nil {
 		return core.APIResponse(w, &core.Response{
 			Status: http.StatusInternalServerError,
@@ -105,6 +123,8 @@ func (b *BasicService) ClearRedisCache(w http.ResponseWriter, r *http.Request) e
 		})
 	}
 
+	recordAuditEvent(r, b.Store, models.AuditCacheCleared, models.AuditTargetCache, "", nil, nil)
+
 	return core.APIResponse(w, &core.Response{
 		Status: http.StatusOK,
 		Data:   APISuccessResponse{Detail: "Redis Cache Cleared Successfully"},
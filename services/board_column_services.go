@@ -0,0 +1,408 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+func boardColumnNotFound(w http.ResponseWriter) error {
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusNotFound,
+		Data:   &core.APIError{Detail: "Column not found"},
+	})
+}
+
+func boardColumnsFull(w http.ResponseWriter) error {
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusBadRequest,
+		Data:   &core.APIError{Detail: "A board can have at most " + strconv.Itoa(models.BOARD_COLUMNS_MAX) + " columns"},
+	})
+}
+
+func boardColumnExists(w http.ResponseWriter) error {
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusBadRequest,
+		Data:   &core.APIError{Detail: "A column with this key already exists on the board"},
+	})
+}
+
+func boardClosed(w http.ResponseWriter) error {
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusBadRequest,
+		Data:   &core.APIError{Detail: "This board is closed"},
+	})
+}
+
+// refreshBoardCache stores the board with its current columns in redis, the
+// cached copy would otherwise still show the old ones.
+func (b *BoardService) refreshBoardCache(boardId uuid.UUID) {
+	board, err := b.Store.GetBoardById(boardId)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return
+	}
+
+	if err := b.RedisClient.Set(board.Id.String(), board); err != nil {
+		log.Println("Error in setting the board in redis", err)
+	}
+}
+
+// columnBoard returns the board of the request path when the request user
+// may change its columns, writing the error response otherwise.
+func (b *BoardService) columnBoard(w http.ResponseWriter, r *http.Request) (*models.Board, error) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return nil, core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	board, err := b.Store.GetBoardById(id)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return nil, core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !middlewares.ContextHasTeamPermission(r.Context(), requestTeamMembership(r.Context(), b.Store, board.TeamId), middlewares.UpdateBoards) {
+		return nil, teamPermissionDenied(w)
+	}
+
+	if board.IsClosed() {
+		return nil, boardClosed(w)
+	}
+
+	return board, nil
+}
+
+func (b *BoardService) GetBoardColumnsHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	board, err := b.Store.GetBoardById(id)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !canViewBoard(r.Context(), b.Store, board) {
+		return teamPermissionDenied(w)
+	}
+
+	return core.ListAPIResponse(w, &core.ListAPI{
+		Status: http.StatusOK,
+		Result: &core.ListAPIResponseBody{
+			Count:  len(board.Columns),
+			Result: board.Columns,
+		},
+	})
+}
+
+// CreateBoardColumnHandler adds a column at the end of the board.
+func (b *BoardService) CreateBoardColumnHandler(w http.ResponseWriter, r *http.Request) error {
+	board, err := b.columnBoard(w, r)
+	if board == nil {
+		return err
+	}
+
+	var columnRequest models.CreateBoardColumnRequest
+
+	json.NewDecoder(r.Body).Decode(&columnRequest)
+	structErr := models.ValidateStruct(&columnRequest)
+	if structErr != nil {
+		log.Println("Error in validating the board column struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	if len(board.Columns) >= models.BOARD_COLUMNS_MAX {
+		return boardColumnsFull(w)
+	}
+
+	if board.HasColumn(columnRequest.Key) {
+		return boardColumnExists(w)
+	}
+
+	column := models.NewBoardColumn(board.Id, len(board.Columns), &columnRequest)
+
+	// The checks above read the board before the column is stored, another
+	// request may have added a column in between.
+	newColumn, storeErr := b.Store.CreateBoardColumn(*column)
+	if errors.Is(storeErr, sql.ErrNoRows) {
+		return boardColumnsFull(w)
+	}
+
+	if storages.IsUniqueViolation(storeErr) {
+		return boardColumnExists(w)
+	}
+
+	if storeErr != nil {
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	b.refreshBoardCache(board.Id)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusCreated,
+		Data:   newColumn,
+	})
+}
+
+// UpdateBoardColumnHandler renames a column or changes its description or
+// color, its feedback stays where it is.
+func (b *BoardService) UpdateBoardColumnHandler(w http.ResponseWriter, r *http.Request) error {
+	board, err := b.columnBoard(w, r)
+	if board == nil {
+		return err
+	}
+
+	columnId, err := uuid.Parse(mux.Vars(r)["column_id"])
+	if err != nil {
+		log.Println("Error in parsing the column id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	column := board.Column(columnId)
+	if column == nil {
+		return boardColumnNotFound(w)
+	}
+
+	var columnRequest models.UpdateBoardColumnRequest
+
+	json.NewDecoder(r.Body).Decode(&columnRequest)
+	structErr := models.ValidateStruct(&columnRequest)
+	if structErr != nil {
+		log.Println("Error in validating the board column struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	column = models.UpdateBoardColumn(column, &columnRequest)
+
+	newColumn, storeErr := b.Store.UpdateBoardColumn(*column)
+	if errors.Is(storeErr, sql.ErrNoRows) {
+		return boardColumnNotFound(w)
+	}
+
+	if storeErr != nil {
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	b.refreshBoardCache(board.Id)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   newColumn,
+	})
+}
+
+// ReorderBoardColumnsHandler takes the ids of every column of the board in
+// their new order.
+func (b *BoardService) ReorderBoardColumnsHandler(w http.ResponseWriter, r *http.Request) error {
+	board, err := b.columnBoard(w, r)
+	if board == nil {
+		return err
+	}
+
+	var reorderRequest models.ReorderBoardColumnsRequest
+
+	json.NewDecoder(r.Body).Decode(&reorderRequest)
+	structErr := models.ValidateStruct(&reorderRequest)
+	if structErr != nil {
+		log.Println("Error in validating the reorder board columns struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	columnIds := make([]uuid.UUID, 0, len(reorderRequest.ColumnIds))
+	for _, value := range reorderRequest.ColumnIds {
+		columnId, _ := uuid.Parse(value)
+		if board.Column(columnId) == nil {
+			return boardColumnNotFound(w)
+		}
+		columnIds = append(columnIds, columnId)
+	}
+
+	if len(columnIds) != len(board.Columns) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Every column of the board has to be listed once"},
+		})
+	}
+
+	storeErr := b.Store.ReorderBoardColumns(board.Id, columnIds, time.Now().UTC())
+	if errors.Is(storeErr, sql.ErrNoRows) {
+		return boardColumnNotFound(w)
+	}
+
+	if storeErr != nil {
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	columns, err := b.Store.GetBoardColumns(board.Id)
+	if err != nil {
+		log.Println("Error in fetching the board columns", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to fetch board columns"},
+		})
+	}
+
+	b.refreshBoardCache(board.Id)
+
+	return core.ListAPIResponse(w, &core.ListAPI{
+		Status: http.StatusOK,
+		Result: &core.ListAPIResponseBody{
+			Count:  len(columns),
+			Result: columns,
+		},
+	})
+}
+
+// RemoveBoardColumnHandler removes a column. Its feedback is moved to the
+// column named by the move_to query parameter, or deleted when
+// delete_feedback=true is sent. A column that still has feedback is not
+// removed without either of them.
+func (b *BoardService) RemoveBoardColumnHandler(w http.ResponseWriter, r *http.Request) error {
+	board, err := b.columnBoard(w, r)
+	if board == nil {
+		return err
+	}
+
+	columnId, err := uuid.Parse(mux.Vars(r)["column_id"])
+	if err != nil {
+		log.Println("Error in parsing the column id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	column := board.Column(columnId)
+	if column == nil {
+		return boardColumnNotFound(w)
+	}
+
+	if len(board.Columns) == 1 {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "The last column of a board cannot be removed"},
+		})
+	}
+
+	moveTo := models.ColumnType(r.URL.Query().Get("move_to"))
+	deleteFeedback, _ := strconv.ParseBool(r.URL.Query().Get("delete_feedback"))
+
+	// Once the retro moved past collecting, deleting the feedback of a column
+	// would throw away cards that were already grouped or voted on.
+	if deleteFeedback && board.Phase != models.PhaseDraft && !board.Allows(models.ActivityDeleteFeedback) {
+		return phaseNotAllowed(w, board)
+	}
+
+	if moveTo != "" && (moveTo == column.Key || !board.HasColumn(moveTo)) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "move_to is not one of the other columns of the board"},
+		})
+	}
+
+	if moveTo == "" && !deleteFeedback {
+		count, err := b.Store.CountColumnFeedbacks(board.Id, column.Key)
+		if err != nil {
+			log.Println("Error in counting the feedback of the column", err)
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusInternalServerError,
+				Data:   &core.APIError{Detail: "Unable to remove the column"},
+			})
+		}
+
+		if count > 0 {
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusConflict,
+				Data:   &core.APIError{Detail: "The column has feedback, move it with move_to or delete it with delete_feedback=true"},
+			})
+		}
+	}
+
+	feedbackIds, storeErr := b.Store.RemoveBoardColumn(board.Id, column.Id, moveTo)
+	if errors.Is(storeErr, sql.ErrNoRows) {
+		return boardColumnNotFound(w)
+	}
+
+	if storeErr != nil {
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	// The action items follow their cards, or the board has none any more.
+	if board.Settings.ActionColumn == column.Key {
+		board.Settings.ActionColumn = moveTo
+		if _, err := b.Store.UpdateBoard(*board); err != nil {
+			log.Println("Error in updating the action column of the board", err)
+		}
+	}
+
+	deleteFeedbackCache(b.RedisClient, feedbackIds)
+	b.refreshBoardCache(board.Id)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   map[string]string{"detail": "Column removed successfully"},
+	})
+}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// requestBoardMember returns the membership of the request user on the
+// board, nil when they are not on it.
+func requestBoardMember(ctx context.Context, store storages.Storage, boardId uuid.UUID) *models.BoardMember {
+	user, ok := middlewares.RequestUserFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	member, err := store.GetBoardMember(boardId, user.Id)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error in fetching the board member", err)
+		}
+		return nil
+	}
+
+	return member
+}
+
+// canViewBoard reports whether the request user may see the board, through
+// the team it belongs to or because they were invited to it. Guests only
+// ever see the board they joined.
+func canViewBoard(ctx context.Context, store storages.Storage, board *models.Board) bool {
+	if guestBoardId, ok := middlewares.GuestBoardFromContext(ctx); ok && guestBoardId != board.Id {
+		return false
+	}
+
+	if canViewTeam(ctx, store, board.TeamId) {
+		return true
+	}
+
+	return requestBoardMember(ctx, store, board.Id) != nil
+}
+
+// canManageBoardMembers lets facilitators of the board and the admins of its
+// team invite and remove people.
+func canManageBoardMembers(ctx context.Context, store storages.Storage, board *models.Board) bool {
+	if middlewares.ContextHasTeamPermission(ctx, requestTeamMembership(ctx, store, board.TeamId), middlewares.ManageBoardMembers) {
+		return true
+	}
+
+	return middlewares.ContextHasBoardPermission(ctx, requestBoardMember(ctx, store, board.Id), middlewares.ManageBoardMembers)
+}
+
+func boardInvitationURL() string {
+	url := os.Getenv("BOARD_INVITATION_URL")
+	if url == "" {
+		url = fmt.Sprintf("http://localhost:%s/boards/invitations/accept/", os.Getenv("APPLICATION_PORT"))
+	}
+	return url
+}
+
+func (b *BoardService) GetBoardMembersHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	board, err := b.Store.GetBoardById(id)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !canViewBoard(r.Context(), b.Store, board) {
+		return teamPermissionDenied(w)
+	}
+
+	members, err := b.Store.GetBoardMembers(id)
+	if err != nil {
+		log.Println("Error in fetching the board members", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to fetch board members"},
+		})
+	}
+
+	return core.ListAPIResponse(w, &core.ListAPI{
+		Status: http.StatusOK,
+		Result: &core.ListAPIResponseBody{
+			Count:  len(members),
+			Result: members,
+		},
+	})
+}
+
+// RemoveBoardMemberHandler removes a user from the board, members can always
+// remove themselves to leave it.
+func (b *BoardService) RemoveBoardMemberHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	userId, err := uuid.Parse(mux.Vars(r)["user_id"])
+	if err != nil {
+		log.Println("Error in parsing the user id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	board, err := b.Store.GetBoardById(id)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if userId != requestUser.Id && !canManageBoardMembers(r.Context(), b.Store, board) {
+		return teamPermissionDenied(w)
+	}
+
+	if board.IsClosed() {
+		return boardClosed(w)
+	}
+
+	err = b.Store.RemoveBoardMember(id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusNotFound,
+			Data:   &core.APIError{Detail: "Board member not found"},
+		})
+	}
+
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	recordAuditEvent(r, b.Store, models.AuditBoardMemberRemoved, models.AuditTargetBoard, id.String(), map[string]string{"user_id": userId.String()}, nil)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   map[string]string{"detail": "Board member removed successfully"},
+	})
+}
+
+// CreateBoardInvitationHandler emails a single use link to join the board,
+// only the hash of the token is stored.
+func (b *BoardService) CreateBoardInvitationHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	board, err := b.Store.GetBoardById(id)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !canManageBoardMembers(r.Context(), b.Store, board) {
+		return teamPermissionDenied(w)
+	}
+
+	if board.IsClosed() {
+		return boardClosed(w)
+	}
+
+	var invitationRequest models.CreateBoardInvitationRequest
+
+	json.NewDecoder(r.Body).Decode(&invitationRequest)
+	structErr := models.ValidateStruct(&invitationRequest)
+	if structErr != nil {
+		log.Println("Error in validating the board invitation struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	rawToken, err := common.GenerateSecureToken(32)
+	if err != nil {
+		log.Println("Error while generating the board invitation token", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while creating the invitation, Try again later"},
+		})
+	}
+
+	invitation := models.NewBoardInvitation(board.Id, requestUser.Id, common.HashToken(rawToken), &invitationRequest)
+	err = b.Store.CreateBoardInvitation(*invitation)
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	invitationLink := fmt.Sprintf("%s?token=%s", boardInvitationURL(), rawToken)
+	emailErr := b.Email.SendEmailForBoardInvitation(invitation.Email, "You are invited to a retro board", board.Name, invitationLink)
+	if emailErr != nil {
+		log.Println("Error while sending email", emailErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Error while sending the invitation email"},
+		})
+	}
+
+	recordAuditEvent(r, b.Store, models.AuditBoardInvitationCreated, models.AuditTargetBoard, board.Id.String(), nil, invitation)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusCreated,
+		Data:   invitation,
+	})
+}
+
+// AcceptBoardInvitationHandler adds the request user to the board, the
+// invitation only works for the account with the email it was sent to.
+func (b *BoardService) AcceptBoardInvitationHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	var acceptRequest models.AcceptBoardInvitationRequest
+
+	json.NewDecoder(r.Body).Decode(&acceptRequest)
+	structErr := models.ValidateStruct(&acceptRequest)
+	if structErr != nil {
+		log.Println("Error in validating the accept invitation struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	invalidInvitation := &core.Response{
+		Status: http.StatusBadRequest,
+		Data:   &core.APIError{Detail: "Invalid or expired invitation"},
+	}
+
+	invitation, err := b.Store.GetBoardInvitationByHash(common.HashToken(acceptRequest.Token))
+	if err != nil || !invitation.IsValid() {
+		log.Println("Invalid board invitation", err)
+		return core.APIResponse(w, invalidInvitation)
+	}
+
+	if !strings.EqualFold(invitation.Email, requestUser.Email) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusForbidden,
+			Data:   &core.APIError{Detail: "This invitation was sent to another email address"},
+		})
+	}
+
+	board, err := b.Store.GetBoardById(invitation.BoardId)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, invalidInvitation)
+	}
+
+	if board.IsClosed() {
+		return boardClosed(w)
+	}
+
+	member := models.NewBoardMember(invitation.BoardId, requestUser.Id, invitation.Role)
+	err = b.Store.AcceptBoardInvitation(invitation.Id, *member)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.APIResponse(w, invalidInvitation)
+	}
+
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	member.User = requestUser
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   member,
+	})
+}
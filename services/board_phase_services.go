@@ -0,0 +1,110 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+func phaseNotAllowed(w http.ResponseWriter, board *models.Board) error {
+	if board.IsClosed() {
+		return boardClosed(w)
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusConflict,
+		Data:   &core.APIError{Detail: "This is not possible while the board is in the " + string(board.Phase) + " phase"},
+	})
+}
+
+// ChangeBoardPhaseHandler moves the board on to its next phase, or closes it
+// from any phase. Only facilitators of the board and team admins drive the
+// retro.
+func (b *BoardService) ChangeBoardPhaseHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	board, err := b.Store.GetBoardById(id)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !canManageBoardMembers(r.Context(), b.Store, board) {
+		return teamPermissionDenied(w)
+	}
+
+	var phaseRequest models.BoardPhaseRequest
+
+	json.NewDecoder(r.Body).Decode(&phaseRequest)
+	structErr := models.ValidateStruct(&phaseRequest)
+	if structErr != nil {
+		log.Println("Error in validating the board phase struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	if board.IsClosed() {
+		return boardClosed(w)
+	}
+
+	if !board.CanMoveTo(phaseRequest.Phase) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "A board in the " + string(board.Phase) + " phase can only move on to " + string(board.Phase.NextPhase()) + " or be closed"},
+		})
+	}
+
+	if phaseRequest.Phase == models.PhaseClosed {
+		return b.closeBoard(w, r, board)
+	}
+
+	modifiedAt := time.Now().UTC()
+	err = b.Store.UpdateBoardPhase(board.Id, board.Phase, phaseRequest.Phase, modifiedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusConflict,
+			Data:   &core.APIError{Detail: "The phase of the board was changed in the meantime"},
+		})
+	}
+
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	board.Phase = phaseRequest.Phase
+	board.ModifiedAt = modifiedAt
+
+	b.refreshBoardCache(board.Id)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   board,
+	})
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// CloneBoardHandler starts the next retro of a series from the board, with
+// its template, settings, columns and members. The unresolved action items
+// of the board are carried over when carry_over_action_items is set.
+func (b *BoardService) CloneBoardHandler(w http.ResponseWriter, r *http.Request) error {
+	userResponse, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	board, err := b.Store.GetBoardById(id)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !canViewBoard(r.Context(), b.Store, board) {
+		return teamPermissionDenied(w)
+	}
+
+	if !middlewares.ContextHasTeamPermission(r.Context(), requestTeamMembership(r.Context(), b.Store, board.TeamId), middlewares.CreateBoards) {
+		return teamPermissionDenied(w)
+	}
+
+	var cloneRequest models.CloneBoardRequest
+
+	json.NewDecoder(r.Body).Decode(&cloneRequest)
+	structErr := models.ValidateStruct(&cloneRequest)
+	if structErr != nil {
+		log.Println("Error in validating the clone board struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	var carryOverFrom models.ColumnType
+	if cloneRequest.CarryOverActionItems {
+		if board.Settings.ActionColumn == "" {
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusBadRequest,
+				Data:   &core.APIError{Detail: "The board has no action column to carry over"},
+			})
+		}
+
+		carryOverFrom = board.Settings.ActionColumn
+	}
+
+	newBoard := models.CloneBoard(board, &cloneRequest, userResponse)
+
+	storeErr := b.Store.CloneBoard(*newBoard, carryOverFrom)
+	if storeErr != nil {
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	redisErr := b.RedisClient.Set(newBoard.Id.String(), newBoard)
+	if redisErr != nil {
+		log.Println("Error in setting the board in redis", redisErr)
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusCreated,
+		Data:   newBoard,
+	})
+}
+
+// GetBoardSeriesHandler lists the boards of the retro series the board is
+// part of, oldest first. Boards of the series the request user may not see
+// are left out.
+func (b *BoardService) GetBoardSeriesHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	board, err := b.Store.GetBoardById(id)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !canViewBoard(r.Context(), b.Store, board) {
+		return teamPermissionDenied(w)
+	}
+
+	series, err := b.Store.GetBoardSeries(board.Id)
+	if err != nil {
+		log.Println("Error in fetching the board series", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to fetch the board series"},
+		})
+	}
+
+	boards := make([]*models.Board, 0, len(series))
+	for _, seriesBoard := range series {
+		if canViewBoard(r.Context(), b.Store, seriesBoard) {
+			boards = append(boards, seriesBoard)
+		}
+	}
+
+	return core.ListAPIResponse(w, &core.ListAPI{
+		Status: http.StatusOK,
+		Result: &core.ListAPIResponseBody{
+			Count:  len(boards),
+			Result: boards,
+		},
+	})
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/Aakash-Pandit/reetro-golang/common"
 	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
 	"github.com/Aakash-Pandit/reetro-golang/models"
 	"github.com/Aakash-Pandit/reetro-golang/storages"
 	"github.com/google/uuid"
@@ -15,17 +16,36 @@ import (
 
 type BoardService struct {
 	Store       storages.Storage
-	User        RequestUser
 	RedisClient storages.RedisStoreInterface
+	Email       common.EmailInterface
 }
 
-func NewBoardService(store storages.Storage, user RequestUser, redisClient storages.RedisStoreInterface) *BoardService {
-	return &BoardService{Store: store, User: user, RedisClient: redisClient}
+func NewBoardService(store storages.Storage, redisClient storages.RedisStoreInterface, email common.EmailInterface) *BoardService {
+	return &BoardService{Store: store, RedisClient: redisClient, Email: email}
 }
 
+// GetAllBoardsHandler lists the boards of the teams the request user is a
+// member of, users allowed to view every team get all boards.
 func (b *BoardService) GetAllBoardsHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
 	limit, offset := core.Pagination(r)
-	boards, err := b.Store.GetAllBoards(limit, offset)
+
+	var boards []*models.Board
+	var err error
+	if middlewares.ContextHasPermission(r.Context(), middlewares.ViewAllTeams) {
+		boards, err = b.Store.GetAllBoards(limit, offset)
+	} else {
+		boards, err = b.Store.GetBoardsForUser(requestUser.Id, limit, offset)
+	}
+
 	if err != nil {
 		log.Println("Error in fetching the boards", err)
 		return core.APIResponse(w, &core.Response{
@@ -55,25 +75,27 @@ func (b *BoardService) GetBoardByIdHandler(w http.ResponseWriter, r *http.Reques
 	}
 
 	var cacheBoard models.Board
-	board, err := b.RedisClient.Get(id.String(), cacheBoard)
-	if board != nil {
-		return core.APIResponse(w, &core.Response{
-			Status: http.StatusOK,
-			Data:   board,
-		})
-	}
-
+	cached, err := b.RedisClient.Get(id.String(), cacheBoard)
 	if err != nil {
 		log.Println("Error in fetching the board from cache", err)
 	}
 
-	board, err = b.Store.GetBoardById(id)
-	if err != nil {
-		log.Println("Error in fetching the Board", err)
-		return core.APIResponse(w, &core.Response{
-			Status: http.StatusBadRequest,
-			Data:   &core.APIError{Detail: "Board not found"},
-		})
+	var board *models.Board
+	if cached != nil {
+		board = common.AnyToAnyStructField(cached, &models.Board{}).(*models.Board)
+	} else {
+		board, err = b.Store.GetBoardById(id)
+		if err != nil {
+			log.Println("Error in fetching the Board", err)
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusBadRequest,
+				Data:   &core.APIError{Detail: "Board not found"},
+			})
+		}
+	}
+
+	if !canViewBoard(r.Context(), b.Store, board) {
+		return teamPermissionDenied(w)
 	}
 
 	return core.APIResponse(w, &core.Response{
@@ -85,20 +107,12 @@ func (b *BoardService) GetBoardByIdHandler(w http.ResponseWriter, r *http.Reques
 func (b *BoardService) CreateBoardHandler(w http.ResponseWriter, r *http.Request) error {
 	var boardRequest models.CreateBoardRequest
 
-	userResponse := b.User.GetRequestUser(r)
-	if userResponse == nil {
+	userResponse, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
 		log.Println("Error while fetching Request User")
-		return core.APIResponse(w, &core.Response{
-			Status: http.StatusBadRequest,
-			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
-		})
-	}
-
-	if userResponse.UserType != models.SuperAdmin {
-		log.Println("Only super admin can create Board")
 		return core.APIResponse(w, &core.Response{
 			Status: http.StatusUnauthorized,
-			Data:   &core.APIError{Detail: "Unauthorized to create board"},
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
 		})
 	}
 
@@ -114,10 +128,44 @@ func (b *BoardService) CreateBoardHandler(w http.ResponseWriter, r *http.Request
 
 	defer r.Body.Close()
 
+	teamId, idErr := uuid.Parse(boardRequest.TeamId)
+	if idErr != nil {
+		log.Println("Error in parsing the team id", idErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: idErr.Error()},
+		})
+	}
+
+	team, dbErr := b.Store.GetTeamById(teamId)
+	if dbErr != nil || !canViewTeam(r.Context(), b.Store, teamId) {
+		return teamNotFound(w)
+	}
+
+	if !middlewares.ContextHasTeamPermission(r.Context(), requestTeamMembership(r.Context(), b.Store, teamId), middlewares.CreateBoards) {
+		return teamPermissionDenied(w)
+	}
+
+	template, templateErr := resolveBoardTemplate(b.Store, teamId, boardRequest.Template)
+	if templateErr != nil {
+		log.Println("Error in fetching the board template", templateErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Unknown template"},
+		})
+	}
+
+	models.ApplyBoardTemplate(&boardRequest, template)
+
+	boardRequest.Team = team
 	boardRequest.CreatedBy = userResponse
 	boardRequest.ModifiedBy = userResponse
 	board := models.NewBoard(&boardRequest)
 
+	if !board.HasValidSettings() {
+		return invalidActionColumn(w)
+	}
+
 	newBoard, store_error := b.Store.CreateBoard(*board)
 	if store_error != nil {
 		msg := common.AnyToAnyStructField(store_error, &core.DatabaseError{})
@@ -140,20 +188,12 @@ func (b *BoardService) CreateBoardHandler(w http.ResponseWriter, r *http.Request
 }
 
 func (b *BoardService) UpdateBoardHandler(w http.ResponseWriter, r *http.Request) error {
-	userResponse := b.User.GetRequestUser(r)
-	if userResponse == nil {
+	userResponse, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
 		log.Println("Error while fetching Request User")
-		return core.APIResponse(w, &core.Response{
-			Status: http.StatusBadRequest,
-			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
-		})
-	}
-
-	if userResponse.UserType != models.SuperAdmin {
-		log.Println("Only super admin can update Board")
 		return core.APIResponse(w, &core.Response{
 			Status: http.StatusUnauthorized,
-			Data:   &core.APIError{Detail: "Unauthorized to create board"},
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
 		})
 	}
 
@@ -175,6 +215,14 @@ func (b *BoardService) UpdateBoardHandler(w http.ResponseWriter, r *http.Request
 		})
 	}
 
+	if !middlewares.ContextHasTeamPermission(r.Context(), requestTeamMembership(r.Context(), b.Store, board.TeamId), middlewares.UpdateBoards) {
+		return teamPermissionDenied(w)
+	}
+
+	if board.IsClosed() {
+		return boardClosed(w)
+	}
+
 	var boardRequest models.UpdateBoardRequest
 
 	json.NewDecoder(r.Body).Decode(&boardRequest)
@@ -189,9 +237,17 @@ func (b *BoardService) UpdateBoardHandler(w http.ResponseWriter, r *http.Request
 
 	defer r.Body.Close()
 
+	if boardRequest.Settings != nil && *boardRequest.Settings != board.Settings && !board.AllowsSettingsChange() {
+		return phaseNotAllowed(w, board)
+	}
+
 	boardRequest.ModifiedBy = userResponse
 	board = models.UpdateBoard(board, &boardRequest)
 
+	if !board.HasValidSettings() {
+		return invalidActionColumn(w)
+	}
+
 	newBoard, store_error := b.Store.UpdateBoard(*board)
 	if store_error != nil {
 		msg := common.AnyToAnyStructField(store_error, &core.DatabaseError{})
@@ -224,6 +280,19 @@ func (b *BoardService) DeleteBoardHandler(w http.ResponseWriter, r *http.Request
 		})
 	}
 
+	board, err := b.Store.GetBoardById(id)
+	if err != nil {
+		log.Println("Error in fetching the Board:", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !middlewares.ContextHasTeamPermission(r.Context(), requestTeamMembership(r.Context(), b.Store, board.TeamId), middlewares.DeleteBoards) {
+		return teamPermissionDenied(w)
+	}
+
 	err = b.Store.DeleteBoard(id)
 	if err != nil {
 		log.Println("Error in fetching the Board:", err)
@@ -238,6 +307,8 @@ func (b *BoardService) DeleteBoardHandler(w http.ResponseWriter, r *http.Request
 		log.Println("Error in setting the board in redis", redisErr)
 	}
 
+	recordAuditEvent(r, b.Store, models.AuditBoardDeleted, models.AuditTargetBoard, id.String(), board, nil)
+
 	return core.APIResponse(w, &core.Response{
 		Status: http.StatusOK,
 		Data:   map[string]string{"detail": "Board deleted successfully"},
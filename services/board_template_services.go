@@ -0,0 +1,247 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+func boardTemplateNotFound(w http.ResponseWriter) error {
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusNotFound,
+		Data:   &core.APIError{Detail: "Template not found"},
+	})
+}
+
+func invalidActionColumn(w http.ResponseWriter) error {
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusBadRequest,
+		Data:   &core.APIError{Detail: "The action column is not one of the columns"},
+	})
+}
+
+// resolveBoardTemplate looks the key up in the built-in catalog first and in
+// the custom templates of the team after that.
+func resolveBoardTemplate(store storages.Storage, teamId uuid.UUID, key models.TemplateType) (*models.BoardTemplate, error) {
+	if template := models.BuiltInTemplate(key); template != nil {
+		return template, nil
+	}
+
+	return store.GetBoardTemplate(teamId, key)
+}
+
+// templateTeam returns the team of the request path when the request user
+// may manage its templates, writing the error response otherwise.
+func (b *BoardService) templateTeam(w http.ResponseWriter, r *http.Request) (*models.Team, error) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return nil, core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	team, err := b.Store.GetTeamById(id)
+	if err != nil || !canViewTeam(r.Context(), b.Store, id) {
+		return nil, teamNotFound(w)
+	}
+
+	if !middlewares.ContextHasTeamPermission(r.Context(), requestTeamMembership(r.Context(), b.Store, id), middlewares.UpdateTeams) {
+		return nil, teamPermissionDenied(w)
+	}
+
+	return team, nil
+}
+
+// GetBoardTemplatesHandler lists the built-in templates, followed by the
+// custom templates of the team given with the team_id query parameter.
+func (b *BoardService) GetBoardTemplatesHandler(w http.ResponseWriter, r *http.Request) error {
+	templates := models.BuiltInTemplates()
+
+	if value := r.URL.Query().Get("team_id"); value != "" {
+		teamId, err := uuid.Parse(value)
+		if err != nil {
+			log.Println("Error in parsing the team id", err)
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusBadRequest,
+				Data:   &core.APIError{Detail: err.Error()},
+			})
+		}
+
+		if !canViewTeam(r.Context(), b.Store, teamId) {
+			return teamNotFound(w)
+		}
+
+		customTemplates, err := b.Store.GetBoardTemplates(teamId)
+		if err != nil {
+			log.Println("Error in fetching the board templates", err)
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusInternalServerError,
+				Data:   &core.APIError{Detail: "Unable to fetch board templates"},
+			})
+		}
+
+		templates = append(templates, customTemplates...)
+	}
+
+	return core.ListAPIResponse(w, &core.ListAPI{
+		Status: http.StatusOK,
+		Result: &core.ListAPIResponseBody{
+			Count:  len(templates),
+			Result: templates,
+		},
+	})
+}
+
+func (b *BoardService) CreateBoardTemplateHandler(w http.ResponseWriter, r *http.Request) error {
+	userResponse, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	team, err := b.templateTeam(w, r)
+	if team == nil {
+		return err
+	}
+
+	var templateRequest models.CreateBoardTemplateRequest
+
+	json.NewDecoder(r.Body).Decode(&templateRequest)
+	structErr := models.ValidateStruct(&templateRequest)
+	if structErr != nil {
+		log.Println("Error in validating the board template struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	_, lookupErr := resolveBoardTemplate(b.Store, team.Id, templateRequest.Key)
+	if lookupErr == nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "A template with this key already exists"},
+		})
+	}
+
+	if !errors.Is(lookupErr, sql.ErrNoRows) {
+		log.Println("Error in fetching the board template", lookupErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to create the template"},
+		})
+	}
+
+	template := models.NewBoardTemplate(team.Id, userResponse.Id, &templateRequest)
+	if !template.HasValidSettings() {
+		return invalidActionColumn(w)
+	}
+
+	newTemplate, storeErr := b.Store.CreateBoardTemplate(*template)
+	if storeErr != nil {
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   msg,
+		})
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusCreated,
+		Data:   newTemplate,
+	})
+}
+
+// UpdateBoardTemplateHandler replaces the columns and settings of a custom
+// template, boards already created from it are left as they are.
+func (b *BoardService) UpdateBoardTemplateHandler(w http.ResponseWriter, r *http.Request) error {
+	team, err := b.templateTeam(w, r)
+	if team == nil {
+		return err
+	}
+
+	template, err := b.Store.GetBoardTemplate(team.Id, models.TemplateType(mux.Vars(r)["key"]))
+	if err != nil {
+		log.Println("Error in fetching the board template", err)
+		return boardTemplateNotFound(w)
+	}
+
+	var templateRequest models.UpdateBoardTemplateRequest
+
+	json.NewDecoder(r.Body).Decode(&templateRequest)
+	structErr := models.ValidateStruct(&templateRequest)
+	if structErr != nil {
+		log.Println("Error in validating the board template struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	template = models.UpdateBoardTemplate(template, &templateRequest)
+	if !template.HasValidSettings() {
+		return invalidActionColumn(w)
+	}
+
+	newTemplate, storeErr := b.Store.UpdateBoardTemplate(*template)
+	if errors.Is(storeErr, sql.ErrNoRows) {
+		return boardTemplateNotFound(w)
+	}
+
+	if storeErr != nil {
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   msg,
+		})
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   newTemplate,
+	})
+}
+
+func (b *BoardService) DeleteBoardTemplateHandler(w http.ResponseWriter, r *http.Request) error {
+	team, err := b.templateTeam(w, r)
+	if team == nil {
+		return err
+	}
+
+	err = b.Store.DeleteBoardTemplate(team.Id, models.TemplateType(mux.Vars(r)["key"]))
+	if errors.Is(err, sql.ErrNoRows) {
+		return boardTemplateNotFound(w)
+	}
+
+	if err != nil {
+		log.Println("Error in deleting the board template", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to delete the template"},
+		})
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   map[string]string{"detail": "Template deleted successfully"},
+	})
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+// GroupFeedbackHandler stacks the feedback onto another feedback of the board
+// while it is grouping. When that one is already in a group the feedback
+// joins the group, feedback stacked onto the moved one comes along.
+func (f *FeedbackService) GroupFeedbackHandler(w http.ResponseWriter, r *http.Request) error {
+	feedback, _, err := f.participantFeedback(w, r, models.ActivityGroupFeedback)
+	if feedback == nil {
+		return err
+	}
+
+	var groupRequest models.GroupFeedbackRequest
+
+	json.NewDecoder(r.Body).Decode(&groupRequest)
+	structErr := models.ValidateStruct(&groupRequest)
+	if structErr != nil {
+		log.Println("Error in validating the group feedback struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	groupFeedbackId, _ := uuid.Parse(groupRequest.GroupId)
+	groupFeedback, err := f.Store.GetFeedbackById(groupFeedbackId)
+	if err != nil || groupFeedback.BoardId != feedback.BoardId {
+		log.Println("Error in fetching the feedback to group with", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "group_id is not a feedback of the same board"},
+		})
+	}
+
+	groupId := groupFeedback.Id
+	if groupFeedback.GroupId != nil {
+		groupId = *groupFeedback.GroupId
+	}
+
+	if groupId == feedback.Id {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "A feedback cannot be grouped with itself"},
+		})
+	}
+
+	feedbackIds, storeErr := f.Store.GroupFeedback(feedback.Id, &groupId, time.Now().UTC())
+	if storeErr != nil {
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	deleteFeedbackCache(f.RedisClient, feedbackIds)
+
+	return f.feedbackResponse(w, http.StatusOK, feedback.Id)
+}
+
+// UngroupFeedbackHandler takes the feedback out of its group while the board
+// is grouping.
+func (f *FeedbackService) UngroupFeedbackHandler(w http.ResponseWriter, r *http.Request) error {
+	feedback, _, err := f.participantFeedback(w, r, models.ActivityGroupFeedback)
+	if feedback == nil {
+		return err
+	}
+
+	if feedback.GroupId == nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "The feedback is not in a group"},
+		})
+	}
+
+	feedbackIds, storeErr := f.Store.GroupFeedback(feedback.Id, nil, time.Now().UTC())
+	if errors.Is(storeErr, sql.ErrNoRows) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Feedback not found"},
+		})
+	}
+
+	if storeErr != nil {
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	deleteFeedbackCache(f.RedisClient, feedbackIds)
+
+	return f.feedbackResponse(w, http.StatusOK, feedback.Id)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+)
+
+// ResolveFeedbackHandler marks an action item as done, it is then no longer
+// carried over to the next board of the series.
+func (f *FeedbackService) ResolveFeedbackHandler(w http.ResponseWriter, r *http.Request) error {
+	resolvedAt := time.Now().UTC()
+	return f.resolveFeedback(w, r, &resolvedAt)
+}
+
+// ReopenFeedbackHandler marks a resolved action item as open again.
+func (f *FeedbackService) ReopenFeedbackHandler(w http.ResponseWriter, r *http.Request) error {
+	return f.resolveFeedback(w, r, nil)
+}
+
+func (f *FeedbackService) resolveFeedback(w http.ResponseWriter, r *http.Request, resolvedAt *time.Time) error {
+	feedback, board, err := f.participantFeedback(w, r, models.ActivityResolveFeedback)
+	if feedback == nil {
+		return err
+	}
+
+	if board.Settings.ActionColumn == "" || feedback.Column != board.Settings.ActionColumn {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Only action items can be resolved"},
+		})
+	}
+
+	storeErr := f.Store.ResolveFeedback(feedback.Id, resolvedAt)
+	if errors.Is(storeErr, sql.ErrNoRows) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Feedback not found"},
+		})
+	}
+
+	if storeErr != nil {
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	return f.feedbackResponse(w, http.StatusOK, feedback.Id)
+}
@@ -1,12 +1,15 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
 
 	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/config"
 	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
 	"github.com/Aakash-Pandit/reetro-golang/models"
 	"github.com/Aakash-Pandit/reetro-golang/storages"
 	"github.com/google/uuid"
@@ -15,17 +18,45 @@ import (
 
 type FeedbackService struct {
 	Store       storages.Storage
-	User        RequestUser
 	RedisClient storages.RedisStoreInterface
 }
 
-func NewFeedbackService(store storages.Storage, user RequestUser, redisClient storages.RedisStoreInterface) *FeedbackService {
-	return &FeedbackService{Store: store, User: user, RedisClient: redisClient}
+func NewFeedbackService(store storages.Storage, redisClient storages.RedisStoreInterface) *FeedbackService {
+	return &FeedbackService{Store: store, RedisClient: redisClient}
 }
 
+// deleteFeedbackCache drops the cached copies of feedback a store call
+// changed along with the one of the request, the next read loads them again.
+func deleteFeedbackCache(redisClient storages.RedisStoreInterface, ids []uuid.UUID) {
+	for _, id := range ids {
+		if err := redisClient.Del(id.String()); err != nil {
+			log.Println("Error in deleting the feedback from redis", err)
+		}
+	}
+}
+
+// GetAllFeedbacksHandler lists the feedbacks on boards of the teams the
+// request user is a member of, users allowed to view every team get all.
 func (f *FeedbackService) GetAllFeedbacksHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
 	limit, offset := core.Pagination(r)
-	feedbacks, err := f.Store.GetAllFeedbacks(limit, offset)
+
+	var feedbacks []*models.Feedback
+	var err error
+	if middlewares.ContextHasPermission(r.Context(), middlewares.ViewAllTeams) {
+		feedbacks, err = f.Store.GetAllFeedbacks(limit, offset)
+	} else {
+		feedbacks, err = f.Store.GetFeedbacksForUser(requestUser.Id, limit, offset)
+	}
+
 	if err != nil {
 		log.Println("Error in fetching the feedbacks", err)
 		return core.APIResponse(w, &core.Response{
@@ -55,25 +86,27 @@ func (f *FeedbackService) GetFeedbackByIdHandler(w http.ResponseWriter, r *http.
 	}
 
 	var cacheFeedback models.Feedback
-	feedback, err := f.RedisClient.Get(id.String(), cacheFeedback)
-	if feedback != nil {
-		return core.APIResponse(w, &core.Response{
-			Status: http.StatusOK,
-			Data:   feedback,
-		})
-	}
-
+	cached, err := f.RedisClient.Get(id.String(), cacheFeedback)
 	if err != nil {
 		log.Println("Error in fetching the feedback from cache", err)
 	}
 
-	feedback, err = f.Store.GetFeedbackById(id)
-	if err != nil {
-		log.Println("Error in fetching the Feedback", err)
-		return core.APIResponse(w, &core.Response{
-			Status: http.StatusBadRequest,
-			Data:   &core.APIError{Detail: "Feedback not found"},
-		})
+	var feedback *models.Feedback
+	if cached != nil {
+		feedback = common.AnyToAnyStructField(cached, &models.Feedback{}).(*models.Feedback)
+	} else {
+		feedback, err = f.Store.GetFeedbackById(id)
+		if err != nil {
+			log.Println("Error in fetching the Feedback", err)
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusBadRequest,
+				Data:   &core.APIError{Detail: "Feedback not found"},
+			})
+		}
+	}
+
+	if !f.canViewFeedbackBoard(r.Context(), feedback.BoardId) {
+		return teamPermissionDenied(w)
 	}
 
 	return core.APIResponse(w, &core.Response{
@@ -85,15 +118,22 @@ func (f *FeedbackService) GetFeedbackByIdHandler(w http.ResponseWriter, r *http.
 func (f *FeedbackService) CreateFeedbackHandler(w http.ResponseWriter, r *http.Request) error {
 	var feedbackRequest models.CreateFeedbackRequest
 
-	userResponse := f.User.GetRequestUser(r)
-	if userResponse == nil {
+	userResponse, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
 		log.Println("Error while fetching Request User")
 		return core.APIResponse(w, &core.Response{
-			Status: http.StatusBadRequest,
+			Status: http.StatusUnauthorized,
 			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
 		})
 	}
 
+	if config.EnvBool("EMAIL_VERIFICATION_REQUIRED_FOR_FEEDBACK", false) && !userResponse.EmailVerified {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusForbidden,
+			Data:   &core.APIError{Detail: "Verify your email address before adding feedback"},
+		})
+	}
+
 	json.NewDecoder(r.Body).Decode(&feedbackRequest)
 	err := models.ValidateStruct(&feedbackRequest)
 	if err != nil {
@@ -124,6 +164,21 @@ func (f *FeedbackService) CreateFeedbackHandler(w http.ResponseWriter, r *http.R
 		})
 	}
 
+	if !middlewares.ContextHasBoardPermission(r.Context(), requestBoardMember(r.Context(), f.Store, board.Id), middlewares.CreateFeedbacks) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusForbidden,
+			Data:   &core.APIError{Detail: "Only participants of the board can add feedback"},
+		})
+	}
+
+	if !board.HasColumn(feedbackRequest.Column) {
+		return invalidFeedbackColumn(w)
+	}
+
+	if !board.AllowsIn(models.ActivityAddFeedback, feedbackRequest.Column) {
+		return phaseNotAllowed(w, board)
+	}
+
 	feedbackRequest.Board = board
 	feedbackRequest.CreatedBy = userResponse
 	feedback := models.NewFeedback(&feedbackRequest)
@@ -148,12 +203,14 @@ func (f *FeedbackService) CreateFeedbackHandler(w http.ResponseWriter, r *http.R
 	})
 }
 
+// UpdateFeedbackHandler lets the author change their feedback, facilitators
+// of the board and admins can change anyone's.
 func (f *FeedbackService) UpdateFeedbackHandler(w http.ResponseWriter, r *http.Request) error {
-	userResponse := f.User.GetRequestUser(r)
-	if userResponse == nil {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
 		log.Println("Error while fetching Request User")
 		return core.APIResponse(w, &core.Response{
-			Status: http.StatusBadRequest,
+			Status: http.StatusUnauthorized,
 			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
 		})
 	}
@@ -176,6 +233,27 @@ func (f *FeedbackService) UpdateFeedbackHandler(w http.ResponseWriter, r *http.R
 		})
 	}
 
+	if !f.canViewFeedbackBoard(r.Context(), feedback.BoardId) {
+		return teamPermissionDenied(w)
+	}
+
+	if !isFeedbackAuthor(feedback, requestUser) && !f.canModerateFeedback(r.Context(), feedback.BoardId) {
+		return feedbackPermissionDenied(w)
+	}
+
+	board, err := f.Store.GetBoardById(feedback.BoardId)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !board.AllowsIn(models.ActivityEditFeedback, feedback.Column) {
+		return phaseNotAllowed(w, board)
+	}
+
 	var feedbackRequest models.UpdateFeedbackRequest
 
 	json.NewDecoder(r.Body).Decode(&feedbackRequest)
@@ -213,7 +291,110 @@ func (f *FeedbackService) UpdateFeedbackHandler(w http.ResponseWriter, r *http.R
 	})
 }
 
+// MoveFeedbackHandler moves a feedback to another column of its board, with
+// the same rules as editing it.
+func (f *FeedbackService) MoveFeedbackHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	feedback, err := f.Store.GetFeedbackById(id)
+	if err != nil {
+		log.Println("Error in fetching the feedback", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "feedback not found"},
+		})
+	}
+
+	if !f.canViewFeedbackBoard(r.Context(), feedback.BoardId) {
+		return teamPermissionDenied(w)
+	}
+
+	if !isFeedbackAuthor(feedback, requestUser) && !f.canModerateFeedback(r.Context(), feedback.BoardId) {
+		return feedbackPermissionDenied(w)
+	}
+
+	var moveRequest models.MoveFeedbackRequest
+
+	json.NewDecoder(r.Body).Decode(&moveRequest)
+	structErr := models.ValidateStruct(&moveRequest)
+	if structErr != nil {
+		log.Println("Error in validating the move feedback struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	board, err := f.Store.GetBoardById(feedback.BoardId)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !board.HasColumn(moveRequest.Column) {
+		return invalidFeedbackColumn(w)
+	}
+
+	if !board.Allows(models.ActivityMoveFeedback) {
+		return phaseNotAllowed(w, board)
+	}
+
+	feedback = models.MoveFeedback(feedback, moveRequest.Column)
+
+	newFeedback, store_error := f.Store.UpdateFeedback(*feedback)
+	if store_error != nil {
+		msg := common.AnyToAnyStructField(store_error, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	redisErr := f.RedisClient.Set(newFeedback.Id.String(), newFeedback)
+	if redisErr != nil {
+		log.Println("Error in setting the feedback in redis", redisErr)
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   newFeedback,
+	})
+}
+
+// DeleteFeedbackHandler lets the author delete their feedback. Facilitators
+// of the board and admins can remove anyone's, but have to give a reason
+// that is kept together with a copy of the feedback.
 func (f *FeedbackService) DeleteFeedbackHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
 	id, err := uuid.Parse(mux.Vars(r)["id"])
 
 	if err != nil {
@@ -224,7 +405,62 @@ func (f *FeedbackService) DeleteFeedbackHandler(w http.ResponseWriter, r *http.R
 		})
 	}
 
-	err = f.Store.DeleteFeedback(id)
+	feedback, err := f.Store.GetFeedbackById(id)
+	if err != nil {
+		log.Println("Error in fetching the Feedback:", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Feedback not found"},
+		})
+	}
+
+	if !f.canViewFeedbackBoard(r.Context(), feedback.BoardId) {
+		return teamPermissionDenied(w)
+	}
+
+	board, err := f.Store.GetBoardById(feedback.BoardId)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if isFeedbackAuthor(feedback, requestUser) {
+		if !board.AllowsIn(models.ActivityDeleteFeedback, feedback.Column) {
+			return phaseNotAllowed(w, board)
+		}
+
+		err = f.Store.DeleteFeedback(id)
+	} else if f.canModerateFeedback(r.Context(), feedback.BoardId) {
+		if !board.Allows(models.ActivityModerateFeedback) {
+			return phaseNotAllowed(w, board)
+		}
+
+		var removalRequest models.RemoveFeedbackRequest
+
+		json.NewDecoder(r.Body).Decode(&removalRequest)
+		structErr := models.ValidateStruct(&removalRequest)
+		if structErr != nil {
+			log.Println("Error in validating the feedback removal struct", structErr)
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusBadRequest,
+				Data:   structErr,
+			})
+		}
+
+		defer r.Body.Close()
+
+		removal := models.NewFeedbackRemoval(feedback, requestUser, &removalRequest)
+		err = f.Store.RemoveFeedback(*removal)
+		if err == nil {
+			recordAuditEvent(r, f.Store, models.AuditFeedbackRemoved, models.AuditTargetFeedback, id.String(), feedback, removal)
+		}
+	} else {
+		return feedbackPermissionDenied(w)
+	}
+
 	if err != nil {
 		log.Println("Error in fetching the Feedback:", err)
 		return core.APIResponse(w, &core.Response{
@@ -243,3 +479,77 @@ func (f *FeedbackService) DeleteFeedbackHandler(w http.ResponseWriter, r *http.R
 		Data:   map[string]string{"detail": "Feedback deleted successfully"},
 	})
 }
+
+// GetFeedbackRemovalsHandler lists the feedbacks moderators removed from
+// the board, who removed them and why.
+func (f *FeedbackService) GetFeedbackRemovalsHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	if !f.canViewFeedbackBoard(r.Context(), id) || !f.canModerateFeedback(r.Context(), id) {
+		return teamPermissionDenied(w)
+	}
+
+	removals, err := f.Store.GetFeedbackRemovals(id)
+	if err != nil {
+		log.Println("Error in fetching the feedback removals", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to fetch feedback removals"},
+		})
+	}
+
+	return core.ListAPIResponse(w, &core.ListAPI{
+		Status: http.StatusOK,
+		Result: &core.ListAPIResponseBody{
+			Count:  len(removals),
+			Result: removals,
+		},
+	})
+}
+
+func isFeedbackAuthor(feedback *models.Feedback, user *models.CreateUserResponse) bool {
+	return feedback.CreatedById != uuid.Nil && feedback.CreatedById == user.Id
+}
+
+// canModerateFeedback reports whether the request user may change or remove
+// feedback of others on the board, as an admin or a facilitator of it.
+func (f *FeedbackService) canModerateFeedback(ctx context.Context, boardId uuid.UUID) bool {
+	if middlewares.ContextHasPermission(ctx, middlewares.ModerateFeedbacks) {
+		return true
+	}
+
+	return middlewares.ContextHasBoardPermission(ctx, requestBoardMember(ctx, f.Store, boardId), middlewares.ModerateFeedbacks)
+}
+
+func feedbackPermissionDenied(w http.ResponseWriter) error {
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusForbidden,
+		Data:   &core.APIError{Detail: "Only the author, a facilitator of the board or an admin can change this feedback"},
+	})
+}
+
+func invalidFeedbackColumn(w http.ResponseWriter) error {
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusBadRequest,
+		Data:   &core.APIError{Detail: "Column is not one of the columns of the board"},
+	})
+}
+
+// canViewFeedbackBoard reports whether the request user may see the board,
+// and so the feedbacks on it.
+func (f *FeedbackService) canViewFeedbackBoard(ctx context.Context, boardId uuid.UUID) bool {
+	board, err := f.Store.GetBoardById(boardId)
+	if err != nil {
+		log.Println("Error in fetching the board of the feedback", err)
+		return false
+	}
+
+	return canViewBoard(ctx, f.Store, board)
+}
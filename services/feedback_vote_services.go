@@ -0,0 +1,164 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// participantFeedback returns the feedback of the request path and its board
+// when the request user takes part in the board and its phase allows the
+// activity, writing the error response otherwise.
+func (f *FeedbackService) participantFeedback(w http.ResponseWriter, r *http.Request, activity models.BoardActivity) (*models.Feedback, *models.Board, error) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return nil, nil, core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	feedback, err := f.Store.GetFeedbackById(id)
+	if err != nil {
+		log.Println("Error in fetching the feedback", err)
+		return nil, nil, core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Feedback not found"},
+		})
+	}
+
+	board, err := f.Store.GetBoardById(feedback.BoardId)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return nil, nil, core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !canViewBoard(r.Context(), f.Store, board) {
+		return nil, nil, teamPermissionDenied(w)
+	}
+
+	if !middlewares.ContextHasBoardPermission(r.Context(), requestBoardMember(r.Context(), f.Store, board.Id), middlewares.CreateFeedbacks) {
+		return nil, nil, core.APIResponse(w, &core.Response{
+			Status: http.StatusForbidden,
+			Data:   &core.APIError{Detail: "Only participants of the board can do this"},
+		})
+	}
+
+	if !board.Allows(activity) {
+		return nil, nil, phaseNotAllowed(w, board)
+	}
+
+	return feedback, board, nil
+}
+
+// feedbackResponse answers with the feedback as it is stored now, with its
+// votes and group, and refreshes the cached copy.
+func (f *FeedbackService) feedbackResponse(w http.ResponseWriter, status int, id uuid.UUID) error {
+	feedback, err := f.Store.GetFeedbackById(id)
+	if err != nil {
+		log.Println("Error in fetching the feedback", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to fetch the feedback"},
+		})
+	}
+
+	redisErr := f.RedisClient.Set(feedback.Id.String(), feedback)
+	if redisErr != nil {
+		log.Println("Error in setting the feedback in redis", redisErr)
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: status,
+		Data:   feedback,
+	})
+}
+
+// VoteFeedbackHandler gives one vote of the request user to the feedback
+// while the board is voting. They can give as many votes as the board
+// settings allow, on one feedback or spread over several.
+func (f *FeedbackService) VoteFeedbackHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	feedback, board, err := f.participantFeedback(w, r, models.ActivityVote)
+	if feedback == nil {
+		return err
+	}
+
+	vote := models.NewFeedbackVote(feedback, requestUser.Id)
+	limit := board.Settings.VotesPerParticipant
+
+	storeErr := f.Store.CreateFeedbackVote(*vote, limit)
+	if errors.Is(storeErr, sql.ErrNoRows) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "You have used all " + strconv.Itoa(limit) + " of your votes on this board"},
+		})
+	}
+
+	if storeErr != nil {
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	return f.feedbackResponse(w, http.StatusCreated, feedback.Id)
+}
+
+// UnvoteFeedbackHandler takes back one vote of the request user on the
+// feedback, which they can then give again.
+func (f *FeedbackService) UnvoteFeedbackHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	feedback, _, err := f.participantFeedback(w, r, models.ActivityVote)
+	if feedback == nil {
+		return err
+	}
+
+	storeErr := f.Store.DeleteFeedbackVote(feedback.Id, requestUser.Id)
+	if errors.Is(storeErr, sql.ErrNoRows) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "You have not voted on this feedback"},
+		})
+	}
+
+	if storeErr != nil {
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	return f.feedbackResponse(w, http.StatusOK, feedback.Id)
+}
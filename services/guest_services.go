@@ -0,0 +1,383 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/config"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	GUEST_JOIN_ATTEMPTS_PREFIX = "guest_join_attempts:"
+	GUEST_JOIN_LOCKOUT_PREFIX  = "guest_join_lockout:"
+)
+
+func boardJoinURL() string {
+	url := os.Getenv("BOARD_JOIN_URL")
+	if url == "" {
+		url = fmt.Sprintf("http://localhost:%s/join/", os.Getenv("APPLICATION_PORT"))
+	}
+	return url
+}
+
+// guestJoinLocked reports whether the ip address guessed too many pins or
+// tokens, pins are short enough to be worth throttling.
+func guestJoinLocked(redisClient storages.RedisStoreInterface, ipAddress string) bool {
+	locked, err := redisClient.Exists(GUEST_JOIN_LOCKOUT_PREFIX + ipAddress)
+	if err != nil {
+		log.Println("Error while checking the guest join lockout", err)
+		return false
+	}
+
+	return locked
+}
+
+func recordFailedGuestJoin(redisClient storages.RedisStoreInterface, ipAddress string) {
+	window := config.EnvDuration("GUEST_JOIN_ATTEMPT_WINDOW", time.Hour)
+
+	failures, err := redisClient.Incr(GUEST_JOIN_ATTEMPTS_PREFIX+ipAddress, window)
+	if err != nil {
+		log.Println("Error while counting the failed guest join", err)
+		return
+	}
+
+	if failures < int64(config.EnvInt("GUEST_JOIN_MAX_ATTEMPTS", 10)) {
+		return
+	}
+
+	if err := redisClient.SetWithExpiry(GUEST_JOIN_LOCKOUT_PREFIX+ipAddress, true, window); err != nil {
+		log.Println("Error while locking out the guest join", err)
+	}
+}
+
+// CreateBoardJoinLinkHandler hands out a link and a pin that let people
+// without an account join the board as guests until the link expires.
+func (b *BoardService) CreateBoardJoinLinkHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	board, err := b.Store.GetBoardById(id)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !canManageBoardMembers(r.Context(), b.Store, board) {
+		return teamPermissionDenied(w)
+	}
+
+	if board.IsClosed() {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "This board is closed"},
+		})
+	}
+
+	var linkRequest models.CreateBoardJoinLinkRequest
+
+	json.NewDecoder(r.Body).Decode(&linkRequest)
+	structErr := models.ValidateStruct(&linkRequest)
+	if structErr != nil {
+		log.Println("Error in validating the board join link struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	rawToken, err := common.GenerateSecureToken(32)
+	if err != nil {
+		log.Println("Error while generating the board join token", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while creating the join link, Try again later"},
+		})
+	}
+
+	pin, err := models.GenerateBoardJoinPin()
+	if err != nil {
+		log.Println("Error while generating the board join pin", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while creating the join link, Try again later"},
+		})
+	}
+
+	link := models.NewBoardJoinLink(board.Id, requestUser.Id, common.HashToken(rawToken), common.HashToken(pin), &linkRequest)
+	err = b.Store.CreateBoardJoinLink(*link)
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	recordAuditEvent(r, b.Store, models.AuditBoardJoinLinkCreated, models.AuditTargetBoard, board.Id.String(), nil, link)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusCreated,
+		Data: &models.CreateBoardJoinLinkResponse{
+			BoardJoinLink: link,
+			Token:         rawToken,
+			Pin:           pin,
+			URL:           fmt.Sprintf("%s?token=%s", boardJoinURL(), rawToken),
+		},
+	})
+}
+
+func (b *BoardService) RevokeBoardJoinLinkHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	linkId, err := uuid.Parse(mux.Vars(r)["link_id"])
+	if err != nil {
+		log.Println("Error in parsing the link id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	board, err := b.Store.GetBoardById(id)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !canManageBoardMembers(r.Context(), b.Store, board) {
+		return teamPermissionDenied(w)
+	}
+
+	err = b.Store.RevokeBoardJoinLink(linkId, board.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusNotFound,
+			Data:   &core.APIError{Detail: "Join link not found"},
+		})
+	}
+
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	recordAuditEvent(r, b.Store, models.AuditBoardJoinLinkRevoked, models.AuditTargetBoard, board.Id.String(), map[string]string{"join_link_id": linkId.String()}, nil)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   map[string]string{"detail": "Join link revoked successfully"},
+	})
+}
+
+// JoinBoardHandler creates a temporary guest for whoever has the token or
+// the pin of a join link. The guest token only works on that board and
+// expires with the guest, at the latest when the link does.
+func (b *BoardService) JoinBoardHandler(w http.ResponseWriter, r *http.Request) error {
+	var joinRequest models.JoinBoardRequest
+
+	json.NewDecoder(r.Body).Decode(&joinRequest)
+	structErr := models.ValidateStruct(&joinRequest)
+	if structErr != nil {
+		log.Println("Error in validating the join board struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	ipAddress := core.ClientIP(r)
+	if guestJoinLocked(b.RedisClient, ipAddress) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusTooManyRequests,
+			Data:   &core.APIError{Detail: "Too many failed attempts to join, try again later"},
+		})
+	}
+
+	var link *models.BoardJoinLink
+	var err error
+	if joinRequest.Token != "" {
+		link, err = b.Store.GetBoardJoinLinkByHash(common.HashToken(joinRequest.Token))
+	} else {
+		link, err = b.Store.GetBoardJoinLinkByPin(common.HashToken(joinRequest.Pin))
+	}
+
+	if err != nil || !link.IsValid() {
+		log.Println("Invalid board join link", err)
+		recordFailedGuestJoin(b.RedisClient, ipAddress)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Invalid or expired join link"},
+		})
+	}
+
+	board, err := b.Store.GetBoardById(link.BoardId)
+	if err != nil || board.IsClosed() {
+		log.Println("Board of the join link is not open", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "This board is closed"},
+		})
+	}
+
+	now := time.Now().UTC()
+	if _, err := b.Store.DeleteExpiredGuestUsers(now); err != nil {
+		log.Println("Error while deleting the expired guest users", err)
+	}
+
+	guest, err := models.NewGuestUser(joinRequest.DisplayName)
+	if err != nil {
+		log.Println("Error while creating the guest user", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while joining the board, Try again later"},
+		})
+	}
+
+	expiresAt := now.Add(middlewares.GUEST_TOKEN_LIFETIME)
+	if link.ExpiresAt.Before(expiresAt) {
+		expiresAt = link.ExpiresAt
+	}
+
+	err = b.Store.CreateGuestUser(*guest, board.Id, expiresAt)
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	token, err := middlewares.GenerateGuestToken(guest.Id.String(), guest.Email, board.Id.String(), expiresAt.Sub(now))
+	if err != nil {
+		log.Println("Error while generating the guest token", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while joining the board, Try again later"},
+		})
+	}
+
+	userResponse := models.CreateUserResponse{}
+	common.AnyToAnyStructField(guest, &userResponse)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusCreated,
+		Data: &models.JoinBoardResponse{
+			Token:     token,
+			ExpiresAt: expiresAt,
+			BoardId:   board.Id,
+			User:      &userResponse,
+		},
+	})
+}
+
+// CloseBoardHandler ends the retro, its join links stop working and the
+// guests who joined it are deleted. Their feedback stays on the board.
+func (b *BoardService) CloseBoardHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	board, err := b.Store.GetBoardById(id)
+	if err != nil {
+		log.Println("Error in fetching the Board", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Board not found"},
+		})
+	}
+
+	if !canManageBoardMembers(r.Context(), b.Store, board) {
+		return teamPermissionDenied(w)
+	}
+
+	return b.closeBoard(w, r, board)
+}
+
+// closeBoard closes the board for CloseBoardHandler and for moving a board
+// to the closed phase.
+func (b *BoardService) closeBoard(w http.ResponseWriter, r *http.Request, board *models.Board) error {
+	closedAt := time.Now().UTC()
+	err := b.Store.CloseBoard(board.Id, closedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "This board is already closed"},
+		})
+	}
+
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	redisErr := b.RedisClient.Del(board.Id.String())
+	if redisErr != nil {
+		log.Println("Error in deleting the board from redis", redisErr)
+	}
+
+	before := *board
+	board.ClosedAt = &closedAt
+	board.Phase = models.PhaseClosed
+	recordAuditEvent(r, b.Store, models.AuditBoardClosed, models.AuditTargetBoard, board.Id.String(), before, board)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   board,
+	})
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/config"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+)
+
+const (
+	LOGIN_ATTEMPTS_PREFIX = "login_attempts:"
+	LOGIN_LOCKOUT_PREFIX  = "login_lockout:"
+)
+
+type LoginThrottleSettings struct {
+	MaxUserAttempts int
+	MaxIPAttempts   int
+	BaseLockout     time.Duration
+	MaxLockout      time.Duration
+	AttemptWindow   time.Duration
+}
+
+func NewLoginThrottleSettings() LoginThrottleSettings {
+	return LoginThrottleSettings{
+		MaxUserAttempts: config.EnvInt("LOGIN_MAX_ATTEMPTS_PER_USER", 5),
+		MaxIPAttempts:   config.EnvInt("LOGIN_MAX_ATTEMPTS_PER_IP", 20),
+		BaseLockout:     config.EnvDuration("LOGIN_LOCKOUT_BASE", time.Minute),
+		MaxLockout:      config.EnvDuration("LOGIN_LOCKOUT_MAX", time.Hour),
+		AttemptWindow:   config.EnvDuration("LOGIN_ATTEMPT_WINDOW", time.Hour*24),
+	}
+}
+
+// LockoutDuration doubles the lockout for every failure past the allowed
+// attempts, capped at MaxLockout. Zero means no lockout yet.
+func (s LoginThrottleSettings) LockoutDuration(failures int64, maxAttempts int) time.Duration {
+	if failures < int64(maxAttempts) {
+		return 0
+	}
+
+	exponent := float64(failures - int64(maxAttempts))
+	lockout := time.Duration(float64(s.BaseLockout) * math.Pow(2, exponent))
+	if lockout > s.MaxLockout || lockout <= 0 {
+		return s.MaxLockout
+	}
+
+	return lockout
+}
+
+type loginKey struct {
+	lockoutType models.LockoutType
+	value       string
+	maxAttempts int
+}
+
+func loginKeys(settings LoginThrottleSettings, username, ipAddress string) []loginKey {
+	return []loginKey{
+		{lockoutType: models.UsernameLockout, value: username, maxAttempts: settings.MaxUserAttempts},
+		{lockoutType: models.IPLockout, value: ipAddress, maxAttempts: settings.MaxIPAttempts},
+	}
+}
+
+// mfaKeys count wrong codes apart from wrong passwords, otherwise logging in
+// with the password again would start the codes over.
+func mfaKeys(settings LoginThrottleSettings, username, ipAddress string) []loginKey {
+	return []loginKey{
+		{lockoutType: models.MFALockout, value: username, maxAttempts: settings.MaxUserAttempts},
+		{lockoutType: models.IPLockout, value: ipAddress, maxAttempts: settings.MaxIPAttempts},
+	}
+}
+
+func (k loginKey) attemptsKey() string {
+	return LOGIN_ATTEMPTS_PREFIX + string(k.lockoutType) + ":" + k.value
+}
+
+func (k loginKey) lockoutKey() string {
+	return LOGIN_LOCKOUT_PREFIX + string(k.lockoutType) + ":" + k.value
+}
+
+// loginLockedFor returns how long the username or the ip address is still
+// locked out, zero when neither is.
+func loginLockedFor(redisClient storages.RedisStoreInterface, username, ipAddress string) time.Duration {
+	return lockedFor(redisClient, loginKeys(NewLoginThrottleSettings(), username, ipAddress))
+}
+
+// mfaLockedFor is loginLockedFor for the second factor, which is also locked
+// out by too many wrong codes.
+func mfaLockedFor(redisClient storages.RedisStoreInterface, username, ipAddress string) time.Duration {
+	settings := NewLoginThrottleSettings()
+	return lockedFor(redisClient, append(loginKeys(settings, username, ipAddress), mfaKeys(settings, username, ipAddress)[0]))
+}
+
+func lockedFor(redisClient storages.RedisStoreInterface, keys []loginKey) time.Duration {
+	var retryAfter time.Duration
+
+	for _, key := range keys {
+		locked, err := redisClient.Exists(key.lockoutKey())
+		if err != nil {
+			log.Println("Error while checking the login lockout", err)
+			continue
+		}
+
+		if !locked {
+			continue
+		}
+
+		ttl, _ := redisClient.TTL(key.lockoutKey())
+		if ttl <= 0 {
+			ttl = time.Second
+		}
+
+		if ttl > retryAfter {
+			retryAfter = ttl
+		}
+	}
+
+	return retryAfter
+}
+
+// recordFailedLogin counts the failure against both the username and the ip
+// address and locks out whichever went past its limit. Usernames that do
+// not exist are counted as well, so lockouts do not reveal valid accounts.
+func recordFailedLogin(store storages.Storage, redisClient storages.RedisStoreInterface, username, ipAddress string) {
+	settings := NewLoginThrottleSettings()
+	recordFailures(store, redisClient, settings, loginKeys(settings, username, ipAddress), username, ipAddress)
+}
+
+// recordFailedMFA counts a wrong code of the second factor.
+func recordFailedMFA(store storages.Storage, redisClient storages.RedisStoreInterface, username, ipAddress string) {
+	settings := NewLoginThrottleSettings()
+	recordFailures(store, redisClient, settings, mfaKeys(settings, username, ipAddress), username, ipAddress)
+}
+
+func recordFailures(store storages.Storage, redisClient storages.RedisStoreInterface, settings LoginThrottleSettings, keys []loginKey, username, ipAddress string) {
+	for _, key := range keys {
+		failures, err := redisClient.Incr(key.attemptsKey(), settings.AttemptWindow)
+		if err != nil {
+			log.Println("Error while counting the failed login", err)
+			continue
+		}
+
+		lockout := settings.LockoutDuration(failures, key.maxAttempts)
+		if lockout == 0 {
+			continue
+		}
+
+		err = redisClient.SetWithExpiry(key.lockoutKey(), true, lockout)
+		if err != nil {
+			log.Println("Error while locking out the login", err)
+			continue
+		}
+
+		log.Printf("Login locked out for %s %s for %s", key.lockoutType, key.value, lockout)
+		lockoutRecord := models.NewAccountLockout(username, ipAddress, key.lockoutType, int(failures), lockout)
+		if err := store.CreateAccountLockout(*lockoutRecord); err != nil {
+			log.Println("Error while recording the account lockout", err)
+		}
+	}
+}
+
+// clearFailedLogins forgets the failed passwords and codes of the username,
+// only once a login is complete or an admin unlocks the user.
+func clearFailedLogins(redisClient storages.RedisStoreInterface, username string) {
+	for _, lockoutType := range []models.LockoutType{models.UsernameLockout, models.MFALockout} {
+		key := loginKey{lockoutType: lockoutType, value: username}
+		redisClient.Del(key.attemptsKey())
+		redisClient.Del(key.lockoutKey())
+	}
+}
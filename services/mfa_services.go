@@ -0,0 +1,359 @@
+package services
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+func mfaIssuer() string {
+	issuer := os.Getenv("MFA_ISSUER")
+	if issuer == "" {
+		issuer = "Reetro"
+	}
+	return issuer
+}
+
+// normalizeRecoveryCode lets users type recovery codes with or without the
+// dash and in any case.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func generateRecoveryCodes(userId uuid.UUID) ([]string, []models.MFARecoveryCode, error) {
+	codes := make([]string, 0, models.MFA_RECOVERY_CODE_COUNT)
+	records := make([]models.MFARecoveryCode, 0, models.MFA_RECOVERY_CODE_COUNT)
+
+	for i := 0; i < models.MFA_RECOVERY_CODE_COUNT; i++ {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(b))[:10]
+		codes = append(codes, code[:5]+"-"+code[5:])
+		records = append(records, *models.NewMFARecoveryCode(userId, common.HashToken(code)))
+	}
+
+	return codes, records, nil
+}
+
+// loginResponse finishes a successful first factor. Users with two-factor
+// authentication get a short lived mfa pending token instead of the tokens,
+// the failed attempts of the others are forgotten as their login is done.
+func (u *UserService) loginResponse(w http.ResponseWriter, r *http.Request, user *models.User) error {
+	mfa, err := u.Store.GetUserMFA(user.Id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Println("Error in fetching the user mfa", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while logging in"},
+		})
+	}
+
+	if err == nil && mfa.Enabled {
+		mfaToken, tokenErr := middlewares.GenerateMFAPendingToken(user.Id.String(), user.Email)
+		if tokenErr != nil {
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusBadRequest,
+				Data:   &core.APIError{Detail: "Error While Generating Token"},
+			})
+		}
+
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusOK,
+			Data:   &core.MFARequiredResponse{MFARequired: true, MFAToken: mfaToken},
+		})
+	}
+
+	clearFailedLogins(u.RedisClient, user.Username)
+
+	tokens, tokenErr := u.startSession(r, user.Id, user.Email)
+	if tokenErr != nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Error While Generating Token"},
+		})
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   tokens,
+	})
+}
+
+// verifyMFACode accepts a code from the authenticator app, refusing one that
+// was already used, or an unused recovery code.
+func (u *UserService) verifyMFACode(mfa *models.UserMFA, code string) bool {
+	step, ok := common.ValidateTOTP(mfa.Secret, strings.TrimSpace(code), time.Now())
+	if ok {
+		err := u.Store.UseMFAStep(mfa.UserId, step)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error in saving the user mfa", err)
+		}
+
+		return err == nil
+	}
+
+	return u.Store.UseMFARecoveryCode(mfa.UserId, common.HashToken(normalizeRecoveryCode(code))) == nil
+}
+
+func (u *UserService) EnrollMFAHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	if _, scoped := middlewares.TokenScopesFromContext(r.Context()); scoped {
+		return personalAccessTokenForbidden(w)
+	}
+
+	existing, err := u.Store.GetUserMFA(requestUser.Id)
+	if err == nil && existing.Enabled {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusConflict,
+			Data:   &core.APIError{Detail: "Two-factor authentication is already enabled"},
+		})
+	}
+
+	secret, err := common.GenerateTOTPSecret()
+	if err != nil {
+		log.Println("Error while generating the totp secret", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while enrolling two-factor authentication"},
+		})
+	}
+
+	err = u.Store.SaveUserMFA(*models.NewUserMFA(requestUser.Id, secret))
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data: &models.MFAEnrollmentResponse{
+			Secret:     secret,
+			OTPAuthURI: common.TOTPURI(mfaIssuer(), requestUser.Email, secret),
+		},
+	})
+}
+
+func (u *UserService) ConfirmMFAHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	if _, scoped := middlewares.TokenScopesFromContext(r.Context()); scoped {
+		return personalAccessTokenForbidden(w)
+	}
+
+	var payload MFACodePayload
+
+	json.NewDecoder(r.Body).Decode(&payload)
+	structErr := models.ValidateStruct(&payload)
+	if structErr != nil {
+		log.Println("Error in validating the mfa payload", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	mfa, err := u.Store.GetUserMFA(requestUser.Id)
+	if err != nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Two-factor enrollment has not been started"},
+		})
+	}
+
+	if mfa.Enabled {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusConflict,
+			Data:   &core.APIError{Detail: "Two-factor authentication is already enabled"},
+		})
+	}
+
+	step, valid := common.ValidateTOTP(mfa.Secret, strings.TrimSpace(payload.Code), time.Now())
+	if !valid {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Invalid code"},
+		})
+	}
+
+	codes, records, err := generateRecoveryCodes(requestUser.Id)
+	if err != nil {
+		log.Println("Error while generating the recovery codes", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while enabling two-factor authentication"},
+		})
+	}
+
+	err = u.Store.ReplaceMFARecoveryCodes(requestUser.Id, records)
+	if err == nil {
+		mfa.Confirm(step)
+		err = u.Store.SaveUserMFA(*mfa)
+	}
+
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   &models.MFARecoveryCodesResponse{RecoveryCodes: codes},
+	})
+}
+
+func (u *UserService) LoginMFAHandler(w http.ResponseWriter, r *http.Request) error {
+	var payload MFALoginPayload
+
+	json.NewDecoder(r.Body).Decode(&payload)
+	structErr := models.ValidateStruct(&payload)
+	if structErr != nil {
+		log.Println("Error in validating the mfa login payload", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	claims, err := middlewares.ValidateTokenOfType(payload.MFAToken, middlewares.MFA_PENDING_TOKEN)
+	if err != nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Invalid token"},
+		})
+	}
+
+	revoked, err := middlewares.IsTokenRevoked(u.RedisClient, middlewares.ClaimString(claims, "jti"))
+	if err != nil || revoked {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Token has been revoked"},
+		})
+	}
+
+	id, _ := uuid.Parse(middlewares.ClaimString(claims, "id"))
+	user, err := u.Store.GetUserById(id)
+	if err != nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Invalid token"},
+		})
+	}
+
+	ipAddress := core.ClientIP(r)
+	retryAfter := mfaLockedFor(u.RedisClient, user.Username, ipAddress)
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusTooManyRequests,
+			Data:   &core.APIError{Detail: "Too many failed login attempts, try again later"},
+		})
+	}
+
+	mfa, err := u.Store.GetUserMFA(user.Id)
+	if err != nil || !mfa.Enabled || !u.verifyMFACode(mfa, payload.Code) {
+		recordFailedMFA(u.Store, u.RedisClient, user.Username, ipAddress)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Invalid code"},
+		})
+	}
+
+	clearFailedLogins(u.RedisClient, user.Username)
+
+	if err := middlewares.RevokeToken(u.RedisClient, claims); err != nil {
+		log.Println("Error while revoking the mfa pending token", err)
+	}
+
+	tokens, tokenErr := u.startSession(r, user.Id, user.Email)
+	if tokenErr != nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Error While Generating Token"},
+		})
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   tokens,
+	})
+}
+
+func (u *UserService) ResetUserMFAHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	_, err = u.Store.GetUserById(id)
+	if err != nil {
+		log.Println("Error in fetching the user", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "User not found"},
+		})
+	}
+
+	err = u.Store.DeleteUserMFA(id)
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	recordAuditEvent(r, u.Store, models.AuditUserMFAReset, models.AuditTargetUser, id.String(), nil, nil)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   &APISuccessResponse{Detail: "Two-factor authentication reset successfully"},
+	})
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/config"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	"github.com/gorilla/mux"
+)
+
+const OIDC_STATE_PREFIX = "oidc_state:"
+
+// oidcLoginState is kept in Redis between sending the user to the provider
+// and the callback, the code verifier never leaves the server.
+type oidcLoginState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+func oidcStateLifetime() time.Duration {
+	return config.EnvDuration("OIDC_STATE_LIFETIME", 10*time.Minute)
+}
+
+func unknownOIDCProvider(w http.ResponseWriter) error {
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusNotFound,
+		Data:   &core.APIError{Detail: "Unknown login provider"},
+	})
+}
+
+// GetOIDCProvidersHandler lists the names of the providers users can log in
+// with, for the login page.
+func (u *UserService) GetOIDCProvidersHandler(w http.ResponseWriter, r *http.Request) error {
+	names := make([]string, 0, len(u.OIDCProviders))
+	for name := range u.OIDCProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	providers := make([]map[string]string, 0, len(names))
+	for _, name := range names {
+		providers = append(providers, map[string]string{"name": name})
+	}
+
+	return core.ListAPIResponse(w, &core.ListAPI{
+		Status: http.StatusOK,
+		Result: &core.ListAPIResponseBody{
+			Count:  len(providers),
+			Result: providers,
+		},
+	})
+}
+
+// OIDCLoginHandler starts the authorization code flow, the client sends the
+// user to the returned URL and posts the code it gets back to the callback.
+func (u *UserService) OIDCLoginHandler(w http.ResponseWriter, r *http.Request) error {
+	name := mux.Vars(r)["provider"]
+	provider, ok := u.OIDCProviders[name]
+	if !ok {
+		return unknownOIDCProvider(w)
+	}
+
+	state, stateErr := common.GenerateSecureToken(32)
+	nonce, nonceErr := common.GenerateSecureToken(32)
+	codeVerifier, verifierErr := middlewares.NewPKCEVerifier()
+	if err := errors.Join(stateErr, nonceErr, verifierErr); err != nil {
+		log.Println("Error while generating the oidc login state", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while starting the login, Try again later"},
+		})
+	}
+
+	authorizationURL, err := provider.AuthorizationURL(state, nonce, codeVerifier)
+	if err != nil {
+		log.Println("Error in fetching the oidc discovery document", name, err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadGateway,
+			Data:   &core.APIError{Detail: "Unable to reach the login provider"},
+		})
+	}
+
+	loginState := oidcLoginState{Provider: name, Nonce: nonce, CodeVerifier: codeVerifier}
+	err = u.RedisClient.SetWithExpiry(OIDC_STATE_PREFIX+state, loginState, oidcStateLifetime())
+	if err != nil {
+		log.Println("Error while storing the oidc login state", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while starting the login, Try again later"},
+		})
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   &core.AuthorizationURLResponse{AuthorizationURL: authorizationURL, State: state},
+	})
+}
+
+// OIDCCallbackHandler redeems the code for a verified identity and logs in
+// the user linked to its subject, linking one on first login. When the
+// provider maps roles the role of the user follows its claims.
+func (u *UserService) OIDCCallbackHandler(w http.ResponseWriter, r *http.Request) error {
+	name := mux.Vars(r)["provider"]
+	provider, ok := u.OIDCProviders[name]
+	if !ok {
+		return unknownOIDCProvider(w)
+	}
+
+	var payload OIDCCallbackPayload
+
+	json.NewDecoder(r.Body).Decode(&payload)
+	structErr := models.ValidateStruct(payload)
+	if structErr != nil {
+		log.Println("Error in validating the oidc callback payload", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	// The state is single use, reading and deleting it in one step keeps a
+	// replayed callback from logging in again.
+	var cacheState oidcLoginState
+	cached, _ := u.RedisClient.GetDel(OIDC_STATE_PREFIX+payload.State, cacheState)
+	if cached == nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Invalid or expired login state"},
+		})
+	}
+
+	loginState := common.AnyToAnyStructField(cached, &oidcLoginState{}).(*oidcLoginState)
+	if loginState.Provider != name {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Invalid or expired login state"},
+		})
+	}
+
+	identity, err := provider.Exchange(payload.Code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		log.Println("Error in exchanging the oidc authorization code", name, err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to log in with the provider"},
+		})
+	}
+
+	role := oidcRole(provider, identity)
+
+	user, dbError := u.Store.GetUserByIdentity(name, identity.Subject)
+	if errors.Is(dbError, sql.ErrNoRows) {
+		return u.linkOIDCIdentity(w, r, name, identity, role)
+	}
+
+	if dbError != nil {
+		log.Println("Error in fetching the user", dbError)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while Fetching User"},
+		})
+	}
+
+	if role != "" && role != user.UserType {
+		u.syncOIDCRole(r, user, role)
+	}
+
+	return u.loginResponse(w, r, user)
+}
+
+// oidcRole is the role the claims of the provider grant. When the provider
+// maps roles, users whose groups no longer map to one fall back to the
+// default role. It is empty when the provider does not map roles, the role
+// of the user is then managed here.
+func oidcRole(provider *middlewares.OIDCProvider, identity *middlewares.OIDCIdentity) models.Role {
+	if provider.Config.RoleClaim == "" || identity.Role != "" {
+		return identity.Role
+	}
+
+	return models.GuestUser
+}
+
+// linkOIDCIdentity handles the first login with a subject of the provider,
+// Google Sign-In included. A new account is created for an unknown email. An
+// existing account is only linked when its email was verified, otherwise
+// whoever signed up with the address without owning it would get the account
+// of the provider user.
+func (u *UserService) linkOIDCIdentity(w http.ResponseWriter, r *http.Request, provider string, identity *middlewares.OIDCIdentity, role models.Role) error {
+	user, dbError := u.Store.VerifyUserByEmail(identity.Email)
+	if errors.Is(dbError, sql.ErrNoRows) {
+		if role == "" {
+			role = models.GuestUser
+		}
+
+		newUser, userErr := models.NewExternalUser(identity.Email, identity.FirstName, identity.LastName, role)
+		if userErr != nil {
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusInternalServerError,
+				Data:   &core.APIError{Detail: "Error while creating the user"},
+			})
+		}
+
+		createdUser, storeErr := u.Store.CreateUser(*newUser)
+		if storeErr != nil {
+			msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusBadRequest,
+				Data:   msg,
+			})
+		}
+
+		user, role = &createdUser, ""
+	} else if dbError != nil {
+		log.Println("Error in fetching the user", dbError)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while Fetching User"},
+		})
+	} else if !user.EmailVerified {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusConflict,
+			Data:   &core.APIError{Detail: "An account with this email exists, verify its email before logging in with the provider"},
+		})
+	}
+
+	storeErr := u.Store.CreateUserIdentity(*models.NewUserIdentity(provider, identity.Subject, user.Id))
+	if storages.IsUniqueViolation(storeErr) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusConflict,
+			Data:   &core.APIError{Detail: "The account is already linked to another user of the provider"},
+		})
+	}
+
+	if storeErr != nil {
+		log.Println("Error in linking the user to the oidc subject", storeErr)
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	if role != "" && role != user.UserType {
+		u.syncOIDCRole(r, user, role)
+	}
+
+	return u.loginResponse(w, r, user)
+}
+
+// syncOIDCRole applies the role mapped from the claims of the provider, a
+// failure is logged and the user logs in with the role they had.
+func (u *UserService) syncOIDCRole(r *http.Request, user *models.User, role models.Role) {
+	before := common.AnyToAnyStructField(user, &models.CreateUserResponse{}).(*models.CreateUserResponse)
+
+	after := *before
+	after.UserType = role
+	after.ModifiedAt = time.Now().UTC()
+
+	if _, err := u.Store.UpdateUser(after); err != nil {
+		log.Println("Error in updating the role of the user from the oidc claims", err)
+		return
+	}
+
+	user.UserType = role
+	recordAuditEvent(r, u.Store, models.AuditUserRoleChanged, models.AuditTargetUser, user.Id.String(), before, &after)
+
+	if err := u.RedisClient.Del(user.Id.String()); err != nil {
+		log.Println("Error in deleting the user from redis", err)
+	}
+}
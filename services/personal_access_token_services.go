@@ -0,0 +1,177 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// Personal access tokens can not manage tokens or two-factor settings, a
+// leaked token must not be able to mint new credentials.
+func personalAccessTokenForbidden(w http.ResponseWriter) error {
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusForbidden,
+		Data:   &core.APIError{Detail: "Personal access tokens can not be used for this action"},
+	})
+}
+
+func (u *UserService) GetPersonalAccessTokensHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	if _, scoped := middlewares.TokenScopesFromContext(r.Context()); scoped {
+		return personalAccessTokenForbidden(w)
+	}
+
+	tokens, err := u.Store.GetPersonalAccessTokensByUserId(requestUser.Id)
+	if err != nil {
+		log.Println("Error in fetching the personal access tokens", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to fetch personal access tokens"},
+		})
+	}
+
+	return core.ListAPIResponse(w, &core.ListAPI{
+		Status: http.StatusOK,
+		Result: &core.ListAPIResponseBody{
+			Count:  len(tokens),
+			Result: tokens,
+		},
+	})
+}
+
+func (u *UserService) CreatePersonalAccessTokenHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	if _, scoped := middlewares.TokenScopesFromContext(r.Context()); scoped {
+		return personalAccessTokenForbidden(w)
+	}
+
+	var tokenRequest models.CreatePersonalAccessTokenRequest
+
+	json.NewDecoder(r.Body).Decode(&tokenRequest)
+	structErr := models.ValidateStruct(&tokenRequest)
+	if structErr != nil {
+		log.Println("Error in validating the personal access token struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	// Permissions that come with a team or board role can be scoped as well,
+	// the token only works with them where the user holds that role.
+	for _, scope := range tokenRequest.Scopes {
+		permission := middlewares.Permission(scope)
+		allowed := middlewares.HasPermission(requestUser.UserType, permission) ||
+			middlewares.HasTeamPermission(models.TeamRoleOwner, permission) ||
+			middlewares.HasBoardPermission(models.BoardFacilitator, permission)
+		if !middlewares.IsKnownPermission(scope) || !allowed {
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusBadRequest,
+				Data:   &core.APIError{Detail: fmt.Sprintf("Scope %q is unknown or not allowed for your role", scope)},
+			})
+		}
+	}
+
+	tokenValue, err := common.GenerateSecureToken(32)
+	if err != nil {
+		log.Println("Error while generating the personal access token", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while creating the personal access token"},
+		})
+	}
+
+	tokenValue = models.PERSONAL_ACCESS_TOKEN_PREFIX + tokenValue
+	token := models.NewPersonalAccessToken(requestUser.Id, common.HashToken(tokenValue), &tokenRequest)
+
+	err = u.Store.CreatePersonalAccessToken(*token)
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	recordAuditEvent(r, u.Store, models.AuditPersonalAccessTokenCreated, models.AuditTargetPersonalAccessToken, token.Id.String(), nil, token)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusCreated,
+		Data:   &models.CreatePersonalAccessTokenResponse{PersonalAccessToken: token, Token: tokenValue},
+	})
+}
+
+func (u *UserService) RevokePersonalAccessTokenHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	if _, scoped := middlewares.TokenScopesFromContext(r.Context()); scoped {
+		return personalAccessTokenForbidden(w)
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	err = u.Store.RevokePersonalAccessToken(id, requestUser.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusNotFound,
+			Data:   &core.APIError{Detail: "Personal access token not found"},
+		})
+	}
+
+	if err != nil {
+		log.Println("Error in revoking the personal access token", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while revoking the personal access token"},
+		})
+	}
+
+	recordAuditEvent(r, u.Store, models.AuditPersonalAccessTokenRevoked, models.AuditTargetPersonalAccessToken, id.String(), nil, nil)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   &APISuccessResponse{Detail: "Personal access token revoked successfully"},
+	})
+}
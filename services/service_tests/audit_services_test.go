@@ -0,0 +1,174 @@
+package service_tests
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common/common_tests"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/services"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func auditRouter(store storages.Storage) *mux.Router {
+	auditService := services.NewAuditService(store)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/audit/", core.HTTPHandleFunc(auditService.GetAuditEventsHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/audit/export/", core.HTTPHandleFunc(auditService.ExportAuditEventsHandler)).Methods(http.MethodGet)
+	return r
+}
+
+func TestGetAuditEventsHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/audit/?actor_id=%s&action=board.deleted&from=2024-01-01T00:00:00Z", uuid.New()), nil)
+
+	rr := httptest.NewRecorder()
+	auditRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var listAPIResponse core.ListAPIResponseBody
+	json.Unmarshal(rr.Body.Bytes(), &listAPIResponse)
+	assert.Equal(t, 2, listAPIResponse.Count)
+}
+
+func TestGetAuditEventsHandlerWithInvalidFilter(t *testing.T) {
+	for _, query := range []string{"actor_id=not-a-uuid", "from=yesterday", "to=2024-01-01"} {
+		req, _ := http.NewRequest(http.MethodGet, "/audit/?"+query, nil)
+
+		rr := httptest.NewRecorder()
+		auditRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func TestExportAuditEventsHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/audit/export/", nil)
+
+	rr := httptest.NewRecorder()
+	auditRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rr.Header().Get("Content-Disposition"), "attachment;"))
+
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, "action", records[0][3])
+	assert.Equal(t, string(models.AuditBoardDeleted), records[1][3])
+	assert.Equal(t, "'=1+1", records[2][6])
+}
+
+func TestExportAuditEventsHandlerStopsAtItsStart(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/audit/export/?to=2999-01-01T00:00:00Z", nil)
+
+	store := new(MockAuditFilterStorage)
+	startedAt := time.Now().UTC()
+
+	rr := httptest.NewRecorder()
+	auditRouter(store).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotEmpty(t, store.Filters)
+	for _, filter := range store.Filters {
+		assert.NotNil(t, filter.To)
+		assert.False(t, filter.To.Before(startedAt))
+		assert.False(t, filter.To.After(time.Now().UTC()))
+	}
+}
+
+func TestDeleteBoardHandlerRecordsAuditEvent(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/boards/%s/", uuid.New()), nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	store := new(MockAuditStorage)
+	boardService := services.NewBoardService(store, new(storages_tests.MockRedisClient), new(common_tests.MockEmail))
+
+	rr := httptest.NewRecorder()
+	r := mux.NewRouter()
+	r.HandleFunc("/boards/{id}/", core.HTTPHandleFunc(boardService.DeleteBoardHandler)).Methods(http.MethodDelete)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, store.Events, 1)
+
+	event := store.Events[0]
+	assert.Equal(t, models.AuditBoardDeleted, event.Action)
+	assert.Equal(t, models.AuditTargetBoard, event.TargetType)
+	assert.Equal(t, user.Id, *event.ActorId)
+	assert.Equal(t, "10.0.0.1", event.IPAddress)
+	assert.NotEmpty(t, event.Before)
+	assert.Empty(t, event.After)
+}
+
+func TestClientIPIgnoresInvalidForwardedAddress(t *testing.T) {
+	t.Setenv("TRUST_PROXY_HEADERS", "true")
+
+	for forwarded, expected := range map[string]string{
+		"203.0.113.7, 10.0.0.2":     "203.0.113.7",
+		"2001:db8::1":               "2001:db8::1",
+		"not-an-address":            "10.0.0.1",
+		strings.Repeat("1", 100):    "10.0.0.1",
+		"<script>alert(1)</script>": "10.0.0.1",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Forwarded-For", forwarded)
+
+		assert.Equal(t, expected, core.ClientIP(req), forwarded)
+	}
+}
+
+func TestClearRedisCacheRecordsAuditEvent(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "/clear_redis/", nil)
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	store := new(MockAuditStorage)
+	basicService := services.BasicService{Store: store, RedisClient: new(storages_tests.MockRedisClient)}
+
+	rr := httptest.NewRecorder()
+	core.HTTPHandleFunc(basicService.ClearRedisCache).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, store.Events, 1)
+	assert.Equal(t, models.AuditCacheCleared, store.Events[0].Action)
+}
+
+func TestUpdateUserHandlerRecordsRoleChange(t *testing.T) {
+	payload, _ := json.Marshal(models.UpdateUserRequest{UserType: models.TeamMember})
+	req, _ := http.NewRequest(http.MethodPatch, fmt.Sprintf("/users/%s/", uuid.New()), strings.NewReader(string(payload)))
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	store := new(MockAuditStorage)
+	userService := services.NewUserService(store, new(storages_tests.MockRedisClient), new(common_tests.MockEmail), middlewares.NewGoogleAuth())
+
+	rr := httptest.NewRecorder()
+	r := mux.NewRouter()
+	r.HandleFunc("/users/{id}/", core.HTTPHandleFunc(userService.UpdateUserHandler)).Methods(http.MethodPatch)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, store.Events, 1)
+	assert.Equal(t, models.AuditUserRoleChanged, store.Events[0].Action)
+}
@@ -2,7 +2,6 @@ package service_tests
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,7 +9,6 @@ import (
 	"github.com/Aakash-Pandit/reetro-golang/core"
 	"github.com/Aakash-Pandit/reetro-golang/middlewares"
 	"github.com/Aakash-Pandit/reetro-golang/services"
-	"github.com/stretchr/testify/assert"
 )
 
 func TestHomePage(t *testing.T) {
@@ -62,22 +60,24 @@ func TestAboutPage(t *testing.T) {
 	}
 }
 
-func TestGetRequestUser(t *testing.T) {
-	req, err := http.NewRequest("GET", "/about/", nil)
+func TestJWKSHandler(t *testing.T) {
+	req, err := http.NewRequest("GET", "/.well-known/jwks.json", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	user := TestMockUser()
-	token, _ := middlewares.GenerateJSONWebToken(user.Id.String(), user.Email)
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(core.HTTPHandleFunc(services.JWKSHandler))
+	handler.ServeHTTP(rr, req)
 
-	token = fmt.Sprintf("Bearer %s", token)
-	req.Header.Set("Authorization", token)
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
 
-	mockRequestUserStorage := new(MockRequestUserStorage)
-	requestUser := services.NewRequestUser(mockRequestUserStorage)
-	reqUser := requestUser.GetRequestUser(req)
+	var jwks middlewares.JSONWebKeySet
+	_ = json.NewDecoder(rr.Body).Decode(&jwks)
 
-	assert.Equal(t, reqUser.Id, user.Id)
-	assert.Equal(t, reqUser.Email, user.Email)
+	if len(jwks.Keys) == 0 {
+		t.Errorf("handler returned no keys: got %v", rr.Body.String())
+	}
 }
@@ -0,0 +1,190 @@
+package service_tests
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBoardColumnsHandler(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard()}
+	url := fmt.Sprintf("/boards/%s/columns/", store.Board.Id)
+
+	rr := serveBoardRequest(store, TestTeamMemberUser(), http.MethodGet, url, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var listAPIResponse core.ListAPIResponseBody
+	json.Unmarshal(rr.Body.Bytes(), &listAPIResponse)
+	assert.Equal(t, 3, listAPIResponse.Count)
+}
+
+func TestCreateBoardColumnHandler(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard()}
+	url := fmt.Sprintf("/boards/%s/columns/", store.Board.Id)
+	payload := models.CreateBoardColumnRequest{Title: "Puzzles & Risks", Color: "#FFAA00"}
+
+	rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodPost, url, payload)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var column models.BoardColumn
+	json.Unmarshal(rr.Body.Bytes(), &column)
+	assert.Equal(t, models.ColumnType("puzzles_risks"), column.Key)
+	assert.Equal(t, "#ffaa00", column.Color)
+	assert.Equal(t, 3, column.Position)
+}
+
+func TestCreateBoardColumnHandlerWithExistingKey(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard()}
+	url := fmt.Sprintf("/boards/%s/columns/", store.Board.Id)
+	payload := models.CreateBoardColumnRequest{Title: "Went well"}
+
+	rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodPost, url, payload)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCreateBoardColumnHandlerRacingAnotherRequest(t *testing.T) {
+	for _, createErr := range []error{sql.ErrNoRows, &pq.Error{Code: storages.UNIQUE_VIOLATION}} {
+		store := &MockBoardColumnStorage{Board: TestMockBoard(), CreateErr: createErr}
+		url := fmt.Sprintf("/boards/%s/columns/", store.Board.Id)
+		payload := models.CreateBoardColumnRequest{Title: "Risks"}
+
+		rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodPost, url, payload)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, createErr.Error())
+	}
+}
+
+func TestCreateBoardColumnHandlerAsTeamMember(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard()}
+	url := fmt.Sprintf("/boards/%s/columns/", store.Board.Id)
+	payload := models.CreateBoardColumnRequest{Title: "Risks"}
+
+	teamMemberStore := &MockTeamMemberStorage{}
+	rr := serveBoardRequest(teamMemberStore, TestTeamMemberUser(), http.MethodPost, url, payload)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestUpdateBoardColumnHandler(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard()}
+	column := store.Board.Columns[0]
+	url := fmt.Sprintf("/boards/%s/columns/%s/", store.Board.Id, column.Id)
+	payload := map[string]string{"title": "Wins", "description": "What made you proud?"}
+
+	rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodPatch, url, payload)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var updated models.BoardColumn
+	json.Unmarshal(rr.Body.Bytes(), &updated)
+	assert.Equal(t, "Wins", updated.Title)
+	assert.Equal(t, "What made you proud?", updated.Description)
+	assert.Equal(t, column.Key, updated.Key)
+}
+
+func TestReorderBoardColumnsHandler(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard()}
+	columns := store.Board.Columns
+	url := fmt.Sprintf("/boards/%s/columns/order/", store.Board.Id)
+	payload := models.ReorderBoardColumnsRequest{ColumnIds: []string{columns[2].Id.String(), columns[0].Id.String(), columns[1].Id.String()}}
+
+	rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodPut, url, payload)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestReorderBoardColumnsHandlerWithMissingColumn(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard()}
+	columns := store.Board.Columns
+	url := fmt.Sprintf("/boards/%s/columns/order/", store.Board.Id)
+	payload := models.ReorderBoardColumnsRequest{ColumnIds: []string{columns[2].Id.String(), columns[0].Id.String()}}
+
+	rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodPut, url, payload)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestRemoveBoardColumnHandler(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard()}
+	url := fmt.Sprintf("/boards/%s/columns/%s/", store.Board.Id, store.Board.Columns[0].Id)
+
+	rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodDelete, url, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRemoveBoardColumnHandlerWithFeedback(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard(), ColumnFeedbacks: 2}
+	url := fmt.Sprintf("/boards/%s/columns/%s/", store.Board.Id, store.Board.Columns[0].Id)
+
+	rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodDelete, url, nil)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = serveBoardRequest(store, TestMockUserResponse(), http.MethodDelete, url+"?move_to=action", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serveBoardRequest(store, TestMockUserResponse(), http.MethodDelete, url+"?delete_feedback=true", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRemoveBoardColumnHandlerDropsCachedFeedback(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard(), ColumnFeedbacks: 2, FeedbackIds: []uuid.UUID{uuid.New(), uuid.New()}}
+	url := fmt.Sprintf("/boards/%s/columns/%s/?move_to=action", store.Board.Id, store.Board.Columns[0].Id)
+	user := TestMockUserResponse()
+
+	req, _ := http.NewRequest(http.MethodDelete, url, nil)
+	req = TestRequestWithUser(req, &user)
+
+	redisClient := new(MockDeletingRedisClient)
+	rr := httptest.NewRecorder()
+	boardRouterWithRedis(store, redisClient).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	for _, id := range store.FeedbackIds {
+		assert.Contains(t, redisClient.Deleted, id.String())
+	}
+}
+
+func TestRemoveBoardColumnHandlerDeletingFeedbackWhileVoting(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard(), ColumnFeedbacks: 2}
+	store.Board.Phase = models.PhaseVoting
+	url := fmt.Sprintf("/boards/%s/columns/%s/", store.Board.Id, store.Board.Columns[0].Id)
+
+	rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodDelete, url+"?delete_feedback=true", nil)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = serveBoardRequest(store, TestMockUserResponse(), http.MethodDelete, url+"?move_to=action", nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRemoveBoardColumnHandlerMovingToUnknownColumn(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard()}
+	url := fmt.Sprintf("/boards/%s/columns/%s/?move_to=went_well", store.Board.Id, store.Board.Columns[0].Id)
+
+	rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodDelete, url, nil)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestRemoveBoardColumnHandlerRemovingLastColumn(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard()}
+	store.Board.Columns = store.Board.Columns[:1]
+	url := fmt.Sprintf("/boards/%s/columns/%s/", store.Board.Id, store.Board.Columns[0].Id)
+
+	rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodDelete, url, nil)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
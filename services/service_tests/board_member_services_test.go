@@ -0,0 +1,175 @@
+package service_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aakash-Pandit/reetro-golang/common/common_tests"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/services"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func boardMemberRouter(store storages.Storage) *mux.Router {
+	boardService := services.NewBoardService(store, new(storages_tests.MockRedisClient), new(common_tests.MockEmail))
+
+	r := mux.NewRouter()
+	r.HandleFunc("/boards/invitations/accept/", core.HTTPHandleFunc(boardService.AcceptBoardInvitationHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{id}/members/", core.HTTPHandleFunc(boardService.GetBoardMembersHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{id}/members/{user_id}/", core.HTTPHandleFunc(boardService.RemoveBoardMemberHandler)).Methods(http.MethodDelete)
+	r.HandleFunc("/boards/{id}/invitations/", core.HTTPHandleFunc(boardService.CreateBoardInvitationHandler)).Methods(http.MethodPost)
+	return r
+}
+
+func TestGetBoardMembersHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/boards/%s/members/", uuid.New()), nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	boardMemberRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var listAPIResponse core.ListAPIResponseBody
+	json.Unmarshal(rr.Body.Bytes(), &listAPIResponse)
+	assert.Equal(t, 1, listAPIResponse.Count)
+}
+
+func TestGetBoardMembersHandlerForNonMember(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/boards/%s/members/", uuid.New()), nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	boardMemberRouter(new(MockNonMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestCreateBoardInvitationHandler(t *testing.T) {
+	payload, _ := json.Marshal(models.CreateBoardInvitationRequest{Email: "New.Member@Email.com", Role: models.BoardViewer})
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/boards/%s/invitations/", uuid.New()), bytes.NewBuffer(payload))
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	boardMemberRouter(new(MockTeamMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.Equal(t, "new.member@email.com", response["email"])
+	assert.Equal(t, string(models.BoardViewer), response["role"])
+	assert.Nil(t, response["token_hash"])
+}
+
+func TestCreateBoardInvitationHandlerForNonMember(t *testing.T) {
+	payload, _ := json.Marshal(models.CreateBoardInvitationRequest{Email: "new.member@email.com"})
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/boards/%s/invitations/", uuid.New()), bytes.NewBuffer(payload))
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	boardMemberRouter(new(MockNonMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestAcceptBoardInvitationHandler(t *testing.T) {
+	payload, _ := json.Marshal(models.AcceptBoardInvitationRequest{Token: "test_invitation_token"})
+	req, _ := http.NewRequest(http.MethodPost, "/boards/invitations/accept/", bytes.NewBuffer(payload))
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	boardMemberRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var member models.BoardMember
+	json.Unmarshal(rr.Body.Bytes(), &member)
+	assert.Equal(t, models.BoardParticipant, member.Role)
+	assert.Equal(t, user.Id, member.UserId)
+}
+
+func TestAcceptBoardInvitationHandlerWithOtherEmail(t *testing.T) {
+	payload, _ := json.Marshal(models.AcceptBoardInvitationRequest{Token: "test_invitation_token"})
+	req, _ := http.NewRequest(http.MethodPost, "/boards/invitations/accept/", bytes.NewBuffer(payload))
+
+	user := TestTeamMemberUser()
+	user.Email = "someone.else@email.com"
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	boardMemberRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestRemoveBoardMemberHandlerLeavingBoard(t *testing.T) {
+	user := TestTeamMemberUser()
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/boards/%s/members/%s/", uuid.New(), user.Id), nil)
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	boardMemberRouter(new(MockNonMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRemoveBoardMemberHandlerForNonMember(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/boards/%s/members/%s/", uuid.New(), uuid.New()), nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	boardMemberRouter(new(MockNonMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestBoardMemberHandlersOnClosedBoard(t *testing.T) {
+	user := TestMockUserResponse()
+	boardId := uuid.New()
+
+	invitation, _ := json.Marshal(models.CreateBoardInvitationRequest{Email: "invitee@email.com", Role: models.BoardParticipant})
+	accept, _ := json.Marshal(models.AcceptBoardInvitationRequest{Token: "test_invitation_token"})
+
+	requests := []struct {
+		method string
+		url    string
+		body   []byte
+	}{
+		{http.MethodDelete, fmt.Sprintf("/boards/%s/members/%s/", boardId, uuid.New()), nil},
+		{http.MethodPost, fmt.Sprintf("/boards/%s/invitations/", boardId), invitation},
+		{http.MethodPost, "/boards/invitations/accept/", accept},
+	}
+
+	for _, request := range requests {
+		req, _ := http.NewRequest(request.method, request.url, bytes.NewBuffer(request.body))
+		req = TestRequestWithUser(req, &user)
+
+		rr := httptest.NewRecorder()
+		boardMemberRouter(new(MockClosedBoardStorage)).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, request.url)
+		assert.Contains(t, rr.Body.String(), "This board is closed", request.url)
+	}
+}
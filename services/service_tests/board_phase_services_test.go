@@ -0,0 +1,231 @@
+package service_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeBoardPhaseHandler(t *testing.T) {
+	tests := map[models.BoardPhase]models.BoardPhase{
+		models.PhaseDraft:      models.PhaseCollecting,
+		models.PhaseCollecting: models.PhaseGrouping,
+		models.PhaseGrouping:   models.PhaseVoting,
+		models.PhaseVoting:     models.PhaseDiscussing,
+		models.PhaseDiscussing: models.PhaseClosed,
+	}
+
+	for from, to := range tests {
+		url := fmt.Sprintf("/boards/%s/phase/", uuid.New())
+		rr := serveBoardRequest(&MockBoardPhaseStorage{Phase: from}, TestMockUserResponse(), http.MethodPost, url, models.BoardPhaseRequest{Phase: to})
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		var board models.Board
+		json.Unmarshal(rr.Body.Bytes(), &board)
+		assert.Equal(t, to, board.Phase)
+	}
+}
+
+func TestChangeBoardPhaseHandlerSkippingAPhase(t *testing.T) {
+	url := fmt.Sprintf("/boards/%s/phase/", uuid.New())
+	rr := serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseCollecting}, TestMockUserResponse(), http.MethodPost, url, models.BoardPhaseRequest{Phase: models.PhaseVoting})
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseVoting}, TestMockUserResponse(), http.MethodPost, url, models.BoardPhaseRequest{Phase: models.PhaseCollecting})
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestChangeBoardPhaseHandlerClosingEarly(t *testing.T) {
+	url := fmt.Sprintf("/boards/%s/phase/", uuid.New())
+	rr := serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseCollecting}, TestMockUserResponse(), http.MethodPost, url, models.BoardPhaseRequest{Phase: models.PhaseClosed})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var board models.Board
+	json.Unmarshal(rr.Body.Bytes(), &board)
+	assert.True(t, board.IsClosed())
+}
+
+func TestChangeBoardPhaseHandlerForClosedBoard(t *testing.T) {
+	url := fmt.Sprintf("/boards/%s/phase/", uuid.New())
+	rr := serveBoardRequest(new(MockClosedBoardStorage), TestMockUserResponse(), http.MethodPost, url, models.BoardPhaseRequest{Phase: models.PhaseClosed})
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestChangeBoardPhaseHandlerForParticipant(t *testing.T) {
+	url := fmt.Sprintf("/boards/%s/phase/", uuid.New())
+	rr := serveBoardRequest(new(MockBoardParticipantStorage), TestTeamMemberUser(), http.MethodPost, url, models.BoardPhaseRequest{Phase: models.PhaseGrouping})
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestUpdateBoardHandlerForClosedBoard(t *testing.T) {
+	url := fmt.Sprintf("/boards/%s/", uuid.New())
+	rr := serveBoardRequest(new(MockClosedBoardStorage), TestMockUserResponse(), http.MethodPatch, url, models.UpdateBoardRequest{Name: "Renamed"})
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUpdateBoardHandlerSettingsByPhase(t *testing.T) {
+	tests := []struct {
+		phase  models.BoardPhase
+		status int
+	}{
+		{models.PhaseDraft, http.StatusOK},
+		{models.PhaseCollecting, http.StatusOK},
+		{models.PhaseGrouping, http.StatusConflict},
+		{models.PhaseVoting, http.StatusConflict},
+		{models.PhaseDiscussing, http.StatusConflict},
+	}
+
+	for _, test := range tests {
+		settings := TestMockBoard().Settings
+		settings.VotesPerParticipant++
+
+		url := fmt.Sprintf("/boards/%s/", uuid.New())
+		rr := serveBoardRequest(&MockBoardPhaseStorage{Phase: test.phase}, TestMockUserResponse(), http.MethodPatch, url, models.UpdateBoardRequest{Name: "Renamed", Settings: &settings})
+
+		assert.Equal(t, test.status, rr.Code, test.phase)
+	}
+
+	// Renaming the board, with its settings sent back as they are, is still
+	// possible.
+	settings := TestMockBoard().Settings
+	url := fmt.Sprintf("/boards/%s/", uuid.New())
+	rr := serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseVoting}, TestMockUserResponse(), http.MethodPatch, url, models.UpdateBoardRequest{Name: "Renamed", Settings: &settings})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestCreateFeedbackHandlerByPhase(t *testing.T) {
+	tests := []struct {
+		phase  models.BoardPhase
+		column models.ColumnType
+		status int
+	}{
+		{models.PhaseDraft, models.WentWell, http.StatusConflict},
+		{models.PhaseCollecting, models.WentWell, http.StatusCreated},
+		{models.PhaseGrouping, models.WentWell, http.StatusConflict},
+		{models.PhaseVoting, models.WentWell, http.StatusConflict},
+		{models.PhaseDiscussing, models.WentWell, http.StatusConflict},
+		{models.PhaseDiscussing, models.Action, http.StatusCreated},
+	}
+
+	for _, test := range tests {
+		payload := models.CreateFeedbackRequest{Message: "feedback", BoardId: uuid.New().String(), Column: test.column}
+		rr := serveBoardRequest(&MockBoardPhaseStorage{Phase: test.phase}, TestMockUserResponse(), http.MethodPost, "/feedbacks/", payload)
+
+		assert.Equal(t, test.status, rr.Code, "%s in %s", test.column, test.phase)
+	}
+}
+
+func TestCreateFeedbackHandlerForClosedBoard(t *testing.T) {
+	payload := models.CreateFeedbackRequest{Message: "feedback", BoardId: uuid.New().String(), Column: models.Action}
+	rr := serveBoardRequest(new(MockClosedBoardStorage), TestMockUserResponse(), http.MethodPost, "/feedbacks/", payload)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUpdateAndMoveFeedbackHandlerByPhase(t *testing.T) {
+	url := fmt.Sprintf("/feedbacks/%s/", uuid.New())
+
+	rr := serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseGrouping}, TestMockUserResponse(), http.MethodPatch, url, models.UpdateFeedbackRequest{Message: "changed"})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseGrouping}, TestMockUserResponse(), http.MethodPost, url+"move/", models.MoveFeedbackRequest{Column: models.ToImprove})
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseVoting}, TestMockUserResponse(), http.MethodPost, url+"move/", models.MoveFeedbackRequest{Column: models.ToImprove})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestVoteFeedbackHandler(t *testing.T) {
+	url := fmt.Sprintf("/feedbacks/%s/votes/", uuid.New())
+
+	rr := serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseVoting}, TestMockUserResponse(), http.MethodPost, url, nil)
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	rr = serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseVoting}, TestMockUserResponse(), http.MethodDelete, url, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestVoteFeedbackHandlerOutsideVoting(t *testing.T) {
+	url := fmt.Sprintf("/feedbacks/%s/votes/", uuid.New())
+
+	rr := serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseCollecting}, TestMockUserResponse(), http.MethodPost, url, nil)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = serveBoardRequest(new(MockClosedBoardStorage), TestMockUserResponse(), http.MethodDelete, url, nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestVoteFeedbackHandlerWithoutVotesLeft(t *testing.T) {
+	url := fmt.Sprintf("/feedbacks/%s/votes/", uuid.New())
+	store := &MockBoardPhaseStorage{Phase: models.PhaseVoting, Votes: TestMockBoard().Settings.VotesPerParticipant}
+
+	rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodPost, url, nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestVoteFeedbackHandlerForNonMember(t *testing.T) {
+	url := fmt.Sprintf("/feedbacks/%s/votes/", uuid.New())
+
+	rr := serveBoardRequest(new(MockNonMemberStorage), TestTeamMemberUser(), http.MethodPost, url, nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestGroupFeedbackHandler(t *testing.T) {
+	url := fmt.Sprintf("/feedbacks/%s/group/", uuid.New())
+	payload := models.GroupFeedbackRequest{GroupId: uuid.New().String()}
+
+	rr := serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseGrouping}, TestMockUserResponse(), http.MethodPut, url, payload)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseCollecting}, TestMockUserResponse(), http.MethodPut, url, payload)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestGroupFeedbackHandlerDropsCachedStack(t *testing.T) {
+	store := &MockBoardPhaseStorage{Phase: models.PhaseGrouping, Stacked: []uuid.UUID{uuid.New()}}
+	payload, _ := json.Marshal(models.GroupFeedbackRequest{GroupId: uuid.New().String()})
+	user := TestMockUserResponse()
+
+	req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("/feedbacks/%s/group/", uuid.New()), bytes.NewBuffer(payload))
+	req = TestRequestWithUser(req, &user)
+
+	redisClient := new(MockDeletingRedisClient)
+	rr := httptest.NewRecorder()
+	boardRouterWithRedis(store, redisClient).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, redisClient.Deleted, store.Stacked[0].String())
+}
+
+func TestGroupFeedbackHandlerWithItself(t *testing.T) {
+	id := uuid.New()
+	url := fmt.Sprintf("/feedbacks/%s/group/", id)
+
+	rr := serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseGrouping}, TestMockUserResponse(), http.MethodPut, url, models.GroupFeedbackRequest{GroupId: id.String()})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUngroupFeedbackHandler(t *testing.T) {
+	url := fmt.Sprintf("/feedbacks/%s/group/", uuid.New())
+
+	rr := serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseGrouping, Grouped: true}, TestMockUserResponse(), http.MethodDelete, url, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseGrouping}, TestMockUserResponse(), http.MethodDelete, url, nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
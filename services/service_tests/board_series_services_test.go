@@ -0,0 +1,94 @@
+package service_tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloneBoardHandler(t *testing.T) {
+	id := uuid.New()
+	payload := models.CloneBoardRequest{CarryOverActionItems: true}
+
+	rr := serveBoardRequest(new(MockClosedBoardStorage), TestMockUserResponse(), http.MethodPost, fmt.Sprintf("/boards/%s/clone/", id), payload)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var board models.Board
+	json.Unmarshal(rr.Body.Bytes(), &board)
+	assert.Equal(t, "test_board 2", board.Name)
+	assert.Equal(t, &id, board.PreviousBoardId)
+	assert.Equal(t, models.PhaseDraft, board.Phase)
+	assert.False(t, board.IsClosed())
+	assert.Len(t, board.Columns, len(TestMockBoard().Columns))
+}
+
+func TestCloneBoardHandlerWithName(t *testing.T) {
+	rr := serveBoardRequest(new(MockStorage), TestMockUserResponse(), http.MethodPost, fmt.Sprintf("/boards/%s/clone/", uuid.New()), models.CloneBoardRequest{Name: "Planning"})
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var board models.Board
+	json.Unmarshal(rr.Body.Bytes(), &board)
+	assert.Equal(t, "Planning", board.Name)
+}
+
+func TestCloneBoardHandlerWithoutActionColumn(t *testing.T) {
+	store := &MockBoardColumnStorage{Board: TestMockBoard()}
+	store.Board.Settings.ActionColumn = ""
+
+	rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodPost, fmt.Sprintf("/boards/%s/clone/", uuid.New()), models.CloneBoardRequest{CarryOverActionItems: true})
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = serveBoardRequest(store, TestMockUserResponse(), http.MethodPost, fmt.Sprintf("/boards/%s/clone/", uuid.New()), models.CloneBoardRequest{})
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
+
+func TestCloneBoardHandlerAsParticipant(t *testing.T) {
+	rr := serveBoardRequest(new(MockBoardParticipantStorage), TestTeamMemberUser(), http.MethodPost, fmt.Sprintf("/boards/%s/clone/", uuid.New()), models.CloneBoardRequest{})
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestGetBoardSeriesHandler(t *testing.T) {
+	rr := serveBoardRequest(new(MockStorage), TestMockUserResponse(), http.MethodGet, fmt.Sprintf("/boards/%s/series/", uuid.New()), nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var listAPIResponse core.ListAPIResponseBody
+	json.Unmarshal(rr.Body.Bytes(), &listAPIResponse)
+	assert.Equal(t, len(TestMockBoards()), listAPIResponse.Count)
+}
+
+func TestResolveFeedbackHandler(t *testing.T) {
+	url := fmt.Sprintf("/feedbacks/%s/resolve/", uuid.New())
+	store := &MockBoardPhaseStorage{Phase: models.PhaseDraft, Column: models.Action}
+
+	rr := serveBoardRequest(store, TestMockUserResponse(), http.MethodPost, url, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serveBoardRequest(store, TestMockUserResponse(), http.MethodDelete, url, nil)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestResolveFeedbackHandlerOutsideActionColumn(t *testing.T) {
+	url := fmt.Sprintf("/feedbacks/%s/resolve/", uuid.New())
+
+	rr := serveBoardRequest(&MockBoardPhaseStorage{Phase: models.PhaseDiscussing}, TestMockUserResponse(), http.MethodPost, url, nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestResolveFeedbackHandlerForClosedBoard(t *testing.T) {
+	url := fmt.Sprintf("/feedbacks/%s/resolve/", uuid.New())
+
+	rr := serveBoardRequest(new(MockClosedBoardStorage), TestMockUserResponse(), http.MethodPost, url, nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -8,10 +8,11 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/Aakash-Pandit/reetro-golang/common/common_tests"
 	"github.com/Aakash-Pandit/reetro-golang/core"
-	"github.com/Aakash-Pandit/reetro-golang/middlewares"
 	"github.com/Aakash-Pandit/reetro-golang/services"
 	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 )
@@ -22,19 +23,14 @@ func TestGetAllBoardsHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	user := TestMockUser()
-	token, _ := middlewares.GenerateJSONWebToken(user.Id.String(), user.Email)
-
-	token = fmt.Sprintf("Bearer %s", token)
-	req.Header.Set("Authorization", token)
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
 
 	rr := httptest.NewRecorder()
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
-	requestUser := services.NewRequestUser(mockRequestUser)
 	mockRepo := new(MockStorage)
-	boardService := services.NewBoardService(mockRepo, *requestUser, mockRedisClient)
+	boardService := services.NewBoardService(mockRepo, mockRedisClient, new(common_tests.MockEmail))
 
 	r := mux.NewRouter()
 	r.HandleFunc("/boards/", core.HTTPHandleFunc(boardService.GetAllBoardsHandler)).Methods(http.MethodGet)
@@ -65,13 +61,14 @@ func TestGetBoardByIdHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
 	rr := httptest.NewRecorder()
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
-	requestUser := services.NewRequestUser(mockRequestUser)
 	mockRepo := new(MockStorage)
-	boardService := services.NewBoardService(mockRepo, *requestUser, mockRedisClient)
+	boardService := services.NewBoardService(mockRepo, mockRedisClient, new(common_tests.MockEmail))
 
 	r := mux.NewRouter()
 	r.HandleFunc("/boards/{id}/", core.HTTPHandleFunc(boardService.GetBoardByIdHandler)).Methods(http.MethodGet)
@@ -92,21 +89,16 @@ func TestCreateBoardHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	user := TestMockUser()
-	token, _ := middlewares.GenerateJSONWebToken(user.Id.String(), user.Email)
-
-	token = fmt.Sprintf("Bearer %s", token)
-	req.Header.Set("Authorization", token)
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
 
 	rr := httptest.NewRecorder()
 
 	mockRepo := new(MockStorage)
 	mockRepo.On("CreateBoard", board).Return(board, nil)
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
-	requestUser := services.NewRequestUser(mockRequestUser)
-	boardService := services.NewBoardService(mockRepo, *requestUser, mockRedisClient)
+	boardService := services.NewBoardService(mockRepo, mockRedisClient, new(common_tests.MockEmail))
 
 	r := mux.NewRouter()
 	r.HandleFunc("/boards/", core.HTTPHandleFunc(boardService.CreateBoardHandler)).Methods(http.MethodPost)
@@ -129,21 +121,16 @@ func TestUpdateBoardHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	user := TestMockUser()
-	token, _ := middlewares.GenerateJSONWebToken(user.Id.String(), user.Email)
-
-	token = fmt.Sprintf("Bearer %s", token)
-	req.Header.Set("Authorization", token)
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
 
 	rr := httptest.NewRecorder()
 
 	mockRepo := new(MockStorage)
 	mockRepo.On("UpdateBoard", testBoard).Return(testBoard, nil)
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
-	requestUser := services.NewRequestUser(mockRequestUser)
-	boardService := services.NewBoardService(mockRepo, *requestUser, mockRedisClient)
+	boardService := services.NewBoardService(mockRepo, mockRedisClient, new(common_tests.MockEmail))
 
 	r := mux.NewRouter()
 	r.HandleFunc("/boards/{id}/", core.HTTPHandleFunc(boardService.UpdateBoardHandler)).Methods(http.MethodPatch)
@@ -164,13 +151,14 @@ func TestDeleteBoardHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
 	rr := httptest.NewRecorder()
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
-	requestUser := services.NewRequestUser(mockRequestUser)
 	mockRepo := new(MockStorage)
-	boardService := services.NewBoardService(mockRepo, *requestUser, mockRedisClient)
+	boardService := services.NewBoardService(mockRepo, mockRedisClient, new(common_tests.MockEmail))
 
 	r := mux.NewRouter()
 	r.HandleFunc("/boards/{id}/", core.HTTPHandleFunc(boardService.DeleteBoardHandler)).Methods(http.MethodDelete)
@@ -180,3 +168,77 @@ func TestDeleteBoardHandler(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
+
+func TestGetAllBoardsHandlerOnlyListsTeamBoards(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/boards/", nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+
+	boardService := services.NewBoardService(new(MockStorage), new(storages_tests.MockRedisClient), new(common_tests.MockEmail))
+
+	r := mux.NewRouter()
+	r.HandleFunc("/boards/", core.HTTPHandleFunc(boardService.GetAllBoardsHandler)).Methods(http.MethodGet)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var listAPIResponse core.ListAPIResponseBody
+	json.Unmarshal(rr.Body.Bytes(), &listAPIResponse)
+	assert.Equal(t, 1, listAPIResponse.Count)
+}
+
+func TestGetBoardByIdHandlerForNonMember(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/boards/%s/", uuid.New()), nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+
+	boardService := services.NewBoardService(new(MockNonMemberStorage), new(storages_tests.MockRedisClient), new(common_tests.MockEmail))
+
+	r := mux.NewRouter()
+	r.HandleFunc("/boards/{id}/", core.HTTPHandleFunc(boardService.GetBoardByIdHandler)).Methods(http.MethodGet)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestCreateBoardHandlerAsTeamMember(t *testing.T) {
+	payload, _ := json.Marshal(TestMockBoard())
+	req, _ := http.NewRequest(http.MethodPost, "/boards/", bytes.NewBuffer(payload))
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+
+	boardService := services.NewBoardService(new(MockTeamMemberStorage), new(storages_tests.MockRedisClient), new(common_tests.MockEmail))
+
+	r := mux.NewRouter()
+	r.HandleFunc("/boards/", core.HTTPHandleFunc(boardService.CreateBoardHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestCreateBoardHandlerAsTeamOwner(t *testing.T) {
+	payload, _ := json.Marshal(TestMockBoard())
+	req, _ := http.NewRequest(http.MethodPost, "/boards/", bytes.NewBuffer(payload))
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+
+	boardService := services.NewBoardService(new(MockStorage), new(storages_tests.MockRedisClient), new(common_tests.MockEmail))
+
+	r := mux.NewRouter()
+	r.HandleFunc("/boards/", core.HTTPHandleFunc(boardService.CreateBoardHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
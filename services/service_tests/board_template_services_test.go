@@ -0,0 +1,135 @@
+package service_tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBoardTemplatesHandler(t *testing.T) {
+	rr := serveBoardRequest(new(MockStorage), TestTeamMemberUser(), http.MethodGet, "/templates/", nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var listAPIResponse core.ListAPIResponseBody
+	json.Unmarshal(rr.Body.Bytes(), &listAPIResponse)
+	assert.Equal(t, len(models.BuiltInTemplates()), listAPIResponse.Count)
+
+	rr = serveBoardRequest(new(MockStorage), TestTeamMemberUser(), http.MethodGet, fmt.Sprintf("/templates/?team_id=%s", uuid.New()), nil)
+
+	json.Unmarshal(rr.Body.Bytes(), &listAPIResponse)
+	assert.Equal(t, len(models.BuiltInTemplates())+1, listAPIResponse.Count)
+}
+
+func TestGetBoardTemplatesHandlerForNonMember(t *testing.T) {
+	rr := serveBoardRequest(new(MockNonMemberStorage), TestTeamMemberUser(), http.MethodGet, fmt.Sprintf("/templates/?team_id=%s", uuid.New()), nil)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestCreateBoardTemplateHandler(t *testing.T) {
+	payload := models.CreateBoardTemplateRequest{
+		Key:  "risks_review",
+		Name: "Risks review",
+		Columns: []models.CreateBoardColumnRequest{
+			{Title: "Risks"},
+			{Title: "Mitigations"},
+		},
+		Settings: models.BoardSettings{ActionColumn: "mitigations"},
+	}
+
+	rr := serveBoardRequest(new(MockStorage), TestTeamMemberUser(), http.MethodPost, fmt.Sprintf("/teams/%s/templates/", uuid.New()), payload)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var template models.BoardTemplate
+	json.Unmarshal(rr.Body.Bytes(), &template)
+	assert.Equal(t, models.ColumnType("mitigations"), template.Columns[1].Key)
+}
+
+func TestCreateBoardTemplateHandlerWithTakenKey(t *testing.T) {
+	for _, key := range []models.TemplateType{models.Sailboat, TestMockBoardTemplate().Key} {
+		payload := models.CreateBoardTemplateRequest{Key: key, Name: "Taken", Columns: []models.CreateBoardColumnRequest{{Title: "Risks"}}}
+
+		rr := serveBoardRequest(new(MockStorage), TestTeamMemberUser(), http.MethodPost, fmt.Sprintf("/teams/%s/templates/", uuid.New()), payload)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestCreateBoardTemplateHandlerWithUnknownActionColumn(t *testing.T) {
+	payload := models.CreateBoardTemplateRequest{
+		Key:      "risks_review",
+		Name:     "Risks review",
+		Columns:  []models.CreateBoardColumnRequest{{Title: "Risks"}},
+		Settings: models.BoardSettings{ActionColumn: models.Action},
+	}
+
+	rr := serveBoardRequest(new(MockStorage), TestTeamMemberUser(), http.MethodPost, fmt.Sprintf("/teams/%s/templates/", uuid.New()), payload)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCreateBoardTemplateHandlerAsTeamMember(t *testing.T) {
+	payload := models.CreateBoardTemplateRequest{Key: "risks_review", Name: "Risks review", Columns: []models.CreateBoardColumnRequest{{Title: "Risks"}}}
+
+	rr := serveBoardRequest(new(MockTeamMemberStorage), TestTeamMemberUser(), http.MethodPost, fmt.Sprintf("/teams/%s/templates/", uuid.New()), payload)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestUpdateBoardTemplateHandler(t *testing.T) {
+	payload := models.UpdateBoardTemplateRequest{Name: "Renamed", Columns: []models.CreateBoardColumnRequest{{Title: "Risks"}}}
+	url := fmt.Sprintf("/teams/%s/templates/%s/", uuid.New(), TestMockBoardTemplate().Key)
+
+	rr := serveBoardRequest(new(MockStorage), TestTeamMemberUser(), http.MethodPut, url, payload)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serveBoardRequest(new(MockStorage), TestTeamMemberUser(), http.MethodPut, fmt.Sprintf("/teams/%s/templates/unknown/", uuid.New()), payload)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestDeleteBoardTemplateHandler(t *testing.T) {
+	url := fmt.Sprintf("/teams/%s/templates/%s/", uuid.New(), TestMockBoardTemplate().Key)
+
+	rr := serveBoardRequest(new(MockStorage), TestTeamMemberUser(), http.MethodDelete, url, nil)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestCreateBoardHandlerFromTemplate(t *testing.T) {
+	tests := map[models.TemplateType]int{
+		models.FourLs:               4,
+		models.Sailboat:             5,
+		TestMockBoardTemplate().Key: 2,
+	}
+
+	for key, columns := range tests {
+		payload := map[string]string{"name": "Sprint 42", "template": string(key), "team_id": uuid.New().String()}
+
+		rr := serveBoardRequest(new(MockStorage), TestMockUserResponse(), http.MethodPost, "/boards/", payload)
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+
+		var board models.Board
+		json.Unmarshal(rr.Body.Bytes(), &board)
+		assert.Len(t, board.Columns, columns)
+		assert.True(t, board.HasValidSettings())
+	}
+}
+
+func TestCreateBoardHandlerWithUnknownTemplate(t *testing.T) {
+	payload := map[string]string{"name": "Sprint 42", "template": "unknown", "team_id": uuid.New().String()}
+
+	rr := serveBoardRequest(new(MockStorage), TestMockUserResponse(), http.MethodPost, "/boards/", payload)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -1,14 +1,22 @@
 package service_tests
 
 import (
+	"net/http"
 	"time"
 
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
 	"github.com/Aakash-Pandit/reetro-golang/models"
 	"github.com/Aakash-Pandit/reetro-golang/services"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// TestRequestWithUser puts the user into the request context the same way
+// JWTAuthentication does for an authenticated request.
+func TestRequestWithUser(req *http.Request, user *models.CreateUserResponse) *http.Request {
+	return req.WithContext(middlewares.WithRequestUser(req.Context(), user))
+}
+
 func TestMockUsers() []*models.CreateUserResponse {
 	userA := models.CreateUserResponse{
 		Id:         uuid.New(),
@@ -83,17 +91,80 @@ func TestResetPasswordPayload() services.ResetPasswordPayload {
 	return services.ResetPasswordPayload{
 		Username:    "test_username",
 		OldPassword: "old_password",
-		NewPassword: "new_password",
+		NewPassword: "New-Passw0rd-42",
+	}
+}
+
+func TestResetPasswordConfirmPayload() services.ResetPasswordConfirmPayload {
+	return services.ResetPasswordConfirmPayload{
+		Token:       "test_reset_token",
+		NewPassword: "New-Passw0rd-42",
 	}
 }
 
+func TestMockPasswordResetToken() models.PasswordResetToken {
+	return *models.NewPasswordResetToken(uuid.New(), "test_token_hash")
+}
+
+func TestMockPersonalAccessToken() models.PersonalAccessToken {
+	return *models.NewPersonalAccessToken(uuid.New(), "test_token_hash", &models.CreatePersonalAccessTokenRequest{
+		Name:   "test_token",
+		Scopes: []string{string(middlewares.ViewBoards)},
+	})
+}
+
+const (
+	TEST_MFA_SECRET        = "JBSWY3DPEHPK3PXP"
+	TEST_MFA_RECOVERY_CODE = "abcde12345"
+)
+
+func TestMockUserMFA() models.UserMFA {
+	mfa := models.NewUserMFA(uuid.New(), TEST_MFA_SECRET)
+	mfa.Confirm(0)
+	return *mfa
+}
+
+func TestMockUserSession() models.UserSession {
+	return *models.NewUserSession(uuid.New(), "test_user_agent", "127.0.0.1", time.Hour)
+}
+
+func TestMockTeams() []*models.Team {
+	user := TestMockUserResponse()
+	teamA := TestMockTeam()
+	teamA.Name = "test team A"
+	teamA.CreatedById = user.Id
+
+	teamB := TestMockTeam()
+	teamB.Name = "test team B"
+	teamB.CreatedById = user.Id
+
+	return []*models.Team{
+		&teamA, &teamB,
+	}
+}
+
+func TestMockTeam() models.Team {
+	return *models.NewTeam(&models.CreateTeamRequest{Name: "test_team"}, uuid.New())
+}
+
+func TestMockTeamMembership(role models.TeamRole) models.TeamMembership {
+	return *models.NewTeamMembership(uuid.New(), uuid.New(), role)
+}
+
+// TestTeamMemberUser is a user without any global team or board rights, so
+// only their team membership decides what they may do.
+func TestTeamMemberUser() models.CreateUserResponse {
+	user := TestMockUserResponse()
+	user.UserType = models.TeamMember
+	return user
+}
+
 func TestMockBoards() []*models.Board {
 	user := TestMockUserResponse()
 	boardA := models.Board{
 		Id:           uuid.New(),
 		Name:         "test board A",
 		Template:     models.Agile,
-		Columns:      []models.ColumnType{models.WentWell, models.ToImprove, models.Action},
 		CreatedById:  user.Id,
 		ModifiedById: user.Id,
 		CreatedBy:    &user,
@@ -106,7 +177,6 @@ func TestMockBoards() []*models.Board {
 		Id:           uuid.New(),
 		Name:         "test board B",
 		Template:     models.Agile,
-		Columns:      []models.ColumnType{models.WentWell, models.ToImprove, models.Action},
 		CreatedById:  user.Id,
 		ModifiedById: user.Id,
 		CreatedBy:    &user,
@@ -119,25 +189,78 @@ func TestMockBoards() []*models.Board {
 		&boardA, &boardB,
 	}
 
+	for _, board := range boards {
+		board.Columns = TestMockBoardColumns(board.Id)
+	}
+
 	return boards
 }
 
 func TestMockBoard() models.Board {
+	id := uuid.New()
 	return models.Board{
-		Id:         uuid.New(),
+		Id:         id,
 		Name:       "test_board",
 		Template:   models.Agile,
-		Columns:    []models.ColumnType{models.WentWell, models.ToImprove, models.Action},
+		Columns:    TestMockBoardColumns(id),
+		Settings:   models.BoardSettings{VotesPerParticipant: 5, ActionColumn: models.Action},
+		Phase:      models.PhaseCollecting,
+		TeamId:     uuid.New(),
 		CreatedAt:  time.Now().UTC(),
 		ModifiedAt: time.Now().UTC(),
 	}
 }
 
+// TestMockBoardColumns are the went_well, to_improve and action columns in
+// that order.
+func TestMockBoardColumns(boardId uuid.UUID) []*models.BoardColumn {
+	columns := make([]*models.BoardColumn, 0)
+	for position, key := range []models.ColumnType{models.WentWell, models.ToImprove, models.Action} {
+		columns = append(columns, models.NewBoardColumn(boardId, position, &models.CreateBoardColumnRequest{
+			Key:   key,
+			Title: string(key),
+		}))
+	}
+
+	return columns
+}
+
+// TestMockBoardTemplate is a custom team template with a risks and an
+// actions column.
+func TestMockBoardTemplate() models.BoardTemplate {
+	return *models.NewBoardTemplate(uuid.New(), uuid.New(), &models.CreateBoardTemplateRequest{
+		Key:  "team_template",
+		Name: "Team template",
+		Columns: []models.CreateBoardColumnRequest{
+			{Key: "risks", Title: "Risks"},
+			{Key: "actions", Title: "Actions"},
+		},
+		Settings: models.BoardSettings{VotesPerParticipant: 3, ActionColumn: "actions"},
+	})
+}
+
+func TestMockBoardMember(role models.BoardRole) models.BoardMember {
+	return *models.NewBoardMember(uuid.New(), uuid.New(), role)
+}
+
+// TestMockBoardInvitation is addressed to the email of TestMockUserResponse.
+func TestMockBoardInvitation() models.BoardInvitation {
+	return *models.NewBoardInvitation(uuid.New(), uuid.New(), "test_token_hash", &models.CreateBoardInvitationRequest{
+		Email: "test@email.com",
+		Role:  models.BoardParticipant,
+	})
+}
+
+func TestMockBoardJoinLink() models.BoardJoinLink {
+	return *models.NewBoardJoinLink(uuid.New(), uuid.New(), "test_token_hash", "test_pin_hash", &models.CreateBoardJoinLinkRequest{})
+}
+
 func TestMockFeedbacks() []*models.Feedback {
 	user := TestMockUserResponse()
 	feedbackA := models.Feedback{
 		Id:          uuid.New(),
 		Message:     "this is feedback A",
+		Column:      models.WentWell,
 		CreatedById: user.Id,
 		CreatedBy:   &user,
 		CreatedAt:   time.Now().UTC(),
@@ -147,6 +270,7 @@ func TestMockFeedbacks() []*models.Feedback {
 	feedbackB := models.Feedback{
 		Id:          uuid.New(),
 		Message:     "this is feedback B",
+		Column:      models.ToImprove,
 		CreatedById: user.Id,
 		CreatedBy:   &user,
 		CreatedAt:   time.Now().UTC(),
@@ -162,7 +286,16 @@ func TestMockFeedback() models.Feedback {
 	return models.Feedback{
 		Id:         uuid.New(),
 		Message:    "this is feedback",
+		Column:     models.WentWell,
 		CreatedAt:  time.Now().UTC(),
 		ModifiedAt: time.Now().UTC(),
 	}
 }
+
+func TestMockAuditEvents() []*models.AuditEvent {
+	actorId := uuid.New()
+	board := TestMockBoard()
+	eventA, _ := models.NewAuditEvent(&actorId, models.AuditBoardDeleted, models.AuditTargetBoard, board.Id.String(), board, nil, "127.0.0.1")
+	eventB, _ := models.NewAuditEvent(nil, models.AuditCacheCleared, models.AuditTargetCache, "", nil, nil, "=1+1")
+	return []*models.AuditEvent{eventA, eventB}
+}
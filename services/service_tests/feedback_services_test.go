@@ -9,9 +9,11 @@ import (
 	"testing"
 
 	"github.com/Aakash-Pandit/reetro-golang/core"
-	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
 	"github.com/Aakash-Pandit/reetro-golang/services"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
 	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 )
@@ -22,19 +24,14 @@ func TestGetAllFeedbacksHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	user := TestMockUser()
-	token, _ := middlewares.GenerateJSONWebToken(user.Id.String(), user.Email)
-
-	token = fmt.Sprintf("Bearer %s", token)
-	req.Header.Set("Authorization", token)
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
 
 	rr := httptest.NewRecorder()
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
-	requestUser := services.NewRequestUser(mockRequestUser)
 	mockRepo := new(MockStorage)
-	feedbackService := services.NewFeedbackService(mockRepo, *requestUser, mockRedisClient)
+	feedbackService := services.NewFeedbackService(mockRepo, mockRedisClient)
 
 	r := mux.NewRouter()
 	r.HandleFunc("/feedbacks/", core.HTTPHandleFunc(feedbackService.GetAllFeedbacksHandler)).Methods(http.MethodGet)
@@ -65,13 +62,14 @@ func TestGetFeedbackByIdHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
 	rr := httptest.NewRecorder()
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
-	requestUser := services.NewRequestUser(mockRequestUser)
 	mockRepo := new(MockStorage)
-	feedbackService := services.NewFeedbackService(mockRepo, *requestUser, mockRedisClient)
+	feedbackService := services.NewFeedbackService(mockRepo, mockRedisClient)
 
 	r := mux.NewRouter()
 	r.HandleFunc("/feedbacks/{id}/", core.HTTPHandleFunc(feedbackService.GetFeedbackByIdHandler)).Methods(http.MethodGet)
@@ -92,21 +90,16 @@ func TestCreateFeedbackHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	user := TestMockUser()
-	token, _ := middlewares.GenerateJSONWebToken(user.Id.String(), user.Email)
-
-	token = fmt.Sprintf("Bearer %s", token)
-	req.Header.Set("Authorization", token)
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
 
 	rr := httptest.NewRecorder()
 
 	mockRepo := new(MockStorage)
 	mockRepo.On("CreateFeedback", testFeedback).Return(testFeedback, nil)
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
-	requestUser := services.NewRequestUser(mockRequestUser)
-	userService := services.NewFeedbackService(mockRepo, *requestUser, mockRedisClient)
+	userService := services.NewFeedbackService(mockRepo, mockRedisClient)
 
 	r := mux.NewRouter()
 	r.HandleFunc("/feedbacks/", core.HTTPHandleFunc(userService.CreateFeedbackHandler)).Methods(http.MethodPost)
@@ -129,21 +122,16 @@ func TestUpdateFeedbackHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	user := TestMockUser()
-	token, _ := middlewares.GenerateJSONWebToken(user.Id.String(), user.Email)
-
-	token = fmt.Sprintf("Bearer %s", token)
-	req.Header.Set("Authorization", token)
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
 
 	rr := httptest.NewRecorder()
 
 	mockRepo := new(MockStorage)
 	mockRepo.On("UpdateFeedback", testFeedback).Return(testFeedback, nil)
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
-	requestUser := services.NewRequestUser(mockRequestUser)
-	feedbackService := services.NewFeedbackService(mockRepo, *requestUser, mockRedisClient)
+	feedbackService := services.NewFeedbackService(mockRepo, mockRedisClient)
 
 	r := mux.NewRouter()
 	r.HandleFunc("/feedbacks/{id}/", core.HTTPHandleFunc(feedbackService.UpdateFeedbackHandler)).Methods(http.MethodPatch)
@@ -159,18 +147,20 @@ func TestDeleteFeedbackHandler(t *testing.T) {
 	id := testFeedback.Id.String()
 	url := fmt.Sprintf("/feedbacks/%s/", id)
 
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	payload, _ := json.Marshal(models.RemoveFeedbackRequest{Reason: "Off topic"})
+	req, err := http.NewRequest(http.MethodDelete, url, bytes.NewBuffer(payload))
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
 	rr := httptest.NewRecorder()
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
-	requestUser := services.NewRequestUser(mockRequestUser)
 	mockRepo := new(MockStorage)
-	feedbackService := services.NewFeedbackService(mockRepo, *requestUser, mockRedisClient)
+	feedbackService := services.NewFeedbackService(mockRepo, mockRedisClient)
 
 	r := mux.NewRouter()
 	r.HandleFunc("/feedbacks/{id}/", core.HTTPHandleFunc(feedbackService.DeleteFeedbackHandler)).Methods(http.MethodDelete)
@@ -180,3 +170,244 @@ func TestDeleteFeedbackHandler(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
+
+func TestCreateFeedbackHandlerWithUnverifiedEmail(t *testing.T) {
+	t.Setenv("EMAIL_VERIFICATION_REQUIRED_FOR_FEEDBACK", "true")
+
+	testFeedback := TestMockFeedback()
+
+	payload, _ := json.Marshal(testFeedback)
+	req, err := http.NewRequest(http.MethodPost, "/feedbacks/", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	feedbackService := services.NewFeedbackService(mockRepo, mockRedisClient)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/feedbacks/", core.HTTPHandleFunc(feedbackService.CreateFeedbackHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestGetFeedbackByIdHandlerForNonMember(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/feedbacks/%s/", uuid.New()), nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+
+	feedbackService := services.NewFeedbackService(new(MockNonMemberStorage), new(storages_tests.MockRedisClient))
+
+	r := mux.NewRouter()
+	r.HandleFunc("/feedbacks/{id}/", core.HTTPHandleFunc(feedbackService.GetFeedbackByIdHandler)).Methods(http.MethodGet)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestCreateFeedbackHandlerForNonMember(t *testing.T) {
+	payload, _ := json.Marshal(TestMockFeedback())
+	req, _ := http.NewRequest(http.MethodPost, "/feedbacks/", bytes.NewBuffer(payload))
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+
+	feedbackService := services.NewFeedbackService(new(MockNonMemberStorage), new(storages_tests.MockRedisClient))
+
+	r := mux.NewRouter()
+	r.HandleFunc("/feedbacks/", core.HTTPHandleFunc(feedbackService.CreateFeedbackHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func feedbackRouter(store storages.Storage) *mux.Router {
+	feedbackService := services.NewFeedbackService(store, new(storages_tests.MockRedisClient))
+
+	r := mux.NewRouter()
+	r.HandleFunc("/feedbacks/{id}/", core.HTTPHandleFunc(feedbackService.UpdateFeedbackHandler)).Methods(http.MethodPatch)
+	r.HandleFunc("/feedbacks/{id}/", core.HTTPHandleFunc(feedbackService.DeleteFeedbackHandler)).Methods(http.MethodDelete)
+	r.HandleFunc("/feedbacks/{id}/move/", core.HTTPHandleFunc(feedbackService.MoveFeedbackHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{id}/feedback_removals/", core.HTTPHandleFunc(feedbackService.GetFeedbackRemovalsHandler)).Methods(http.MethodGet)
+	return r
+}
+
+func TestUpdateFeedbackHandlerForAuthor(t *testing.T) {
+	user := TestTeamMemberUser()
+
+	payload, _ := json.Marshal(models.UpdateFeedbackRequest{Message: "changed feedback"})
+	req, _ := http.NewRequest(http.MethodPatch, fmt.Sprintf("/feedbacks/%s/", uuid.New()), bytes.NewBuffer(payload))
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	feedbackRouter(&MockFeedbackAuthorStorage{AuthorId: user.Id}).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestUpdateFeedbackHandlerForOtherParticipant(t *testing.T) {
+	user := TestTeamMemberUser()
+
+	payload, _ := json.Marshal(models.UpdateFeedbackRequest{Message: "changed feedback"})
+	req, _ := http.NewRequest(http.MethodPatch, fmt.Sprintf("/feedbacks/%s/", uuid.New()), bytes.NewBuffer(payload))
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	feedbackRouter(&MockFeedbackAuthorStorage{AuthorId: uuid.New()}).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestUpdateFeedbackHandlerForFacilitator(t *testing.T) {
+	user := TestTeamMemberUser()
+
+	payload, _ := json.Marshal(models.UpdateFeedbackRequest{Message: "changed feedback"})
+	req, _ := http.NewRequest(http.MethodPatch, fmt.Sprintf("/feedbacks/%s/", uuid.New()), bytes.NewBuffer(payload))
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	feedbackRouter(new(MockTeamMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestDeleteFeedbackHandlerForAuthor(t *testing.T) {
+	user := TestTeamMemberUser()
+
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/feedbacks/%s/", uuid.New()), nil)
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	feedbackRouter(&MockFeedbackAuthorStorage{AuthorId: user.Id}).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestDeleteFeedbackHandlerForOtherParticipant(t *testing.T) {
+	user := TestTeamMemberUser()
+
+	payload, _ := json.Marshal(models.RemoveFeedbackRequest{Reason: "Off topic"})
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/feedbacks/%s/", uuid.New()), bytes.NewBuffer(payload))
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	feedbackRouter(&MockFeedbackAuthorStorage{AuthorId: uuid.New()}).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestDeleteFeedbackHandlerByModeratorWithoutReason(t *testing.T) {
+	user := TestTeamMemberUser()
+
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/feedbacks/%s/", uuid.New()), bytes.NewBufferString("{}"))
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	feedbackRouter(new(MockTeamMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetFeedbackRemovalsHandler(t *testing.T) {
+	user := TestTeamMemberUser()
+
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/boards/%s/feedback_removals/", uuid.New()), nil)
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	feedbackRouter(new(MockTeamMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var listAPIResponse core.ListAPIResponseBody
+	json.Unmarshal(rr.Body.Bytes(), &listAPIResponse)
+	assert.Equal(t, 1, listAPIResponse.Count)
+}
+
+func TestGetFeedbackRemovalsHandlerForParticipant(t *testing.T) {
+	user := TestTeamMemberUser()
+
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/boards/%s/feedback_removals/", uuid.New()), nil)
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	feedbackRouter(new(MockBoardParticipantStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestCreateFeedbackHandlerWithColumnNotOnBoard(t *testing.T) {
+	testFeedback := TestMockFeedback()
+	testFeedback.Column = models.ShoutOut
+
+	payload, _ := json.Marshal(testFeedback)
+	req, _ := http.NewRequest(http.MethodPost, "/feedbacks/", bytes.NewBuffer(payload))
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+
+	feedbackService := services.NewFeedbackService(new(MockStorage), new(storages_tests.MockRedisClient))
+
+	r := mux.NewRouter()
+	r.HandleFunc("/feedbacks/", core.HTTPHandleFunc(feedbackService.CreateFeedbackHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestMoveFeedbackHandlerForAuthor(t *testing.T) {
+	user := TestTeamMemberUser()
+
+	payload, _ := json.Marshal(models.MoveFeedbackRequest{Column: models.Action})
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/feedbacks/%s/move/", uuid.New()), bytes.NewBuffer(payload))
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	feedbackRouter(&MockFeedbackAuthorStorage{AuthorId: user.Id}).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var feedback models.Feedback
+	json.Unmarshal(rr.Body.Bytes(), &feedback)
+	assert.Equal(t, models.Action, feedback.Column)
+}
+
+func TestMoveFeedbackHandlerWithColumnNotOnBoard(t *testing.T) {
+	user := TestTeamMemberUser()
+
+	payload, _ := json.Marshal(models.MoveFeedbackRequest{Column: models.ShoutOut})
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/feedbacks/%s/move/", uuid.New()), bytes.NewBuffer(payload))
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	feedbackRouter(&MockFeedbackAuthorStorage{AuthorId: user.Id}).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestMoveFeedbackHandlerForOtherParticipant(t *testing.T) {
+	user := TestTeamMemberUser()
+
+	payload, _ := json.Marshal(models.MoveFeedbackRequest{Column: models.Action})
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/feedbacks/%s/move/", uuid.New()), bytes.NewBuffer(payload))
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	feedbackRouter(&MockFeedbackAuthorStorage{AuthorId: uuid.New()}).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
@@ -0,0 +1,206 @@
+package service_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Aakash-Pandit/reetro-golang/common/common_tests"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/services"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func guestRouter(store storages.Storage) *mux.Router {
+	boardService := services.NewBoardService(store, new(storages_tests.MockRedisClient), new(common_tests.MockEmail))
+
+	r := mux.NewRouter()
+	r.HandleFunc("/join/", core.HTTPHandleFunc(boardService.JoinBoardHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{id}/", core.HTTPHandleFunc(boardService.GetBoardByIdHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{id}/join_links/", core.HTTPHandleFunc(boardService.CreateBoardJoinLinkHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{id}/join_links/{link_id}/", core.HTTPHandleFunc(boardService.RevokeBoardJoinLinkHandler)).Methods(http.MethodDelete)
+	r.HandleFunc("/boards/{id}/close/", core.HTTPHandleFunc(boardService.CloseBoardHandler)).Methods(http.MethodPost)
+	return r
+}
+
+func TestCreateBoardJoinLinkHandler(t *testing.T) {
+	payload, _ := json.Marshal(models.CreateBoardJoinLinkRequest{ExpiresInHours: 2})
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/boards/%s/join_links/", uuid.New()), bytes.NewBuffer(payload))
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	guestRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var link models.CreateBoardJoinLinkResponse
+	json.Unmarshal(rr.Body.Bytes(), &link)
+	assert.NotEmpty(t, link.Token)
+	assert.Len(t, link.Pin, models.BOARD_JOIN_PIN_LENGTH)
+	assert.True(t, strings.HasSuffix(link.URL, "?token="+link.Token))
+	assert.NotContains(t, rr.Body.String(), "token_hash")
+}
+
+func TestCreateBoardJoinLinkHandlerForNonMember(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/boards/%s/join_links/", uuid.New()), bytes.NewBufferString("{}"))
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	guestRouter(new(MockNonMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestCreateBoardJoinLinkHandlerForClosedBoard(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/boards/%s/join_links/", uuid.New()), bytes.NewBufferString("{}"))
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	guestRouter(new(MockClosedBoardStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestRevokeBoardJoinLinkHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/boards/%s/join_links/%s/", uuid.New(), uuid.New()), nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	guestRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestJoinBoardHandlerWithToken(t *testing.T) {
+	payload, _ := json.Marshal(models.JoinBoardRequest{Token: "join_token", DisplayName: "Contractor"})
+	req, _ := http.NewRequest(http.MethodPost, "/join/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+	guestRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var joined models.JoinBoardResponse
+	json.Unmarshal(rr.Body.Bytes(), &joined)
+	assert.Equal(t, "Contractor", joined.User.FirstName)
+	assert.Equal(t, models.GuestUser, joined.User.UserType)
+	assert.True(t, strings.HasSuffix(joined.User.Email, "@"+models.GUEST_EMAIL_DOMAIN))
+
+	claims, err := middlewares.ValidateTokenOfType(joined.Token, middlewares.ACCESS_TOKEN)
+	assert.Nil(t, err)
+
+	boardId, ok := middlewares.GuestBoardIdFromClaims(claims)
+	assert.True(t, ok)
+	assert.Equal(t, joined.BoardId, boardId)
+}
+
+func TestJoinBoardHandlerWithPin(t *testing.T) {
+	payload, _ := json.Marshal(models.JoinBoardRequest{Pin: "12345678", DisplayName: "Contractor"})
+	req, _ := http.NewRequest(http.MethodPost, "/join/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+	guestRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
+
+func TestJoinBoardHandlerWithoutTokenOrPin(t *testing.T) {
+	payload, _ := json.Marshal(models.JoinBoardRequest{DisplayName: "Contractor"})
+	req, _ := http.NewRequest(http.MethodPost, "/join/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+	guestRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestJoinBoardHandlerWithRevokedLink(t *testing.T) {
+	payload, _ := json.Marshal(models.JoinBoardRequest{Token: "join_token", DisplayName: "Contractor"})
+	req, _ := http.NewRequest(http.MethodPost, "/join/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+	guestRouter(new(MockRevokedJoinLinkStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestJoinBoardHandlerForClosedBoard(t *testing.T) {
+	payload, _ := json.Marshal(models.JoinBoardRequest{Token: "join_token", DisplayName: "Contractor"})
+	req, _ := http.NewRequest(http.MethodPost, "/join/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+	guestRouter(new(MockClosedBoardStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestJoinBoardHandlerWhenLockedOut(t *testing.T) {
+	payload, _ := json.Marshal(models.JoinBoardRequest{Pin: "12345678", DisplayName: "Contractor"})
+	req, _ := http.NewRequest(http.MethodPost, "/join/", bytes.NewBuffer(payload))
+
+	boardService := services.NewBoardService(new(MockStorage), new(MockLockedRedisClient), new(common_tests.MockEmail))
+
+	rr := httptest.NewRecorder()
+	core.HTTPHandleFunc(boardService.JoinBoardHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+}
+
+func TestGetBoardByIdHandlerForGuestOfAnotherBoard(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/boards/%s/", uuid.New()), nil)
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+	req = req.WithContext(middlewares.WithGuestBoard(req.Context(), uuid.New()))
+
+	rr := httptest.NewRecorder()
+	guestRouter(new(MockNonMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestCloseBoardHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/boards/%s/close/", uuid.New()), nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	guestRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var board models.Board
+	json.Unmarshal(rr.Body.Bytes(), &board)
+	assert.True(t, board.IsClosed())
+}
+
+func TestCloseBoardHandlerForClosedBoard(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/boards/%s/close/", uuid.New()), nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	guestRouter(new(MockClosedBoardStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
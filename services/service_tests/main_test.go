@@ -0,0 +1,13 @@
+package service_tests
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain signs the tokens of the tests with an ephemeral key, the way a
+// development server without JWT_KEYS_DIR does.
+func TestMain(m *testing.M) {
+	os.Setenv("JWT_EPHEMERAL_KEYS", "true")
+	os.Exit(m.Run())
+}
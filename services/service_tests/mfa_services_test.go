@@ -0,0 +1,237 @@
+package service_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/common/common_tests"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/services"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func mfaRouter(store storages.Storage) *mux.Router {
+	return mfaRouterWithRedis(store, new(storages_tests.MockRedisClient))
+}
+
+func mfaRouterWithRedis(store storages.Storage, redisClient storages.RedisStoreInterface) *mux.Router {
+	userService := services.NewUserService(store, redisClient, new(common_tests.MockEmail), middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/login/", core.HTTPHandleFunc(userService.LoginHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/login/mfa/", core.HTTPHandleFunc(userService.LoginMFAHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/users/me/mfa/", core.HTTPHandleFunc(userService.EnrollMFAHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/users/me/mfa/confirm/", core.HTTPHandleFunc(userService.ConfirmMFAHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/users/{id}/mfa/", core.HTTPHandleFunc(userService.ResetUserMFAHandler)).Methods(http.MethodDelete)
+	return r
+}
+
+func currentTestMFACode() string {
+	code, _ := common.TOTPCodeAtStep(TEST_MFA_SECRET, common.TOTPStep(time.Now()))
+	return code
+}
+
+func TestEnrollMFAHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/", nil)
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	mfaRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var enrollment models.MFAEnrollmentResponse
+	json.Unmarshal(rr.Body.Bytes(), &enrollment)
+
+	assert.NotEmpty(t, enrollment.Secret)
+	assert.True(t, strings.HasPrefix(enrollment.OTPAuthURI, "otpauth://totp/"))
+}
+
+func TestEnrollMFAHandlerWhenEnabled(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/", nil)
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	mfaRouter(new(MockMFAStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestConfirmMFAHandler(t *testing.T) {
+	payload, _ := json.Marshal(services.MFACodePayload{Code: currentTestMFACode()})
+	req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/confirm/", bytes.NewBuffer(payload))
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	mfaRouter(new(MockPendingMFAStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var recoveryCodes models.MFARecoveryCodesResponse
+	json.Unmarshal(rr.Body.Bytes(), &recoveryCodes)
+
+	assert.Len(t, recoveryCodes.RecoveryCodes, models.MFA_RECOVERY_CODE_COUNT)
+}
+
+func TestConfirmMFAHandlerWithInvalidCode(t *testing.T) {
+	payload, _ := json.Marshal(services.MFACodePayload{Code: "000000x"})
+	req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/confirm/", bytes.NewBuffer(payload))
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	mfaRouter(new(MockPendingMFAStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestLoginHandlerWithMFA(t *testing.T) {
+	payload, _ := json.Marshal(TestLoginPayload())
+	req, _ := http.NewRequest(http.MethodPost, "/login/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+	mfaRouter(new(MockMFAStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(rr.Body.Bytes(), &response)
+
+	assert.Equal(t, true, response["mfa_required"])
+	assert.NotEmpty(t, response["mfa_token"])
+	assert.Nil(t, response["token"])
+}
+
+func TestLoginMFAHandler(t *testing.T) {
+	user := TestMockUser()
+	mfaToken, _ := middlewares.GenerateMFAPendingToken(user.Id.String(), user.Email)
+
+	payload, _ := json.Marshal(services.MFALoginPayload{MFAToken: mfaToken, Code: currentTestMFACode()})
+	req, _ := http.NewRequest(http.MethodPost, "/login/mfa/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+	mfaRouter(new(MockMFAStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var tokenResponse core.TokenResponse
+	json.Unmarshal(rr.Body.Bytes(), &tokenResponse)
+
+	assert.NotEmpty(t, tokenResponse.Token)
+	assert.NotEmpty(t, tokenResponse.RefreshToken)
+}
+
+func TestLoginMFAHandlerWithRecoveryCode(t *testing.T) {
+	user := TestMockUser()
+	mfaToken, _ := middlewares.GenerateMFAPendingToken(user.Id.String(), user.Email)
+
+	payload, _ := json.Marshal(services.MFALoginPayload{MFAToken: mfaToken, Code: "ABCDE-12345"})
+	req, _ := http.NewRequest(http.MethodPost, "/login/mfa/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+	mfaRouter(new(MockMFAStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestLoginMFAHandlerWithInvalidCode(t *testing.T) {
+	user := TestMockUser()
+	mfaToken, _ := middlewares.GenerateMFAPendingToken(user.Id.String(), user.Email)
+
+	payload, _ := json.Marshal(services.MFALoginPayload{MFAToken: mfaToken, Code: "wrong-code"})
+	req, _ := http.NewRequest(http.MethodPost, "/login/mfa/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+	mfaRouter(new(MockMFAStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestLoginHandlerWithMFAKeepsFailedCodes(t *testing.T) {
+	user := TestMockUser()
+	redisClient := new(MockCountingRedisClient)
+	router := mfaRouterWithRedis(new(MockMFAStorage), redisClient)
+
+	mfaToken, _ := middlewares.GenerateMFAPendingToken(user.Id.String(), user.Email)
+	payload, _ := json.Marshal(services.MFALoginPayload{MFAToken: mfaToken, Code: "wrong-code"})
+	req, _ := http.NewRequest(http.MethodPost, "/login/mfa/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	mfaAttemptsKey := services.LOGIN_ATTEMPTS_PREFIX + string(models.MFALockout) + ":" + user.Username
+	assert.Equal(t, int64(1), redisClient.Counts[mfaAttemptsKey])
+
+	// The right password again does not start the codes over.
+	payload, _ = json.Marshal(TestLoginPayload())
+	req, _ = http.NewRequest(http.MethodPost, "/login/", bytes.NewBuffer(payload))
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, int64(1), redisClient.Counts[mfaAttemptsKey])
+
+	payload, _ = json.Marshal(services.MFALoginPayload{MFAToken: mfaToken, Code: currentTestMFACode()})
+	req, _ = http.NewRequest(http.MethodPost, "/login/mfa/", bytes.NewBuffer(payload))
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, redisClient.Counts, mfaAttemptsKey)
+}
+
+func TestLoginMFAHandlerReplayingCode(t *testing.T) {
+	user := TestMockUser()
+	mfaToken, _ := middlewares.GenerateMFAPendingToken(user.Id.String(), user.Email)
+
+	payload, _ := json.Marshal(services.MFALoginPayload{MFAToken: mfaToken, Code: currentTestMFACode()})
+	req, _ := http.NewRequest(http.MethodPost, "/login/mfa/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+	mfaRouter(new(MockUsedMFAStepStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestLoginMFAHandlerWithAccessToken(t *testing.T) {
+	user := TestMockUser()
+	tokens, _ := middlewares.GenerateTokenPair(user.Id.String(), user.Email, uuid.New().String())
+
+	payload, _ := json.Marshal(services.MFALoginPayload{MFAToken: tokens.Token, Code: currentTestMFACode()})
+	req, _ := http.NewRequest(http.MethodPost, "/login/mfa/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+	mfaRouter(new(MockMFAStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestResetUserMFAHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/users/%s/mfa/", uuid.New()), nil)
+
+	rr := httptest.NewRecorder()
+	mfaRouter(new(MockMFAStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -1,7 +1,12 @@
 package service_tests
 
 import (
+	"database/sql"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
 	"github.com/Aakash-Pandit/reetro-golang/models"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
@@ -11,8 +16,426 @@ type MockStorage struct {
 	mock.Mock
 }
 
-type MockRequestUserStorage struct {
-	mock.Mock
+// MockLockedRedisClient answers as if every lockout key is set.
+type MockLockedRedisClient struct {
+	storages_tests.MockRedisClient
+}
+
+func (m *MockLockedRedisClient) Exists(key string) (bool, error) {
+	return true, nil
+}
+
+func (m *MockLockedRedisClient) TTL(key string) (time.Duration, error) {
+	return time.Minute, nil
+}
+
+// MockCountingRedisClient keeps the counters increased through it in Counts
+// and deletes them again.
+type MockCountingRedisClient struct {
+	storages_tests.MockRedisClient
+	Counts map[string]int64
+}
+
+func (m *MockCountingRedisClient) Incr(key string, expiration time.Duration) (int64, error) {
+	if m.Counts == nil {
+		m.Counts = make(map[string]int64)
+	}
+
+	m.Counts[key]++
+	return m.Counts[key], nil
+}
+
+func (m *MockCountingRedisClient) Del(key string) error {
+	delete(m.Counts, key)
+	return nil
+}
+
+// MockDeletingRedisClient keeps the keys deleted through it in Deleted.
+type MockDeletingRedisClient struct {
+	storages_tests.MockRedisClient
+	Deleted []string
+}
+
+func (m *MockDeletingRedisClient) Del(key string) error {
+	m.Deleted = append(m.Deleted, key)
+	return nil
+}
+
+// MockMFAStorage answers as if every user enabled two-factor authentication
+// with TEST_MFA_SECRET and TEST_MFA_RECOVERY_CODE.
+type MockMFAStorage struct {
+	MockStorage
+}
+
+func (m *MockMFAStorage) GetUserMFA(userId uuid.UUID) (*models.UserMFA, error) {
+	mfa := TestMockUserMFA()
+	mfa.UserId = userId
+	return &mfa, nil
+}
+
+func (m *MockMFAStorage) UseMFARecoveryCode(userId uuid.UUID, codeHash string) error {
+	if codeHash != common.HashToken(TEST_MFA_RECOVERY_CODE) {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// MockUsedMFAStepStorage answers as if the time step of every code was
+// already used, by an earlier login or a concurrent one.
+type MockUsedMFAStepStorage struct {
+	MockMFAStorage
+}
+
+func (m *MockUsedMFAStepStorage) UseMFAStep(userId uuid.UUID, step int64) error {
+	return sql.ErrNoRows
+}
+
+// MockPendingMFAStorage answers as if every user started enrolling two-factor
+// authentication with TEST_MFA_SECRET without confirming it yet.
+type MockPendingMFAStorage struct {
+	MockStorage
+}
+
+func (m *MockPendingMFAStorage) GetUserMFA(userId uuid.UUID) (*models.UserMFA, error) {
+	return models.NewUserMFA(userId, TEST_MFA_SECRET), nil
+}
+
+// MockRevokedSessionStorage answers as if every session was revoked.
+type MockRevokedSessionStorage struct {
+	MockStorage
+}
+
+func (m *MockRevokedSessionStorage) GetUserSession(id uuid.UUID) (*models.UserSession, error) {
+	session := TestMockUserSession()
+	revokedAt := time.Now().UTC()
+	session.Id = id
+	session.RevokedAt = &revokedAt
+	return &session, nil
+}
+
+// MockActiveSessionStorage answers as if every session was an active one of
+// the user.
+type MockActiveSessionStorage struct {
+	MockStorage
+	UserId uuid.UUID
+}
+
+func (m *MockActiveSessionStorage) GetUserSession(id uuid.UUID) (*models.UserSession, error) {
+	session := *models.NewUserSession(m.UserId, "test_user_agent", "127.0.0.1", time.Hour)
+	session.Id = id
+	return &session, nil
+}
+
+// MockOIDCStorage answers for a user of the given role whose email is
+// verified or not, linked to the subject of the provider or not. It keeps
+// the identities it links and the users it updates.
+type MockOIDCStorage struct {
+	MockStorage
+	Linked        bool
+	EmailVerified bool
+	UserType      models.Role
+	Identities    []models.UserIdentity
+	Updated       []models.CreateUserResponse
+}
+
+func (m *MockOIDCStorage) user() *models.User {
+	user := TestMockUser()
+	user.EmailVerified = m.EmailVerified
+	user.UserType = m.UserType
+	return &user
+}
+
+func (m *MockOIDCStorage) GetUserByIdentity(provider, subject string) (*models.User, error) {
+	if !m.Linked {
+		return nil, sql.ErrNoRows
+	}
+	return m.user(), nil
+}
+
+func (m *MockOIDCStorage) VerifyUserByEmail(email string) (*models.User, error) {
+	user := m.user()
+	user.Email = email
+	return user, nil
+}
+
+func (m *MockOIDCStorage) CreateUserIdentity(identity models.UserIdentity) error {
+	m.Identities = append(m.Identities, identity)
+	return nil
+}
+
+func (m *MockOIDCStorage) UpdateUser(user models.CreateUserResponse) (models.CreateUserResponse, error) {
+	m.Updated = append(m.Updated, user)
+	return user, nil
+}
+
+// MockCreatedUserStorage keeps the users created through it in Users.
+type MockCreatedUserStorage struct {
+	MockStorage
+	Users []models.User
+}
+
+func (m *MockCreatedUserStorage) CreateUser(user models.User) (models.User, error) {
+	m.Users = append(m.Users, user)
+	return user, nil
+}
+
+// MockUsedResetTokenStorage answers as if the reset token was redeemed by
+// another request in the meantime.
+type MockUsedResetTokenStorage struct {
+	MockStorage
+}
+
+func (m *MockUsedResetTokenStorage) UsePasswordResetToken(tokenHash string) (uuid.UUID, error) {
+	return uuid.Nil, sql.ErrNoRows
+}
+
+// MockResetPasswordUserStorage answers with a user named Username and
+// records whether the reset token was redeemed.
+type MockResetPasswordUserStorage struct {
+	MockStorage
+	Username string
+	Redeemed bool
+}
+
+func (m *MockResetPasswordUserStorage) GetUserById(id uuid.UUID) (*models.CreateUserResponse, error) {
+	user := TestMockUserResponse()
+	user.Id = id
+	user.Username = m.Username
+	return &user, nil
+}
+
+func (m *MockResetPasswordUserStorage) UsePasswordResetToken(tokenHash string) (uuid.UUID, error) {
+	m.Redeemed = true
+	return m.MockStorage.UsePasswordResetToken(tokenHash)
+}
+
+// MockNonMemberStorage answers as if the request user is not a member of any
+// team or board.
+type MockNonMemberStorage struct {
+	MockStorage
+}
+
+func (m *MockNonMemberStorage) GetTeamMembership(teamId, userId uuid.UUID) (*models.TeamMembership, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (m *MockNonMemberStorage) GetBoardMember(boardId, userId uuid.UUID) (*models.BoardMember, error) {
+	return nil, sql.ErrNoRows
+}
+
+// MockTeamMemberStorage answers as if the request user has the plain member
+// role in every team.
+type MockTeamMemberStorage struct {
+	MockStorage
+}
+
+func (m *MockTeamMemberStorage) GetTeamMembership(teamId, userId uuid.UUID) (*models.TeamMembership, error) {
+	membership := TestMockTeamMembership(models.TeamRoleMember)
+	membership.TeamId = teamId
+	membership.UserId = userId
+	return &membership, nil
+}
+
+// MockTeamAdminStorage answers as if AdminId is an admin of every team and
+// every other user one of its two owners.
+type MockTeamAdminStorage struct {
+	MockStorage
+	AdminId uuid.UUID
+}
+
+func (m *MockTeamAdminStorage) GetTeamMembership(teamId, userId uuid.UUID) (*models.TeamMembership, error) {
+	role := models.TeamRoleOwner
+	if userId == m.AdminId {
+		role = models.TeamRoleAdmin
+	}
+
+	membership := TestMockTeamMembership(role)
+	membership.TeamId = teamId
+	membership.UserId = userId
+	return &membership, nil
+}
+
+func (m *MockTeamAdminStorage) CountTeamOwners(teamId uuid.UUID) (int, error) {
+	return 2, nil
+}
+
+// MockBoardParticipantStorage answers as if the request user is a plain
+// member of every team and a participant of every board.
+type MockBoardParticipantStorage struct {
+	MockTeamMemberStorage
+}
+
+func (m *MockBoardParticipantStorage) GetBoardMember(boardId, userId uuid.UUID) (*models.BoardMember, error) {
+	member := TestMockBoardMember(models.BoardParticipant)
+	member.BoardId = boardId
+	member.UserId = userId
+	return &member, nil
+}
+
+// MockFeedbackAuthorStorage is MockBoardParticipantStorage where every
+// feedback was written by AuthorId.
+type MockFeedbackAuthorStorage struct {
+	MockBoardParticipantStorage
+	AuthorId uuid.UUID
+}
+
+func (m *MockFeedbackAuthorStorage) GetFeedbackById(id uuid.UUID) (*models.Feedback, error) {
+	feedback := TestMockFeedback()
+	feedback.Id = id
+	feedback.CreatedById = m.AuthorId
+	return &feedback, nil
+}
+
+// MockAuditStorage keeps the audit events recorded through it in Events.
+type MockAuditStorage struct {
+	MockStorage
+	Events []models.AuditEvent
+}
+
+func (m *MockAuditStorage) CreateAuditEvent(event models.AuditEvent) error {
+	m.Events = append(m.Events, event)
+	return nil
+}
+
+// MockAuditFilterStorage keeps the filters the audit events are read with
+// in Filters.
+type MockAuditFilterStorage struct {
+	MockStorage
+	Filters []models.AuditEventFilter
+}
+
+func (m *MockAuditFilterStorage) GetAuditEvents(filter models.AuditEventFilter, limit, offset int) ([]*models.AuditEvent, error) {
+	m.Filters = append(m.Filters, filter)
+	return m.MockStorage.GetAuditEvents(filter, limit, offset)
+}
+
+// MockSoleOwnerStorage answers as if every user was the only owner of a
+// team.
+type MockSoleOwnerStorage struct {
+	MockStorage
+}
+
+func (m *MockSoleOwnerStorage) GetTeamsOwnedOnlyBy(userId uuid.UUID) ([]*models.Team, error) {
+	return TestMockTeams()[:1], nil
+}
+
+// MockMissingUserStorage answers as if no user existed.
+type MockMissingUserStorage struct {
+	MockStorage
+}
+
+func (m *MockMissingUserStorage) DeleteUser(id uuid.UUID) error {
+	return sql.ErrNoRows
+}
+
+// MockBoardColumnStorage always answers with Board, whose columns have
+// ColumnFeedbacks feedback each. Creating a column fails with CreateErr
+// when it is set.
+type MockBoardColumnStorage struct {
+	MockStorage
+	Board           models.Board
+	ColumnFeedbacks int
+	CreateErr       error
+	FeedbackIds     []uuid.UUID
+}
+
+func (m *MockBoardColumnStorage) CreateBoardColumn(column models.BoardColumn) (models.BoardColumn, error) {
+	if m.CreateErr != nil {
+		return models.BoardColumn{}, m.CreateErr
+	}
+	return column, nil
+}
+
+func (m *MockBoardColumnStorage) GetBoardById(id uuid.UUID) (*models.Board, error) {
+	board := m.Board
+	return &board, nil
+}
+
+func (m *MockBoardColumnStorage) CountColumnFeedbacks(boardId uuid.UUID, key models.ColumnType) (int, error) {
+	return m.ColumnFeedbacks, nil
+}
+
+// RemoveBoardColumn answers as if FeedbackIds was the feedback of the column.
+func (m *MockBoardColumnStorage) RemoveBoardColumn(boardId, columnId uuid.UUID, moveTo models.ColumnType) ([]uuid.UUID, error) {
+	return m.FeedbackIds, nil
+}
+
+// MockBoardPhaseStorage answers as if every board was in Phase and the
+// request user had given Votes votes on it. Feedback is in a group when
+// Grouped is set, and in Column when that is set. The feedback of Stacked
+// is stacked onto every feedback and moves along when it is grouped.
+type MockBoardPhaseStorage struct {
+	MockStorage
+	Phase   models.BoardPhase
+	Votes   int
+	Grouped bool
+	Column  models.ColumnType
+	Stacked []uuid.UUID
+}
+
+func (m *MockBoardPhaseStorage) GetBoardById(id uuid.UUID) (*models.Board, error) {
+	board := TestMockBoard()
+	board.Id = id
+	board.Phase = m.Phase
+	return &board, nil
+}
+
+func (m *MockBoardPhaseStorage) GetFeedbackById(id uuid.UUID) (*models.Feedback, error) {
+	feedback := TestMockFeedback()
+	feedback.Id = id
+	if m.Column != "" {
+		feedback.Column = m.Column
+	}
+	if m.Grouped {
+		groupId := uuid.New()
+		feedback.GroupId = &groupId
+	}
+	return &feedback, nil
+}
+
+func (m *MockBoardPhaseStorage) GroupFeedback(id uuid.UUID, groupId *uuid.UUID, modifiedAt time.Time) ([]uuid.UUID, error) {
+	return append([]uuid.UUID{id}, m.Stacked...), nil
+}
+
+func (m *MockBoardPhaseStorage) CreateFeedbackVote(vote models.FeedbackVote, limit int) error {
+	if limit > 0 && m.Votes >= limit {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// MockClosedBoardStorage answers as if every board was already closed.
+type MockClosedBoardStorage struct {
+	MockStorage
+}
+
+func (m *MockClosedBoardStorage) GetBoardById(id uuid.UUID) (*models.Board, error) {
+	board := TestMockBoard()
+	closedAt := time.Now().UTC()
+	board.Id = id
+	board.ClosedAt = &closedAt
+	board.Phase = models.PhaseClosed
+	return &board, nil
+}
+
+func (m *MockClosedBoardStorage) CloseBoard(id uuid.UUID, closedAt time.Time) error {
+	return sql.ErrNoRows
+}
+
+// MockRevokedJoinLinkStorage answers as if every board join link was revoked.
+type MockRevokedJoinLinkStorage struct {
+	MockStorage
+}
+
+func (m *MockRevokedJoinLinkStorage) GetBoardJoinLinkByHash(tokenHash string) (*models.BoardJoinLink, error) {
+	link := TestMockBoardJoinLink()
+	revokedAt := time.Now().UTC()
+	link.RevokedAt = &revokedAt
+	return &link, nil
+}
+
+func (m *MockRevokedJoinLinkStorage) GetBoardJoinLinkByPin(pinHash string) (*models.BoardJoinLink, error) {
+	return nil, sql.ErrNoRows
 }
 
 func (m *MockStorage) GetAllUsers(int, int) ([]*models.CreateUserResponse, error) {
@@ -32,10 +455,28 @@ func (m *MockStorage) CreateUser(user models.User) (models.User, error) {
 	return TestMockUser(), nil
 }
 
+func (m *MockStorage) UpdateUser(user models.CreateUserResponse) (models.CreateUserResponse, error) {
+	return user, nil
+}
+
 func (m *MockStorage) DeleteUser(id uuid.UUID) error {
 	return nil
 }
 
+func (m *MockStorage) GetUserDataExport(userId uuid.UUID) (*models.UserDataExport, error) {
+	user := TestMockUserResponse()
+	user.Id = userId
+	return &models.UserDataExport{
+		ExportedAt:           time.Now().UTC(),
+		Profile:              &user,
+		Teams:                []*models.Team{},
+		Boards:               []*models.Board{},
+		Feedbacks:            []*models.Feedback{},
+		Sessions:             []*models.UserSession{},
+		PersonalAccessTokens: []*models.PersonalAccessToken{},
+	}, nil
+}
+
 func (m *MockStorage) VerifyUserByUsername(username string) (*models.User, error) {
 	user := TestMockUser()
 	user.Username = username
@@ -48,10 +489,24 @@ func (m *MockStorage) VerifyUserByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
+func (m *MockStorage) GetUserByIdentity(provider, subject string) (*models.User, error) {
+	user := TestMockUser()
+	user.EmailVerified = true
+	return &user, nil
+}
+
+func (m *MockStorage) CreateUserIdentity(identity models.UserIdentity) error {
+	return nil
+}
+
 func (m *MockStorage) SavePassword(user models.User) error {
 	return nil
 }
 
+func (m *MockStorage) VerifyUserEmail(id uuid.UUID) error {
+	return nil
+}
+
 func (m *MockStorage) VerifyUserByUsernamePassword(username, password string) (*models.User, error) {
 	user := TestMockUser()
 	user.Username = username
@@ -60,11 +515,173 @@ func (m *MockStorage) VerifyUserByUsernamePassword(username, password string) (*
 	return &user, nil
 }
 
+func (m *MockStorage) CreatePasswordResetToken(token models.PasswordResetToken) error {
+	return nil
+}
+
+func (m *MockStorage) GetPasswordResetTokenByHash(tokenHash string) (*models.PasswordResetToken, error) {
+	token := TestMockPasswordResetToken()
+	token.TokenHash = tokenHash
+	return &token, nil
+}
+
+func (m *MockStorage) UsePasswordResetToken(tokenHash string) (uuid.UUID, error) {
+	return TestMockPasswordResetToken().UserId, nil
+}
+
+func (m *MockStorage) MarkPasswordResetTokensUsed(userId uuid.UUID) error {
+	return nil
+}
+
+func (m *MockStorage) CreateAccountLockout(lockout models.AccountLockout) error {
+	return nil
+}
+
+func (m *MockStorage) UnlockAccountLockouts(username string, unlockedById uuid.UUID) error {
+	return nil
+}
+
+func (m *MockStorage) CreatePersonalAccessToken(token models.PersonalAccessToken) error {
+	return nil
+}
+
+func (m *MockStorage) GetPersonalAccessTokensByUserId(userId uuid.UUID) ([]*models.PersonalAccessToken, error) {
+	token := TestMockPersonalAccessToken()
+	token.UserId = userId
+	return []*models.PersonalAccessToken{&token}, nil
+}
+
+func (m *MockStorage) GetPersonalAccessTokenByHash(tokenHash string) (*models.PersonalAccessToken, error) {
+	token := TestMockPersonalAccessToken()
+	token.TokenHash = tokenHash
+	return &token, nil
+}
+
+func (m *MockStorage) RevokePersonalAccessToken(id, userId uuid.UUID) error {
+	return nil
+}
+
+func (m *MockStorage) TouchPersonalAccessToken(id uuid.UUID, lastUsedAt time.Time) error {
+	return nil
+}
+
+func (m *MockStorage) GetUserMFA(userId uuid.UUID) (*models.UserMFA, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (m *MockStorage) SaveUserMFA(mfa models.UserMFA) error {
+	return nil
+}
+
+func (m *MockStorage) DeleteUserMFA(userId uuid.UUID) error {
+	return nil
+}
+
+func (m *MockStorage) ReplaceMFARecoveryCodes(userId uuid.UUID, codes []models.MFARecoveryCode) error {
+	return nil
+}
+
+func (m *MockStorage) UseMFAStep(userId uuid.UUID, step int64) error {
+	return nil
+}
+
+func (m *MockStorage) UseMFARecoveryCode(userId uuid.UUID, codeHash string) error {
+	return sql.ErrNoRows
+}
+
+func (m *MockStorage) CreateUserSession(session models.UserSession) error {
+	return nil
+}
+
+func (m *MockStorage) GetUserSession(id uuid.UUID) (*models.UserSession, error) {
+	session := TestMockUserSession()
+	session.Id = id
+	return &session, nil
+}
+
+func (m *MockStorage) GetActiveUserSessions(userId uuid.UUID) ([]*models.UserSession, error) {
+	session := TestMockUserSession()
+	session.UserId = userId
+	return []*models.UserSession{&session}, nil
+}
+
+func (m *MockStorage) TouchUserSession(id uuid.UUID, lastSeenAt, expiresAt time.Time) error {
+	return nil
+}
+
+func (m *MockStorage) RevokeUserSession(id, userId uuid.UUID) error {
+	return nil
+}
+
+func (m *MockStorage) RevokeUserSessions(userId, exceptId uuid.UUID) error {
+	args := m.Called(userId, exceptId)
+	return args.Error(0)
+}
+
+func (m *MockStorage) GetAllTeams(int, int) ([]*models.Team, error) {
+	return TestMockTeams(), nil
+}
+
+func (m *MockStorage) GetTeamsForUser(userId uuid.UUID, limit, offset int) ([]*models.Team, error) {
+	return TestMockTeams()[:1], nil
+}
+
+func (m *MockStorage) GetTeamById(id uuid.UUID) (*models.Team, error) {
+	team := TestMockTeam()
+	team.Id = id
+	return &team, nil
+}
+
+func (m *MockStorage) CreateTeam(team models.Team, owner models.TeamMembership) error {
+	return nil
+}
+
+func (m *MockStorage) UpdateTeam(team models.Team) (models.Team, error) {
+	return team, nil
+}
+
+func (m *MockStorage) DeleteTeam(id uuid.UUID) error {
+	return nil
+}
+
+func (m *MockStorage) GetTeamMembers(teamId uuid.UUID) ([]*models.TeamMembership, error) {
+	membership := TestMockTeamMembership(models.TeamRoleOwner)
+	membership.TeamId = teamId
+	return []*models.TeamMembership{&membership}, nil
+}
+
+func (m *MockStorage) GetTeamMembership(teamId, userId uuid.UUID) (*models.TeamMembership, error) {
+	membership := TestMockTeamMembership(models.TeamRoleOwner)
+	membership.TeamId = teamId
+	membership.UserId = userId
+	return &membership, nil
+}
+
+func (m *MockStorage) SaveTeamMember(membership models.TeamMembership) error {
+	return nil
+}
+
+func (m *MockStorage) RemoveTeamMember(teamId, userId uuid.UUID) error {
+	return nil
+}
+
+func (m *MockStorage) CountTeamOwners(teamId uuid.UUID) (int, error) {
+	return 1, nil
+}
+
+func (m *MockStorage) GetTeamsOwnedOnlyBy(userId uuid.UUID) ([]*models.Team, error) {
+	return []*models.Team{}, nil
+}
+
 func (m *MockStorage) GetAllBoards(int, int) ([]*models.Board, error) {
 	boards := TestMockBoards()
 	return boards, nil
 }
 
+func (m *MockStorage) GetBoardsForUser(userId uuid.UUID, limit, offset int) ([]*models.Board, error) {
+	return TestMockBoards()[:1], nil
+}
+
 func (m *MockStorage) GetBoardById(id uuid.UUID) (*models.Board, error) {
 	board := TestMockBoard()
 	board.Id = id
@@ -85,10 +702,142 @@ func (m *MockStorage) DeleteBoard(id uuid.UUID) error {
 	return nil
 }
 
+func (m *MockStorage) GetBoardColumns(boardId uuid.UUID) ([]*models.BoardColumn, error) {
+	return TestMockBoardColumns(boardId), nil
+}
+
+func (m *MockStorage) CreateBoardColumn(column models.BoardColumn) (models.BoardColumn, error) {
+	return column, nil
+}
+
+func (m *MockStorage) UpdateBoardColumn(column models.BoardColumn) (models.BoardColumn, error) {
+	return column, nil
+}
+
+func (m *MockStorage) ReorderBoardColumns(boardId uuid.UUID, columnIds []uuid.UUID, modifiedAt time.Time) error {
+	return nil
+}
+
+func (m *MockStorage) CountColumnFeedbacks(boardId uuid.UUID, key models.ColumnType) (int, error) {
+	return 0, nil
+}
+
+func (m *MockStorage) RemoveBoardColumn(boardId, columnId uuid.UUID, moveTo models.ColumnType) ([]uuid.UUID, error) {
+	return []uuid.UUID{}, nil
+}
+
+func (m *MockStorage) GetBoardTemplates(teamId uuid.UUID) ([]*models.BoardTemplate, error) {
+	template := TestMockBoardTemplate()
+	template.TeamId = &teamId
+	return []*models.BoardTemplate{&template}, nil
+}
+
+// GetBoardTemplate only knows the key of TestMockBoardTemplate.
+func (m *MockStorage) GetBoardTemplate(teamId uuid.UUID, key models.TemplateType) (*models.BoardTemplate, error) {
+	template := TestMockBoardTemplate()
+	if key != template.Key {
+		return nil, sql.ErrNoRows
+	}
+
+	template.TeamId = &teamId
+	return &template, nil
+}
+
+func (m *MockStorage) CreateBoardTemplate(template models.BoardTemplate) (models.BoardTemplate, error) {
+	return template, nil
+}
+
+func (m *MockStorage) UpdateBoardTemplate(template models.BoardTemplate) (models.BoardTemplate, error) {
+	return template, nil
+}
+
+func (m *MockStorage) DeleteBoardTemplate(teamId uuid.UUID, key models.TemplateType) error {
+	return nil
+}
+
+func (m *MockStorage) GetBoardMembers(boardId uuid.UUID) ([]*models.BoardMember, error) {
+	member := TestMockBoardMember(models.BoardFacilitator)
+	member.BoardId = boardId
+	return []*models.BoardMember{&member}, nil
+}
+
+func (m *MockStorage) GetBoardMember(boardId, userId uuid.UUID) (*models.BoardMember, error) {
+	member := TestMockBoardMember(models.BoardFacilitator)
+	member.BoardId = boardId
+	member.UserId = userId
+	return &member, nil
+}
+
+func (m *MockStorage) RemoveBoardMember(boardId, userId uuid.UUID) error {
+	return nil
+}
+
+func (m *MockStorage) CreateBoardInvitation(invitation models.BoardInvitation) error {
+	return nil
+}
+
+func (m *MockStorage) GetBoardInvitationByHash(tokenHash string) (*models.BoardInvitation, error) {
+	invitation := TestMockBoardInvitation()
+	invitation.TokenHash = tokenHash
+	return &invitation, nil
+}
+
+func (m *MockStorage) AcceptBoardInvitation(invitationId uuid.UUID, member models.BoardMember) error {
+	return nil
+}
+
+func (m *MockStorage) CreateBoardJoinLink(link models.BoardJoinLink) error {
+	return nil
+}
+
+func (m *MockStorage) GetBoardJoinLinkByHash(tokenHash string) (*models.BoardJoinLink, error) {
+	link := TestMockBoardJoinLink()
+	link.TokenHash = tokenHash
+	return &link, nil
+}
+
+func (m *MockStorage) GetBoardJoinLinkByPin(pinHash string) (*models.BoardJoinLink, error) {
+	link := TestMockBoardJoinLink()
+	link.PinHash = pinHash
+	return &link, nil
+}
+
+func (m *MockStorage) RevokeBoardJoinLink(id, boardId uuid.UUID) error {
+	return nil
+}
+
+func (m *MockStorage) CreateGuestUser(user models.User, boardId uuid.UUID, expiresAt time.Time) error {
+	return nil
+}
+
+func (m *MockStorage) DeleteExpiredGuestUsers(now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockStorage) CloseBoard(id uuid.UUID, closedAt time.Time) error {
+	return nil
+}
+
+func (m *MockStorage) CloneBoard(board models.Board, carryOverFrom models.ColumnType) error {
+	return nil
+}
+
+func (m *MockStorage) GetBoardSeries(id uuid.UUID) ([]*models.Board, error) {
+	return TestMockBoards(), nil
+}
+
+func (m *MockStorage) UpdateBoardPhase(id uuid.UUID, from, to models.BoardPhase, modifiedAt time.Time) error {
+	return nil
+}
+
 func (m *MockStorage) GetAllFeedbacks(int, int) ([]*models.Feedback, error) {
 	return TestMockFeedbacks(), nil
 }
 
+func (m *MockStorage) GetFeedbacksForUser(userId uuid.UUID, limit, offset int) ([]*models.Feedback, error) {
+	return TestMockFeedbacks()[:1], nil
+}
+
 func (m *MockStorage) GetFeedbackById(id uuid.UUID) (*models.Feedback, error) {
 	feedback := TestMockFeedback()
 	feedback.Id = id
@@ -109,8 +858,41 @@ func (m *MockStorage) DeleteFeedback(id uuid.UUID) error {
 	return nil
 }
 
-func (m *MockRequestUserStorage) GetUserById(id uuid.UUID) (*models.CreateUserResponse, error) {
+func (m *MockStorage) RemoveFeedback(removal models.FeedbackRemoval) error {
+	return nil
+}
+
+func (m *MockStorage) GetFeedbackRemovals(boardId uuid.UUID) ([]*models.FeedbackRemoval, error) {
+	feedback := TestMockFeedback()
+	feedback.BoardId = boardId
 	user := TestMockUserResponse()
-	user.Id = id
-	return &user, nil
+	removal := models.NewFeedbackRemoval(&feedback, &user, &models.RemoveFeedbackRequest{Reason: "Off topic"})
+	return []*models.FeedbackRemoval{removal}, nil
+}
+
+func (m *MockStorage) GroupFeedback(id uuid.UUID, groupId *uuid.UUID, modifiedAt time.Time) ([]uuid.UUID, error) {
+	return []uuid.UUID{id}, nil
+}
+
+func (m *MockStorage) ResolveFeedback(id uuid.UUID, resolvedAt *time.Time) error {
+	return nil
+}
+
+func (m *MockStorage) CreateFeedbackVote(vote models.FeedbackVote, limit int) error {
+	return nil
+}
+
+func (m *MockStorage) DeleteFeedbackVote(feedbackId, userId uuid.UUID) error {
+	return nil
+}
+
+func (m *MockStorage) CreateAuditEvent(event models.AuditEvent) error {
+	return nil
+}
+
+func (m *MockStorage) GetAuditEvents(filter models.AuditEventFilter, limit, offset int) ([]*models.AuditEvent, error) {
+	if offset > 0 {
+		return []*models.AuditEvent{}, nil
+	}
+	return TestMockAuditEvents(), nil
 }
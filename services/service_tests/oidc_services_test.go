@@ -0,0 +1,200 @@
+package service_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aakash-Pandit/reetro-golang/common/common_tests"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares/middleware_tests"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/services"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func oidcRouter(fake *middleware_tests.FakeOIDCServer) *mux.Router {
+	return oidcRouterWithStore(fake, new(MockStorage))
+}
+
+func oidcRouterWithStore(fake *middleware_tests.FakeOIDCServer, store storages.Storage) *mux.Router {
+	userService := services.NewUserService(store, new(storages_tests.MockRedisClientWithValues), new(common_tests.MockEmail), middlewares.NewGoogleAuth())
+	userService.OIDCProviders = map[string]*middlewares.OIDCProvider{
+		"okta": fake.Provider(map[string]models.Role{"retro-admins": models.SuperAdmin}),
+	}
+
+	r := mux.NewRouter()
+	r.HandleFunc("/login/oidc/", core.HTTPHandleFunc(userService.GetOIDCProvidersHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/login/oidc/{provider}/", core.HTTPHandleFunc(userService.OIDCLoginHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/login/oidc/{provider}/callback/", core.HTTPHandleFunc(userService.OIDCCallbackHandler)).Methods(http.MethodPost)
+	return r
+}
+
+// startOIDCLogin starts the login and plays the user at the fake provider,
+// returning the callback payload.
+func startOIDCLogin(t *testing.T, router *mux.Router, fake *middleware_tests.FakeOIDCServer) services.OIDCCallbackPayload {
+	req, _ := http.NewRequest(http.MethodGet, "/login/oidc/okta/", nil)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response core.AuthorizationURLResponse
+	json.Unmarshal(rr.Body.Bytes(), &response)
+
+	code, state, err := fake.Authorize(response.AuthorizationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, response.State, state)
+
+	return services.OIDCCallbackPayload{Code: code, State: state}
+}
+
+func postOIDCCallback(router *mux.Router, payload services.OIDCCallbackPayload) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest(http.MethodPost, "/login/oidc/okta/callback/", bytes.NewBuffer(body))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestGetOIDCProvidersHandler(t *testing.T) {
+	fake := middleware_tests.NewFakeOIDCServer()
+	defer fake.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, "/login/oidc/", nil)
+
+	rr := httptest.NewRecorder()
+	oidcRouter(fake).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var listAPIResponse core.ListAPIResponseBody
+	json.Unmarshal(rr.Body.Bytes(), &listAPIResponse)
+	assert.Equal(t, 1, listAPIResponse.Count)
+}
+
+func TestOIDCLoginHandlerWithUnknownProvider(t *testing.T) {
+	fake := middleware_tests.NewFakeOIDCServer()
+	defer fake.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, "/login/oidc/unknown/", nil)
+
+	rr := httptest.NewRecorder()
+	oidcRouter(fake).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestOIDCCallbackHandler(t *testing.T) {
+	fake := middleware_tests.NewFakeOIDCServer()
+	defer fake.Close()
+	fake.Claims["groups"] = []string{"retro-admins"}
+
+	router := oidcRouter(fake)
+	rr := postOIDCCallback(router, startOIDCLogin(t, router, fake))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var tokenResponse core.TokenResponse
+	json.Unmarshal(rr.Body.Bytes(), &tokenResponse)
+	assert.NotEmpty(t, tokenResponse.Token)
+}
+
+func TestOIDCCallbackHandlerReplayingState(t *testing.T) {
+	fake := middleware_tests.NewFakeOIDCServer()
+	defer fake.Close()
+
+	router := oidcRouter(fake)
+	payload := startOIDCLogin(t, router, fake)
+
+	rr := postOIDCCallback(router, payload)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = postOIDCCallback(router, payload)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestOIDCCallbackHandlerWithUnknownState(t *testing.T) {
+	fake := middleware_tests.NewFakeOIDCServer()
+	defer fake.Close()
+
+	rr := postOIDCCallback(oidcRouter(fake), services.OIDCCallbackPayload{Code: "test_code", State: "test_state"})
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestOIDCCallbackHandlerWithInvalidCode(t *testing.T) {
+	fake := middleware_tests.NewFakeOIDCServer()
+	defer fake.Close()
+
+	router := oidcRouter(fake)
+	payload := startOIDCLogin(t, router, fake)
+	payload.Code = "someone_elses_code"
+
+	rr := postOIDCCallback(router, payload)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestOIDCCallbackHandlerWithoutSubject(t *testing.T) {
+	fake := middleware_tests.NewFakeOIDCServer()
+	defer fake.Close()
+	fake.Claims["sub"] = ""
+
+	store := &MockOIDCStorage{EmailVerified: true, UserType: models.GuestUser}
+	router := oidcRouterWithStore(fake, store)
+	rr := postOIDCCallback(router, startOIDCLogin(t, router, fake))
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Empty(t, store.Identities)
+}
+
+func TestOIDCCallbackHandlerLinksVerifiedAccount(t *testing.T) {
+	fake := middleware_tests.NewFakeOIDCServer()
+	defer fake.Close()
+
+	store := &MockOIDCStorage{EmailVerified: true, UserType: models.GuestUser}
+	router := oidcRouterWithStore(fake, store)
+	rr := postOIDCCallback(router, startOIDCLogin(t, router, fake))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, store.Identities, 1)
+	assert.Equal(t, "okta", store.Identities[0].Provider)
+	assert.NotEmpty(t, store.Identities[0].Subject)
+}
+
+func TestOIDCCallbackHandlerWithUnverifiedAccount(t *testing.T) {
+	fake := middleware_tests.NewFakeOIDCServer()
+	defer fake.Close()
+
+	store := &MockOIDCStorage{UserType: models.GuestUser}
+	router := oidcRouterWithStore(fake, store)
+	rr := postOIDCCallback(router, startOIDCLogin(t, router, fake))
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Empty(t, store.Identities)
+}
+
+func TestOIDCCallbackHandlerDemotesUnmappedRole(t *testing.T) {
+	fake := middleware_tests.NewFakeOIDCServer()
+	defer fake.Close()
+	fake.Claims["groups"] = []string{"engineering"}
+
+	store := &MockOIDCStorage{Linked: true, EmailVerified: true, UserType: models.SuperAdmin}
+	router := oidcRouterWithStore(fake, store)
+	rr := postOIDCCallback(router, startOIDCLogin(t, router, fake))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, store.Updated, 1)
+	assert.Equal(t, models.GuestUser, store.Updated[0].UserType)
+}
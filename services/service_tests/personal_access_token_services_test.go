@@ -0,0 +1,120 @@
+package service_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Aakash-Pandit/reetro-golang/common/common_tests"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/services"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func personalAccessTokenRouter() *mux.Router {
+	userService := services.NewUserService(new(MockStorage), new(storages_tests.MockRedisClient), new(common_tests.MockEmail), middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/users/me/tokens/", core.HTTPHandleFunc(userService.GetPersonalAccessTokensHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/users/me/tokens/", core.HTTPHandleFunc(userService.CreatePersonalAccessTokenHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/users/me/tokens/{id}/", core.HTTPHandleFunc(userService.RevokePersonalAccessTokenHandler)).Methods(http.MethodDelete)
+	return r
+}
+
+func TestCreatePersonalAccessTokenHandler(t *testing.T) {
+	payload, _ := json.Marshal(models.CreatePersonalAccessTokenRequest{
+		Name:          "sprint automation",
+		Scopes:        []string{string(middlewares.ViewBoards), string(middlewares.CreateFeedbacks)},
+		ExpiresInDays: 30,
+	})
+
+	req, err := http.NewRequest(http.MethodPost, "/users/me/tokens/", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	personalAccessTokenRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(rr.Body.Bytes(), &response)
+
+	assert.True(t, strings.HasPrefix(response["token"].(string), models.PERSONAL_ACCESS_TOKEN_PREFIX))
+	assert.NotNil(t, response["expires_at"])
+	assert.Nil(t, response["token_hash"])
+}
+
+func TestCreatePersonalAccessTokenHandlerWithScopeAboveRole(t *testing.T) {
+	payload, _ := json.Marshal(models.CreatePersonalAccessTokenRequest{
+		Name:   "sprint automation",
+		Scopes: []string{string(middlewares.DeleteUsers)},
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/users/me/tokens/", bytes.NewBuffer(payload))
+
+	user := TestMockUserResponse()
+	user.UserType = models.GuestUser
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	personalAccessTokenRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCreatePersonalAccessTokenHandlerWithPersonalAccessToken(t *testing.T) {
+	payload, _ := json.Marshal(models.CreatePersonalAccessTokenRequest{
+		Name:   "sprint automation",
+		Scopes: []string{string(middlewares.ViewBoards)},
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/users/me/tokens/", bytes.NewBuffer(payload))
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+	req = req.WithContext(middlewares.WithTokenScopes(req.Context(), []string{string(middlewares.ManageOwnAccount)}))
+
+	rr := httptest.NewRecorder()
+	personalAccessTokenRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestGetPersonalAccessTokensHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/users/me/tokens/", nil)
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	personalAccessTokenRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "test_token_hash")
+}
+
+func TestRevokePersonalAccessTokenHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/users/me/tokens/%s/", uuid.New()), nil)
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	personalAccessTokenRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
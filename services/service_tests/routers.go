@@ -0,0 +1,65 @@
+package service_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/Aakash-Pandit/reetro-golang/common/common_tests"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/services"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/gorilla/mux"
+)
+
+// boardRouter serves the board, column, template, phase and series handlers
+// along with the feedback handlers that depend on the phase of the board.
+func boardRouter(store storages.Storage) *mux.Router {
+	return boardRouterWithRedis(store, new(storages_tests.MockRedisClient))
+}
+
+func boardRouterWithRedis(store storages.Storage, redisClient storages.RedisStoreInterface) *mux.Router {
+	boardService := services.NewBoardService(store, redisClient, new(common_tests.MockEmail))
+	feedbackService := services.NewFeedbackService(store, redisClient)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/templates/", core.HTTPHandleFunc(boardService.GetBoardTemplatesHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/teams/{id}/templates/", core.HTTPHandleFunc(boardService.CreateBoardTemplateHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/teams/{id}/templates/{key}/", core.HTTPHandleFunc(boardService.UpdateBoardTemplateHandler)).Methods(http.MethodPut)
+	r.HandleFunc("/teams/{id}/templates/{key}/", core.HTTPHandleFunc(boardService.DeleteBoardTemplateHandler)).Methods(http.MethodDelete)
+	r.HandleFunc("/boards/", core.HTTPHandleFunc(boardService.CreateBoardHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{id}/", core.HTTPHandleFunc(boardService.UpdateBoardHandler)).Methods(http.MethodPatch)
+	r.HandleFunc("/boards/{id}/phase/", core.HTTPHandleFunc(boardService.ChangeBoardPhaseHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{id}/clone/", core.HTTPHandleFunc(boardService.CloneBoardHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{id}/series/", core.HTTPHandleFunc(boardService.GetBoardSeriesHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{id}/columns/", core.HTTPHandleFunc(boardService.GetBoardColumnsHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/boards/{id}/columns/", core.HTTPHandleFunc(boardService.CreateBoardColumnHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/boards/{id}/columns/order/", core.HTTPHandleFunc(boardService.ReorderBoardColumnsHandler)).Methods(http.MethodPut)
+	r.HandleFunc("/boards/{id}/columns/{column_id}/", core.HTTPHandleFunc(boardService.UpdateBoardColumnHandler)).Methods(http.MethodPatch)
+	r.HandleFunc("/boards/{id}/columns/{column_id}/", core.HTTPHandleFunc(boardService.RemoveBoardColumnHandler)).Methods(http.MethodDelete)
+	r.HandleFunc("/feedbacks/", core.HTTPHandleFunc(feedbackService.CreateFeedbackHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/feedbacks/{id}/", core.HTTPHandleFunc(feedbackService.UpdateFeedbackHandler)).Methods(http.MethodPatch)
+	r.HandleFunc("/feedbacks/{id}/move/", core.HTTPHandleFunc(feedbackService.MoveFeedbackHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/feedbacks/{id}/votes/", core.HTTPHandleFunc(feedbackService.VoteFeedbackHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/feedbacks/{id}/votes/", core.HTTPHandleFunc(feedbackService.UnvoteFeedbackHandler)).Methods(http.MethodDelete)
+	r.HandleFunc("/feedbacks/{id}/group/", core.HTTPHandleFunc(feedbackService.GroupFeedbackHandler)).Methods(http.MethodPut)
+	r.HandleFunc("/feedbacks/{id}/group/", core.HTTPHandleFunc(feedbackService.UngroupFeedbackHandler)).Methods(http.MethodDelete)
+	r.HandleFunc("/feedbacks/{id}/resolve/", core.HTTPHandleFunc(feedbackService.ResolveFeedbackHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/feedbacks/{id}/resolve/", core.HTTPHandleFunc(feedbackService.ReopenFeedbackHandler)).Methods(http.MethodDelete)
+	return r
+}
+
+// serveBoardRequest sends the payload as JSON to boardRouter on behalf of
+// the user.
+func serveBoardRequest(store storages.Storage, user models.CreateUserResponse, method, url string, payload interface{}) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	req, _ := http.NewRequest(method, url, bytes.NewBuffer(body))
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	boardRouter(store).ServeHTTP(rr, req)
+	return rr
+}
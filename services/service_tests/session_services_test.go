@@ -0,0 +1,115 @@
+package service_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/common/common_tests"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/services"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func sessionRouter(store storages.Storage) *mux.Router {
+	userService := services.NewUserService(store, new(storages_tests.MockRedisClient), new(common_tests.MockEmail), middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/token/refresh/", core.HTTPHandleFunc(userService.RefreshTokenHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/users/me/sessions/", core.HTTPHandleFunc(userService.GetSessionsHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/users/me/sessions/", core.HTTPHandleFunc(userService.RevokeAllSessionsHandler)).Methods(http.MethodDelete)
+	r.HandleFunc("/users/me/sessions/{id}/", core.HTTPHandleFunc(userService.RevokeSessionHandler)).Methods(http.MethodDelete)
+	return r
+}
+
+func TestGetSessionsHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/users/me/sessions/", nil)
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	sessionRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response core.ListAPIResponseBody
+	json.Unmarshal(rr.Body.Bytes(), &response)
+
+	assert.Equal(t, 1, response.Count)
+}
+
+func TestRevokeSessionHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/users/me/sessions/%s/", uuid.New()), nil)
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	sessionRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRevokeAllSessionsHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, "/users/me/sessions/", nil)
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	mockRepo := new(MockStorage)
+	mockRepo.On("RevokeUserSessions", user.Id, uuid.Nil).Return(nil)
+
+	rr := httptest.NewRecorder()
+	sessionRouter(mockRepo).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockRepo.AssertCalled(t, "RevokeUserSessions", user.Id, uuid.Nil)
+}
+
+func TestRefreshTokenHandlerWithRevokedSession(t *testing.T) {
+	user := TestMockUser()
+	tokens, _ := middlewares.GenerateTokenPair(user.Id.String(), user.Email, uuid.New().String())
+
+	payload, _ := json.Marshal(services.RefreshTokenPayload{RefreshToken: tokens.RefreshToken})
+	req, _ := http.NewRequest(http.MethodPost, "/token/refresh/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+	sessionRouter(new(MockRevokedSessionStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestRefreshTokenHandlerWithoutSession(t *testing.T) {
+	user := TestMockUser()
+	keySet, _ := middlewares.SigningKeys()
+	refreshToken, _ := keySet.Sign(jwt.MapClaims{
+		"id":         user.Id.String(),
+		"email":      user.Email,
+		"jti":        uuid.New().String(),
+		"token_type": middlewares.REFRESH_TOKEN,
+		"exp":        time.Now().Add(time.Hour).Unix(),
+	})
+
+	payload, _ := json.Marshal(services.RefreshTokenPayload{RefreshToken: refreshToken})
+	req, _ := http.NewRequest(http.MethodPost, "/token/refresh/", bytes.NewBuffer(payload))
+
+	mockRepo := new(MockStorage)
+
+	rr := httptest.NewRecorder()
+	sessionRouter(mockRepo).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockRepo.AssertNotCalled(t, "CreateUserSession", mock.Anything)
+}
@@ -0,0 +1,222 @@
+package service_tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/services"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func teamRouter(store storages.Storage) *mux.Router {
+	teamService := services.NewTeamService(store)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/teams/", core.HTTPHandleFunc(teamService.GetAllTeamsHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/teams/", core.HTTPHandleFunc(teamService.CreateTeamHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/teams/{id}/", core.HTTPHandleFunc(teamService.GetTeamByIdHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/teams/{id}/", core.HTTPHandleFunc(teamService.UpdateTeamHandler)).Methods(http.MethodPatch)
+	r.HandleFunc("/teams/{id}/", core.HTTPHandleFunc(teamService.DeleteTeamHandler)).Methods(http.MethodDelete)
+	r.HandleFunc("/teams/{id}/members/", core.HTTPHandleFunc(teamService.GetTeamMembersHandler)).Methods(http.MethodGet)
+	r.HandleFunc("/teams/{id}/members/", core.HTTPHandleFunc(teamService.AddTeamMemberHandler)).Methods(http.MethodPost)
+	r.HandleFunc("/teams/{id}/members/{user_id}/", core.HTTPHandleFunc(teamService.UpdateTeamMemberHandler)).Methods(http.MethodPatch)
+	r.HandleFunc("/teams/{id}/members/{user_id}/", core.HTTPHandleFunc(teamService.RemoveTeamMemberHandler)).Methods(http.MethodDelete)
+	return r
+}
+
+func TestGetAllTeamsHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/teams/", nil)
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	teamRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var listAPIResponse core.ListAPIResponseBody
+	json.Unmarshal(rr.Body.Bytes(), &listAPIResponse)
+	assert.Equal(t, 2, listAPIResponse.Count)
+}
+
+func TestGetAllTeamsHandlerOnlyListsOwnTeams(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/teams/", nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	teamRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var listAPIResponse core.ListAPIResponseBody
+	json.Unmarshal(rr.Body.Bytes(), &listAPIResponse)
+	assert.Equal(t, 1, listAPIResponse.Count)
+}
+
+func TestCreateTeamHandler(t *testing.T) {
+	payload, _ := json.Marshal(models.CreateTeamRequest{Name: "Payments squad"})
+	req, _ := http.NewRequest(http.MethodPost, "/teams/", bytes.NewBuffer(payload))
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	teamRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var team models.Team
+	json.Unmarshal(rr.Body.Bytes(), &team)
+	assert.Equal(t, "Payments squad", team.Name)
+	assert.Equal(t, user.Id, team.CreatedById)
+}
+
+func TestGetTeamByIdHandlerForNonMember(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/teams/%s/", uuid.New()), nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	teamRouter(new(MockNonMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestUpdateTeamHandler(t *testing.T) {
+	payload, _ := json.Marshal(models.UpdateTeamRequest{Name: "Renamed squad"})
+	req, _ := http.NewRequest(http.MethodPatch, fmt.Sprintf("/teams/%s/", uuid.New()), bytes.NewBuffer(payload))
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	teamRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestDeleteTeamHandlerAsTeamMember(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/teams/%s/", uuid.New()), nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	teamRouter(new(MockTeamMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestGetTeamMembersHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/teams/%s/members/", uuid.New()), nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	teamRouter(new(MockTeamMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestAddTeamMemberHandler(t *testing.T) {
+	payload, _ := json.Marshal(models.AddTeamMemberRequest{UserId: uuid.New().String(), Role: models.TeamRoleOwner})
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/teams/%s/members/", uuid.New()), bytes.NewBuffer(payload))
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	teamRouter(new(MockNonMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	req, _ = http.NewRequest(http.MethodPost, fmt.Sprintf("/teams/%s/members/", uuid.New()), bytes.NewBuffer(payload))
+	req = TestRequestWithUser(req, &user)
+
+	rr = httptest.NewRecorder()
+	teamRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	var membership models.TeamMembership
+	json.Unmarshal(rr.Body.Bytes(), &membership)
+	assert.Equal(t, models.TeamRoleOwner, membership.Role)
+}
+
+func TestAddTeamMemberHandlerAsTeamMember(t *testing.T) {
+	payload, _ := json.Marshal(models.AddTeamMemberRequest{UserId: uuid.New().String()})
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/teams/%s/members/", uuid.New()), bytes.NewBuffer(payload))
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	teamRouter(new(MockTeamMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestAddTeamMemberHandlerDemotingOwnerAsAdmin(t *testing.T) {
+	user := TestTeamMemberUser()
+
+	for _, role := range []models.TeamRole{models.TeamRoleAdmin, models.TeamRoleMember, ""} {
+		payload, _ := json.Marshal(models.AddTeamMemberRequest{UserId: uuid.New().String(), Role: role})
+		req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/teams/%s/members/", uuid.New()), bytes.NewBuffer(payload))
+		req = TestRequestWithUser(req, &user)
+
+		rr := httptest.NewRecorder()
+		teamRouter(&MockTeamAdminStorage{AdminId: user.Id}).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	}
+}
+
+func TestUpdateTeamMemberHandlerDemotingLastOwner(t *testing.T) {
+	payload, _ := json.Marshal(models.UpdateTeamMemberRequest{Role: models.TeamRoleMember})
+	req, _ := http.NewRequest(http.MethodPatch, fmt.Sprintf("/teams/%s/members/%s/", uuid.New(), uuid.New()), bytes.NewBuffer(payload))
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	teamRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestRemoveTeamMemberHandlerLeavingTeam(t *testing.T) {
+	user := TestTeamMemberUser()
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/teams/%s/members/%s/", uuid.New(), user.Id), nil)
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	teamRouter(new(MockTeamMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRemoveTeamMemberHandlerAsTeamMember(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/teams/%s/members/%s/", uuid.New(), uuid.New()), nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	teamRouter(new(MockTeamMemberStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
@@ -0,0 +1,112 @@
+package service_tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aakash-Pandit/reetro-golang/common/common_tests"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/services"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func userExportRouter(store storages.Storage) *mux.Router {
+	userService := services.NewUserService(store, new(storages_tests.MockRedisClient), new(common_tests.MockEmail), middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/users/me/", core.HTTPHandleFunc(userService.DeleteOwnAccountHandler)).Methods(http.MethodDelete)
+	r.HandleFunc("/users/me/export/", core.HTTPHandleFunc(userService.ExportUserDataHandler)).Methods(http.MethodGet)
+	return r
+}
+
+func TestExportUserDataHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/users/me/export/", nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	userExportRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "attachment")
+
+	var export models.UserDataExport
+	json.Unmarshal(rr.Body.Bytes(), &export)
+	assert.Equal(t, user.Id, export.Profile.Id)
+}
+
+func TestExportUserDataHandlerWithPersonalAccessToken(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/users/me/export/", nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+	req = req.WithContext(middlewares.WithTokenScopes(req.Context(), []string{string(middlewares.ManageOwnAccount)}))
+
+	rr := httptest.NewRecorder()
+	userExportRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestDeleteOwnAccountHandler(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, "/users/me/", nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	userExportRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestDeleteOwnAccountHandlerWithPersonalAccessToken(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, "/users/me/", nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+	req = req.WithContext(middlewares.WithTokenScopes(req.Context(), []string{string(middlewares.ManageOwnAccount)}))
+
+	rr := httptest.NewRecorder()
+	userExportRouter(new(MockStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestDeleteOwnAccountHandlerRecordsOnlyTheId(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, "/users/me/", nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	store := new(MockAuditStorage)
+
+	rr := httptest.NewRecorder()
+	userExportRouter(store).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, store.Events, 1)
+	assert.Equal(t, user.Id.String(), store.Events[0].TargetId)
+	assert.Empty(t, store.Events[0].Before)
+	assert.Empty(t, store.Events[0].After)
+}
+
+func TestDeleteOwnAccountHandlerAsSoleTeamOwner(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, "/users/me/", nil)
+
+	user := TestTeamMemberUser()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+	userExportRouter(new(MockSoleOwnerStorage)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
@@ -6,15 +6,21 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Aakash-Pandit/reetro-golang/common/common_tests"
 	"github.com/Aakash-Pandit/reetro-golang/core"
 	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
 	"github.com/Aakash-Pandit/reetro-golang/services"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
 	storages_tests "github.com/Aakash-Pandit/reetro-golang/storages/storage_tests"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetAllUsersHandler(t *testing.T) {
@@ -23,21 +29,16 @@ func TestGetAllUsersHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	user := TestMockUser()
-	token, _ := middlewares.GenerateJSONWebToken(user.Id.String(), user.Email)
-
-	token = fmt.Sprintf("Bearer %s", token)
-	req.Header.Set("Authorization", token)
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
 
 	rr := httptest.NewRecorder()
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
 	mockEmail := new(common_tests.MockEmail)
-	requestUser := services.NewRequestUser(mockRequestUser)
 
 	mockRepo := new(MockStorage)
-	userService := services.NewUserService(mockRepo, *requestUser, mockRedisClient, mockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
 
 	r := mux.NewRouter()
 	r.HandleFunc("/users/", core.HTTPHandleFunc(userService.GetAllUsersHandler)).Methods(http.MethodGet)
@@ -73,13 +74,11 @@ func TestGetUserByIdHandler(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
 	mockEmail := new(common_tests.MockEmail)
-	requestUser := services.NewRequestUser(mockRequestUser)
 
 	mockRepo := new(MockStorage)
-	userService := services.NewUserService(mockRepo, *requestUser, mockRedisClient, mockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
 
 	r := mux.NewRouter()
 	r.HandleFunc("/users/{id}/", core.HTTPHandleFunc(userService.GetUserByIdHandler)).Methods(http.MethodGet)
@@ -105,11 +104,9 @@ func TestCreateUserHandler(t *testing.T) {
 	mockRepo := new(MockStorage)
 	mockRepo.On("CreateUser", testUser).Return(testUser, nil)
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
 	mockEmail := new(common_tests.MockEmail)
-	requestUser := services.NewRequestUser(mockRequestUser)
-	userService := services.NewUserService(mockRepo, *requestUser, mockRedisClient, mockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
 
 	r := mux.NewRouter()
 	r.HandleFunc("/users/", core.HTTPHandleFunc(userService.CreateUserHandler)).Methods(http.MethodPost)
@@ -121,6 +118,69 @@ func TestCreateUserHandler(t *testing.T) {
 	assert.Equal(t, testUser.Email, "test@email.com")
 }
 
+func TestCreateUserHandlerIgnoresUserType(t *testing.T) {
+	payload, _ := json.Marshal(map[string]string{
+		"first_name": "test_first_name",
+		"last_name":  "test_last_name",
+		"username":   "test_username",
+		"password":   "Retro-Board-42",
+		"email":      "test@email.com",
+		"user_type":  string(models.SuperAdmin),
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/signup/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockCreatedUserStorage)
+	userService := services.NewUserService(mockRepo, new(storages_tests.MockRedisClient), new(common_tests.MockEmail), middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/signup/", core.HTTPHandleFunc(userService.CreateUserHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Len(t, mockRepo.Users, 1)
+	assert.Equal(t, models.GuestUser, mockRepo.Users[0].UserType)
+
+	var userResponse models.CreateUserResponse
+	json.Unmarshal(rr.Body.Bytes(), &userResponse)
+	assert.Equal(t, models.GuestUser, userResponse.UserType)
+}
+
+func TestCreateUserAsAdminHandler(t *testing.T) {
+	for userType, status := range map[models.Role]int{
+		models.TeamMember: http.StatusCreated,
+		"":                http.StatusBadRequest,
+		"owner":           http.StatusBadRequest,
+	} {
+		payload, _ := json.Marshal(models.AdminCreateUserRequest{
+			CreateUserRequest: models.CreateUserRequest{
+				FirstName: "test_first_name",
+				LastName:  "test_last_name",
+				Username:  "test_username",
+				Password:  "Retro-Board-42",
+				Email:     "test@email.com",
+			},
+			UserType: userType,
+		})
+		req, _ := http.NewRequest(http.MethodPost, "/users/", bytes.NewBuffer(payload))
+
+		rr := httptest.NewRecorder()
+
+		mockRepo := new(MockCreatedUserStorage)
+		userService := services.NewUserService(mockRepo, new(storages_tests.MockRedisClient), new(common_tests.MockEmail), middlewares.NewGoogleAuth())
+
+		r := mux.NewRouter()
+		r.HandleFunc("/users/", core.HTTPHandleFunc(userService.CreateUserAsAdminHandler)).Methods(http.MethodPost)
+		r.ServeHTTP(rr, req)
+
+		assert.Equal(t, status, rr.Code, userType)
+		if status == http.StatusCreated {
+			assert.Equal(t, userType, mockRepo.Users[0].UserType)
+		}
+	}
+}
+
 func TestDeleteUserHandler(t *testing.T) {
 	testUser := TestMockUser()
 	id := testUser.Id.String()
@@ -133,13 +193,11 @@ func TestDeleteUserHandler(t *testing.T) {
 
 	rr := httptest.NewRecorder()
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
 	mockEmail := new(common_tests.MockEmail)
-	requestUser := services.NewRequestUser(mockRequestUser)
 
 	mockRepo := new(MockStorage)
-	userService := services.NewUserService(mockRepo, *requestUser, mockRedisClient, mockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
 
 	r := mux.NewRouter()
 	r.HandleFunc("/users/{id}/", core.HTTPHandleFunc(userService.DeleteUserHandler)).Methods(http.MethodDelete)
@@ -150,6 +208,20 @@ func TestDeleteUserHandler(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
 
+func TestDeleteUserHandlerWithUnknownUser(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodDelete, fmt.Sprintf("/users/%s/", uuid.New()), nil)
+
+	rr := httptest.NewRecorder()
+
+	userService := services.NewUserService(new(MockMissingUserStorage), new(storages_tests.MockRedisClient), new(common_tests.MockEmail), middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/users/{id}/", core.HTTPHandleFunc(userService.DeleteUserHandler)).Methods(http.MethodDelete)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
 func TestLoginHandler(t *testing.T) {
 	testLogin := TestLoginPayload()
 	payload, _ := json.Marshal(testLogin)
@@ -163,11 +235,9 @@ func TestLoginHandler(t *testing.T) {
 	mockRepo := new(MockStorage)
 	mockRepo.On("VerifyUserByUsername", testLogin.Username).Return(&testLogin, nil)
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
 	mockEmail := new(common_tests.MockEmail)
-	requestUser := services.NewRequestUser(mockRequestUser)
-	userService := services.NewUserService(mockRepo, *requestUser, mockRedisClient, mockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
 
 	r := mux.NewRouter()
 	r.HandleFunc("/login/", core.HTTPHandleFunc(userService.LoginHandler)).Methods(http.MethodPost)
@@ -201,12 +271,11 @@ func TestResetPasswordHandler(t *testing.T) {
 	mockRepo := new(MockStorage)
 	mockRepo.On("VerifyUserByUsernamePassword", resetPasswordPayload.Username, resetPasswordPayload.OldPassword).Return(&testUser, nil)
 	mockRepo.On("SavePassword", testUser).Return(nil)
+	mockRepo.On("RevokeUserSessions", mock.Anything, uuid.Nil).Return(nil)
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
 	mockEmail := new(common_tests.MockEmail)
-	requestUser := services.NewRequestUser(mockRequestUser)
-	userService := services.NewUserService(mockRepo, *requestUser, mockRedisClient, mockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
 
 	r := mux.NewRouter()
 	r.HandleFunc("/reset_password/", core.HTTPHandleFunc(userService.ResetPasswordHandler)).Methods(http.MethodPost)
@@ -215,6 +284,7 @@ func TestResetPasswordHandler(t *testing.T) {
 	assert.NoError(t, err)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
+	mockRepo.AssertCalled(t, "RevokeUserSessions", mock.Anything, uuid.Nil)
 }
 
 func TestForgotPasswordHandler(t *testing.T) {
@@ -232,12 +302,11 @@ func TestForgotPasswordHandler(t *testing.T) {
 	mockRepo := new(MockStorage)
 	mockRepo.On("VerifyUserByEmail", testUser.Email).Return(&testUser, nil)
 	mockRepo.On("SavePassword", testUser).Return(nil)
+	mockRepo.On("RevokeUserSessions", mock.Anything, uuid.Nil).Return(nil)
 
-	mockRequestUser := new(MockRequestUserStorage)
 	mockRedisClient := new(storages_tests.MockRedisClient)
 	mockEmail := new(common_tests.MockEmail)
-	requestUser := services.NewRequestUser(mockRequestUser)
-	userService := services.NewUserService(mockRepo, *requestUser, mockRedisClient, mockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
 
 	r := mux.NewRouter()
 	r.HandleFunc("/forgot_password/", core.HTTPHandleFunc(userService.ForgotPasswordHandler)).Methods(http.MethodPost)
@@ -247,3 +316,576 @@ func TestForgotPasswordHandler(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
+
+func TestRefreshTokenHandler(t *testing.T) {
+	user := TestMockUser()
+	tokens, _ := middlewares.GenerateTokenPair(user.Id.String(), user.Email, uuid.New().String())
+	refreshToken := tokens.RefreshToken
+
+	payload, _ := json.Marshal(services.RefreshTokenPayload{RefreshToken: refreshToken})
+	req, err := http.NewRequest(http.MethodPost, "/token/refresh/", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := &MockActiveSessionStorage{UserId: user.Id}
+	mockRedisClient := new(storages_tests.MockRedisClientWithValues)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/token/refresh/", core.HTTPHandleFunc(userService.RefreshTokenHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var tokenResponse core.TokenResponse
+	err = json.Unmarshal(rr.Body.Bytes(), &tokenResponse)
+	if err != nil {
+		t.Errorf("failed to unmarshal response body: %v", err)
+	}
+
+	assert.NotEmpty(t, tokenResponse.Token)
+	assert.NotEmpty(t, tokenResponse.RefreshToken)
+	assert.NotEqual(t, refreshToken, tokenResponse.RefreshToken)
+
+	// The rotated out refresh token can not be used again.
+	req, _ = http.NewRequest(http.MethodPost, "/token/refresh/", bytes.NewBuffer(payload))
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestRefreshTokenHandlerWithAccessToken(t *testing.T) {
+	user := TestMockUser()
+	tokens, _ := middlewares.GenerateTokenPair(user.Id.String(), user.Email, uuid.New().String())
+
+	payload, _ := json.Marshal(services.RefreshTokenPayload{RefreshToken: tokens.Token})
+	req, err := http.NewRequest(http.MethodPost, "/token/refresh/", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/token/refresh/", core.HTTPHandleFunc(userService.RefreshTokenHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestLogoutHandler(t *testing.T) {
+	user := TestMockUser()
+	tokens, _ := middlewares.GenerateTokenPair(user.Id.String(), user.Email, uuid.New().String())
+
+	payload, _ := json.Marshal(services.LogoutPayload{RefreshToken: tokens.RefreshToken})
+	req, err := http.NewRequest(http.MethodPost, "/logout/", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, _ := middlewares.ValidateTokenOfType(tokens.Token, middlewares.ACCESS_TOKEN)
+	userResponse := TestMockUserResponse()
+	userResponse.Id = user.Id
+	req = req.WithContext(middlewares.WithTokenClaims(middlewares.WithRequestUser(req.Context(), &userResponse), claims))
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/logout/", core.HTTPHandleFunc(userService.LogoutHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestGoogleLoginHandler(t *testing.T) {
+	googleStub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"iss":            "https://accounts.google.com",
+			"aud":            "test_client_id",
+			"sub":            "test_google_subject",
+			"email":          "test@email.com",
+			"email_verified": "true",
+			"given_name":     "test_first_name",
+		})
+	}))
+	defer googleStub.Close()
+
+	payload, _ := json.Marshal(services.GoogleLoginPayload{IdToken: "test_id_token"})
+	req, err := http.NewRequest(http.MethodPost, "/login/google/", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	googleAuth := middlewares.NewGoogleAuth()
+	googleAuth.TokenValidationURL = googleStub.URL + "?id_token="
+	googleAuth.ClientId = "test_client_id"
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, googleAuth)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/login/google/", core.HTTPHandleFunc(userService.GoogleLoginHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var tokenResponse core.TokenResponse
+	err = json.Unmarshal(rr.Body.Bytes(), &tokenResponse)
+	if err != nil {
+		t.Errorf("failed to unmarshal response body: %v", err)
+	}
+
+	assert.NotEmpty(t, tokenResponse.Token)
+}
+
+func TestGoogleLoginHandlerWithOtherAudience(t *testing.T) {
+	googleStub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"iss":            "accounts.google.com",
+			"aud":            "someone_elses_client_id",
+			"email":          "test@email.com",
+			"email_verified": "true",
+		})
+	}))
+	defer googleStub.Close()
+
+	payload, _ := json.Marshal(services.GoogleLoginPayload{IdToken: "test_id_token"})
+	req, err := http.NewRequest(http.MethodPost, "/login/google/", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	googleAuth := middlewares.NewGoogleAuth()
+	googleAuth.TokenValidationURL = googleStub.URL + "?id_token="
+	googleAuth.ClientId = "test_client_id"
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, googleAuth)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/login/google/", core.HTTPHandleFunc(userService.GoogleLoginHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+// serveGoogleLogin logs in with a Google token whose tokeninfo answer is
+// claims.
+func serveGoogleLogin(store storages.Storage, claims map[string]interface{}) *httptest.ResponseRecorder {
+	googleStub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(claims)
+	}))
+	defer googleStub.Close()
+
+	payload, _ := json.Marshal(services.GoogleLoginPayload{IdToken: "test_id_token"})
+	req, _ := http.NewRequest(http.MethodPost, "/login/google/", bytes.NewBuffer(payload))
+
+	googleAuth := middlewares.NewGoogleAuth()
+	googleAuth.TokenValidationURL = googleStub.URL + "?id_token="
+	googleAuth.ClientId = "test_client_id"
+
+	userService := services.NewUserService(store, new(storages_tests.MockRedisClient), new(common_tests.MockEmail), googleAuth)
+
+	rr := httptest.NewRecorder()
+	r := mux.NewRouter()
+	r.HandleFunc("/login/google/", core.HTTPHandleFunc(userService.GoogleLoginHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+	return rr
+}
+
+func testGoogleClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":            "accounts.google.com",
+		"aud":            "test_client_id",
+		"sub":            "test_google_subject",
+		"email":          "test@email.com",
+		"email_verified": "true",
+	}
+}
+
+func TestGoogleLoginHandlerLinksVerifiedAccount(t *testing.T) {
+	store := &MockOIDCStorage{EmailVerified: true, UserType: models.TeamMember}
+
+	rr := serveGoogleLogin(store, testGoogleClaims())
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, store.Identities, 1)
+	assert.Equal(t, middlewares.GOOGLE_IDENTITY_PROVIDER, store.Identities[0].Provider)
+	assert.Equal(t, "test_google_subject", store.Identities[0].Subject)
+}
+
+func TestGoogleLoginHandlerWithUnverifiedAccount(t *testing.T) {
+	store := &MockOIDCStorage{EmailVerified: false, UserType: models.TeamMember}
+
+	rr := serveGoogleLogin(store, testGoogleClaims())
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Empty(t, store.Identities)
+}
+
+func TestGoogleLoginHandlerWithoutVerifiedEmailOrSubject(t *testing.T) {
+	for _, claim := range []string{"sub", "email_verified"} {
+		claims := testGoogleClaims()
+		delete(claims, claim)
+
+		rr := serveGoogleLogin(&MockOIDCStorage{EmailVerified: true}, claims)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, claim)
+	}
+}
+
+func TestResetPasswordConfirmHandler(t *testing.T) {
+	confirmPayload := TestResetPasswordConfirmPayload()
+
+	payload, _ := json.Marshal(confirmPayload)
+	req, err := http.NewRequest(http.MethodPost, "/reset_password/confirm/", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	mockRepo.On("RevokeUserSessions", mock.Anything, uuid.Nil).Return(nil)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/reset_password/confirm/", core.HTTPHandleFunc(userService.ResetPasswordConfirmHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockRepo.AssertCalled(t, "RevokeUserSessions", mock.Anything, uuid.Nil)
+}
+
+func TestUpdateUserHandler(t *testing.T) {
+	testUser := TestMockUser()
+	url := fmt.Sprintf("/users/%s/", testUser.Id.String())
+
+	payload, _ := json.Marshal(map[string]string{"first_name": "updated_first_name", "user_type": "team_member"})
+	req, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userResponse := TestMockUserResponse()
+	userResponse.Id = testUser.Id
+	req = TestRequestWithUser(req, &userResponse)
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/users/{id}/", core.HTTPHandleFunc(userService.UpdateUserHandler)).Methods(http.MethodPatch)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var updatedUser map[string]interface{}
+	json.Unmarshal(rr.Body.Bytes(), &updatedUser)
+
+	assert.Equal(t, "updated_first_name", updatedUser["first_name"])
+	assert.Equal(t, "team_member", updatedUser["user_type"])
+}
+
+func TestUpdateUserHandlerWithTakenUsername(t *testing.T) {
+	testUser := TestMockUser()
+	url := fmt.Sprintf("/users/%s/", testUser.Id.String())
+
+	payload, _ := json.Marshal(map[string]string{"username": "taken_username"})
+	req, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userResponse := TestMockUserResponse()
+	userResponse.Id = testUser.Id
+	req = TestRequestWithUser(req, &userResponse)
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/users/{id}/", core.HTTPHandleFunc(userService.UpdateUserHandler)).Methods(http.MethodPatch)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestLoginHandlerWithInvalidPassword(t *testing.T) {
+	payload, _ := json.Marshal(models.LoginRequest{Username: "test_username", Password: "wrong_password"})
+	req, err := http.NewRequest(http.MethodPost, "/login/", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/login/", core.HTTPHandleFunc(userService.LoginHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	var apiError core.APIError
+	json.Unmarshal(rr.Body.Bytes(), &apiError)
+	assert.Equal(t, "Invalid username or password", apiError.Detail)
+}
+
+func TestLoginHandlerWhenLockedOut(t *testing.T) {
+	testLogin := TestLoginPayload()
+	payload, _ := json.Marshal(testLogin)
+	req, err := http.NewRequest(http.MethodPost, "/login/", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(MockLockedRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/login/", core.HTTPHandleFunc(userService.LoginHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+}
+
+func TestResetPasswordHandlerWhenLockedOut(t *testing.T) {
+	payload, _ := json.Marshal(TestResetPasswordPayload())
+	req, _ := http.NewRequest(http.MethodPost, "/reset_password/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	userService := services.NewUserService(mockRepo, new(MockLockedRedisClient), new(common_tests.MockEmail), middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/reset_password/", core.HTTPHandleFunc(userService.ResetPasswordHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+	mockRepo.AssertNotCalled(t, "VerifyUserByUsernamePassword", mock.Anything, mock.Anything)
+}
+
+func TestUnlockUserHandler(t *testing.T) {
+	testUser := TestMockUser()
+	url := fmt.Sprintf("/users/%s/unlock/", testUser.Id.String())
+
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := TestMockUserResponse()
+	req = TestRequestWithUser(req, &user)
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/users/{id}/unlock/", core.HTTPHandleFunc(userService.UnlockUserHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestLoginLockoutDuration(t *testing.T) {
+	settings := services.LoginThrottleSettings{
+		BaseLockout: time.Minute,
+		MaxLockout:  time.Hour,
+	}
+
+	assert.Equal(t, time.Duration(0), settings.LockoutDuration(4, 5))
+	assert.Equal(t, time.Minute, settings.LockoutDuration(5, 5))
+	assert.Equal(t, 4*time.Minute, settings.LockoutDuration(7, 5))
+	assert.Equal(t, time.Hour, settings.LockoutDuration(50, 5))
+}
+
+func TestVerifyEmailHandler(t *testing.T) {
+	user := TestMockUserResponse()
+	token, _ := middlewares.GenerateEmailVerificationToken(user.Id.String(), user.Email)
+
+	req, err := http.NewRequest(http.MethodGet, "/verify_email/?token="+token, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/verify_email/", core.HTTPHandleFunc(userService.VerifyEmailHandler)).Methods(http.MethodGet)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestVerifyEmailHandlerWithChangedEmail(t *testing.T) {
+	user := TestMockUserResponse()
+	token, _ := middlewares.GenerateEmailVerificationToken(user.Id.String(), "old@email.com")
+
+	req, err := http.NewRequest(http.MethodGet, "/verify_email/?token="+token, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/verify_email/", core.HTTPHandleFunc(userService.VerifyEmailHandler)).Methods(http.MethodGet)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestResendVerificationEmailHandler(t *testing.T) {
+	payload, _ := json.Marshal(services.ResendVerificationPayload{Email: "test@email.com"})
+	req, err := http.NewRequest(http.MethodPost, "/verify_email/resend/", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/verify_email/resend/", core.HTTPHandleFunc(userService.ResendVerificationEmailHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestLoginHandlerWithUnverifiedEmail(t *testing.T) {
+	t.Setenv("EMAIL_VERIFICATION_REQUIRED_FOR_LOGIN", "true")
+
+	testLogin := TestLoginPayload()
+	payload, _ := json.Marshal(testLogin)
+	req, err := http.NewRequest(http.MethodPost, "/login/", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockStorage)
+	mockRedisClient := new(storages_tests.MockRedisClient)
+	mockEmail := new(common_tests.MockEmail)
+	userService := services.NewUserService(mockRepo, mockRedisClient, mockEmail, middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/login/", core.HTTPHandleFunc(userService.LoginHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestForgotPasswordHandlerWithFailingEmail(t *testing.T) {
+	payload, _ := json.Marshal(TestForgotPasswordPayload())
+	req, _ := http.NewRequest(http.MethodPost, "/forgot_password/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+
+	userService := services.NewUserService(new(MockStorage), new(storages_tests.MockRedisClient), new(common_tests.MockFailingEmail), middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/forgot_password/", core.HTTPHandleFunc(userService.ForgotPasswordHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestResetPasswordConfirmHandlerWithUsernameAsPassword(t *testing.T) {
+	confirmPayload := TestResetPasswordConfirmPayload()
+	payload, _ := json.Marshal(confirmPayload)
+	req, _ := http.NewRequest(http.MethodPost, "/reset_password/confirm/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := &MockResetPasswordUserStorage{Username: strings.ToLower(confirmPayload.NewPassword)}
+	userService := services.NewUserService(mockRepo, new(storages_tests.MockRedisClient), new(common_tests.MockEmail), middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/reset_password/confirm/", core.HTTPHandleFunc(userService.ResetPasswordConfirmHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "not_username")
+	assert.False(t, mockRepo.Redeemed)
+}
+
+func TestResetPasswordConfirmHandlerWithRedeemedToken(t *testing.T) {
+	payload, _ := json.Marshal(TestResetPasswordConfirmPayload())
+	req, _ := http.NewRequest(http.MethodPost, "/reset_password/confirm/", bytes.NewBuffer(payload))
+
+	rr := httptest.NewRecorder()
+
+	mockRepo := new(MockUsedResetTokenStorage)
+	userService := services.NewUserService(mockRepo, new(storages_tests.MockRedisClient), new(common_tests.MockEmail), middlewares.NewGoogleAuth())
+
+	r := mux.NewRouter()
+	r.HandleFunc("/reset_password/confirm/", core.HTTPHandleFunc(userService.ResetPasswordConfirmHandler)).Methods(http.MethodPost)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockRepo.AssertNotCalled(t, "RevokeUserSessions", mock.Anything, uuid.Nil)
+}
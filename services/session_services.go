@@ -0,0 +1,199 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// startSession records the device the user logged in from and issues the
+// token pair bound to it.
+func (u *UserService) startSession(r *http.Request, id uuid.UUID, email string) (*core.TokenResponse, error) {
+	session := models.NewUserSession(id, r.UserAgent(), core.ClientIP(r), middlewares.REFRESH_TOKEN_LIFETIME)
+
+	err := u.Store.CreateUserSession(*session)
+	if err != nil {
+		return nil, err
+	}
+
+	return middlewares.GenerateTokenPair(id.String(), email, session.Id.String())
+}
+
+// currentSessionId returns the session of the access token sent with the
+// request, if it belongs to the user, so it can be kept when the others are
+// revoked. Routes outside JWTAuthentication are checked as well.
+func currentSessionId(r *http.Request, userId uuid.UUID) uuid.UUID {
+	claims, ok := middlewares.TokenClaimsFromContext(r.Context())
+	if !ok {
+		tokenString, err := middlewares.BearerToken(r)
+		if err != nil {
+			return uuid.Nil
+		}
+
+		claims, err = middlewares.ValidateTokenOfType(tokenString, middlewares.ACCESS_TOKEN)
+		if err != nil {
+			return uuid.Nil
+		}
+	}
+
+	if middlewares.ClaimString(claims, "id") != userId.String() {
+		return uuid.Nil
+	}
+
+	sessionId, _ := middlewares.SessionIdFromClaims(claims)
+	return sessionId
+}
+
+// revokeOtherSessions logs the user out everywhere but the current session,
+// used after the password changed.
+func (u *UserService) revokeOtherSessions(r *http.Request, userId uuid.UUID) {
+	err := u.Store.RevokeUserSessions(userId, currentSessionId(r, userId))
+	if err != nil {
+		log.Println("Error while revoking the user sessions", err)
+	}
+}
+
+func (u *UserService) GetSessionsHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	sessions, err := u.Store.GetActiveUserSessions(requestUser.Id)
+	if err != nil {
+		log.Println("Error in fetching the sessions", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to fetch sessions"},
+		})
+	}
+
+	current := currentSessionId(r, requestUser.Id)
+	for _, session := range sessions {
+		session.Current = session.Id == current
+	}
+
+	return core.ListAPIResponse(w, &core.ListAPI{
+		Status: http.StatusOK,
+		Result: &core.ListAPIResponseBody{
+			Count:  len(sessions),
+			Result: sessions,
+		},
+	})
+}
+
+func (u *UserService) RevokeSessionHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	if _, scoped := middlewares.TokenScopesFromContext(r.Context()); scoped {
+		return personalAccessTokenForbidden(w)
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	err = u.Store.RevokeUserSession(id, requestUser.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusNotFound,
+			Data:   &core.APIError{Detail: "Session not found"},
+		})
+	}
+
+	if err != nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while revoking the session"},
+		})
+	}
+
+	recordAuditEvent(r, u.Store, models.AuditSessionRevoked, models.AuditTargetSession, id.String(), nil, nil)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   &APISuccessResponse{Detail: "Session revoked successfully"},
+	})
+}
+
+// RevokeAllSessionsHandler logs the user out everywhere, including the
+// session making the request.
+func (u *UserService) RevokeAllSessionsHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	if _, scoped := middlewares.TokenScopesFromContext(r.Context()); scoped {
+		return personalAccessTokenForbidden(w)
+	}
+
+	err := u.Store.RevokeUserSessions(requestUser.Id, uuid.Nil)
+	if err != nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while revoking the sessions"},
+		})
+	}
+
+	recordAuditEvent(r, u.Store, models.AuditAllSessionsRevoked, models.AuditTargetUser, requestUser.Id.String(), nil, nil)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   &APISuccessResponse{Detail: "All sessions revoked successfully"},
+	})
+}
+
+// refreshSession keeps the session of a refresh token alive. Refresh tokens
+// issued before sessions were tracked have none to revoke, so they cannot be
+// used any more and the user has to log in again.
+func (u *UserService) refreshSession(user *models.CreateUserResponse, sessionId uuid.UUID, hasSession bool) (*core.TokenResponse, error) {
+	if !hasSession {
+		return nil, errors.New("Refresh token has no session")
+	}
+
+	session, err := u.Store.GetUserSession(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !session.IsActive() || session.UserId != user.Id {
+		return nil, errors.New("Session has been revoked")
+	}
+
+	now := time.Now().UTC()
+	err = u.Store.TouchUserSession(session.Id, now, now.Add(middlewares.REFRESH_TOKEN_LIFETIME))
+	if err != nil {
+		return nil, err
+	}
+
+	return middlewares.GenerateTokenPair(user.Id.String(), user.Email, session.Id.String())
+}
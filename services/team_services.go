@@ -0,0 +1,571 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/Aakash-Pandit/reetro-golang/storages"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type TeamService struct {
+	Store storages.Storage
+}
+
+func NewTeamService(store storages.Storage) *TeamService {
+	return &TeamService{Store: store}
+}
+
+// requestTeamMembership returns the membership of the request user in the
+// team, nil when they are not a member of it.
+func requestTeamMembership(ctx context.Context, store storages.Storage, teamId uuid.UUID) *models.TeamMembership {
+	user, ok := middlewares.RequestUserFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	membership, err := store.GetTeamMembership(teamId, user.Id)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error in fetching the team membership", err)
+		}
+		return nil
+	}
+
+	return membership
+}
+
+// canViewTeam reports whether the request user may see the team and the
+// boards and feedbacks inside it.
+func canViewTeam(ctx context.Context, store storages.Storage, teamId uuid.UUID) bool {
+	if middlewares.ContextHasPermission(ctx, middlewares.ViewAllTeams) {
+		return true
+	}
+
+	return requestTeamMembership(ctx, store, teamId) != nil
+}
+
+func teamPermissionDenied(w http.ResponseWriter) error {
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusForbidden,
+		Data:   &core.APIError{Detail: "Permission denied"},
+	})
+}
+
+func teamNotFound(w http.ResponseWriter) error {
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusNotFound,
+		Data:   &core.APIError{Detail: "Team not found"},
+	})
+}
+
+func (t *TeamService) GetAllTeamsHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	limit, offset := core.Pagination(r)
+
+	var teams []*models.Team
+	var err error
+	if middlewares.ContextHasPermission(r.Context(), middlewares.ViewAllTeams) {
+		teams, err = t.Store.GetAllTeams(limit, offset)
+	} else {
+		teams, err = t.Store.GetTeamsForUser(requestUser.Id, limit, offset)
+	}
+
+	if err != nil {
+		log.Println("Error in fetching the teams", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to fetch teams"},
+		})
+	}
+
+	return core.ListAPIResponse(w, &core.ListAPI{
+		Status: http.StatusOK,
+		Result: &core.ListAPIResponseBody{
+			Count:  len(teams),
+			Result: teams,
+		},
+	})
+}
+
+func (t *TeamService) GetTeamByIdHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	team, err := t.Store.GetTeamById(id)
+	if err != nil || !canViewTeam(r.Context(), t.Store, id) {
+		return teamNotFound(w)
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   team,
+	})
+}
+
+func (t *TeamService) CreateTeamHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	var teamRequest models.CreateTeamRequest
+
+	json.NewDecoder(r.Body).Decode(&teamRequest)
+	structErr := models.ValidateStruct(&teamRequest)
+	if structErr != nil {
+		log.Println("Error in validating the team struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	team := models.NewTeam(&teamRequest, requestUser.Id)
+	owner := models.NewTeamMembership(team.Id, requestUser.Id, models.TeamRoleOwner)
+
+	err := t.Store.CreateTeam(*team, *owner)
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusCreated,
+		Data:   team,
+	})
+}
+
+func (t *TeamService) UpdateTeamHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	team, err := t.Store.GetTeamById(id)
+	if err != nil || !canViewTeam(r.Context(), t.Store, id) {
+		return teamNotFound(w)
+	}
+
+	if !middlewares.ContextHasTeamPermission(r.Context(), requestTeamMembership(r.Context(), t.Store, id), middlewares.UpdateTeams) {
+		return teamPermissionDenied(w)
+	}
+
+	var teamRequest models.UpdateTeamRequest
+
+	json.NewDecoder(r.Body).Decode(&teamRequest)
+	structErr := models.ValidateStruct(&teamRequest)
+	if structErr != nil {
+		log.Println("Error in validating the team struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	team = models.UpdateTeam(team, &teamRequest)
+
+	newTeam, storeErr := t.Store.UpdateTeam(*team)
+	if storeErr != nil {
+		msg := common.AnyToAnyStructField(storeErr, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   newTeam,
+	})
+}
+
+func (t *TeamService) DeleteTeamHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	if !canViewTeam(r.Context(), t.Store, id) {
+		return teamNotFound(w)
+	}
+
+	if !middlewares.ContextHasTeamPermission(r.Context(), requestTeamMembership(r.Context(), t.Store, id), middlewares.DeleteTeams) {
+		return teamPermissionDenied(w)
+	}
+
+	team, err := t.Store.GetTeamById(id)
+	if err != nil {
+		return teamNotFound(w)
+	}
+
+	err = t.Store.DeleteTeam(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return teamNotFound(w)
+	}
+
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	recordAuditEvent(r, t.Store, models.AuditTeamDeleted, models.AuditTargetTeam, id.String(), team, nil)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   map[string]string{"detail": "Team deleted successfully"},
+	})
+}
+
+func (t *TeamService) GetTeamMembersHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	if !canViewTeam(r.Context(), t.Store, id) {
+		return teamNotFound(w)
+	}
+
+	members, err := t.Store.GetTeamMembers(id)
+	if err != nil {
+		log.Println("Error in fetching the team members", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to fetch team members"},
+		})
+	}
+
+	return core.ListAPIResponse(w, &core.ListAPI{
+		Status: http.StatusOK,
+		Result: &core.ListAPIResponseBody{
+			Count:  len(members),
+			Result: members,
+		},
+	})
+}
+
+// AddTeamMemberHandler adds a user to the team, or changes their role when
+// they are already in it. Only owners can make someone else an owner.
+func (t *TeamService) AddTeamMemberHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	if !canViewTeam(r.Context(), t.Store, id) {
+		return teamNotFound(w)
+	}
+
+	membership := requestTeamMembership(r.Context(), t.Store, id)
+	if !middlewares.ContextHasTeamPermission(r.Context(), membership, middlewares.ManageTeamMembers) {
+		return teamPermissionDenied(w)
+	}
+
+	var memberRequest models.AddTeamMemberRequest
+
+	json.NewDecoder(r.Body).Decode(&memberRequest)
+	structErr := models.ValidateStruct(&memberRequest)
+	if structErr != nil {
+		log.Println("Error in validating the team member struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	userId, err := uuid.Parse(memberRequest.UserId)
+	if err != nil {
+		log.Println("Error in parsing the user id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	user, err := t.Store.GetUserById(userId)
+	if err != nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "User not found"},
+		})
+	}
+
+	if memberRequest.Role == models.TeamRoleOwner && !t.canGrantOwner(r.Context(), membership) {
+		return teamPermissionDenied(w)
+	}
+
+	var before *models.TeamMembership
+	newMembership := models.NewTeamMembership(id, userId, memberRequest.Role)
+	if existing, err := t.Store.GetTeamMembership(id, userId); err == nil {
+		// Adding an existing owner again changes their role, with the same
+		// rules as UpdateTeamMemberHandler.
+		if existing.Role == models.TeamRoleOwner && !t.canGrantOwner(r.Context(), membership) {
+			return teamPermissionDenied(w)
+		}
+
+		if existing.Role == models.TeamRoleOwner && newMembership.Role != models.TeamRoleOwner && !t.hasOtherOwner(id) {
+			return lastTeamOwner(w)
+		}
+		newMembership.CreatedAt = existing.CreatedAt
+		before = existing
+	}
+
+	err = t.Store.SaveTeamMember(*newMembership)
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	newMembership.User = user
+	recordAuditEvent(r, t.Store, models.AuditTeamMemberAdded, models.AuditTargetTeam, id.String(), before, newMembership)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusCreated,
+		Data:   newMembership,
+	})
+}
+
+func (t *TeamService) UpdateTeamMemberHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	userId, err := uuid.Parse(mux.Vars(r)["user_id"])
+	if err != nil {
+		log.Println("Error in parsing the user id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	if !canViewTeam(r.Context(), t.Store, id) {
+		return teamNotFound(w)
+	}
+
+	membership := requestTeamMembership(r.Context(), t.Store, id)
+	if !middlewares.ContextHasTeamPermission(r.Context(), membership, middlewares.ManageTeamMembers) {
+		return teamPermissionDenied(w)
+	}
+
+	var memberRequest models.UpdateTeamMemberRequest
+
+	json.NewDecoder(r.Body).Decode(&memberRequest)
+	structErr := models.ValidateStruct(&memberRequest)
+	if structErr != nil {
+		log.Println("Error in validating the team member struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	existing, err := t.Store.GetTeamMembership(id, userId)
+	if err != nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusNotFound,
+			Data:   &core.APIError{Detail: "Team member not found"},
+		})
+	}
+
+	if (memberRequest.Role == models.TeamRoleOwner || existing.Role == models.TeamRoleOwner) && !t.canGrantOwner(r.Context(), membership) {
+		return teamPermissionDenied(w)
+	}
+
+	if existing.Role == models.TeamRoleOwner && memberRequest.Role != models.TeamRoleOwner && !t.hasOtherOwner(id) {
+		return lastTeamOwner(w)
+	}
+
+	before := *existing
+	existing.Role = memberRequest.Role
+	err = t.Store.SaveTeamMember(*existing)
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	existing.User, _ = t.Store.GetUserById(userId)
+	recordAuditEvent(r, t.Store, models.AuditTeamMemberRoleChanged, models.AuditTargetTeam, id.String(), before, existing)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   existing,
+	})
+}
+
+// RemoveTeamMemberHandler removes a user from the team, members can always
+// remove themselves to leave it.
+func (t *TeamService) RemoveTeamMemberHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	userId, err := uuid.Parse(mux.Vars(r)["user_id"])
+	if err != nil {
+		log.Println("Error in parsing the user id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	if !canViewTeam(r.Context(), t.Store, id) {
+		return teamNotFound(w)
+	}
+
+	existing, err := t.Store.GetTeamMembership(id, userId)
+	if err != nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusNotFound,
+			Data:   &core.APIError{Detail: "Team member not found"},
+		})
+	}
+
+	membership := requestTeamMembership(r.Context(), t.Store, id)
+	if userId != requestUser.Id {
+		if !middlewares.ContextHasTeamPermission(r.Context(), membership, middlewares.ManageTeamMembers) {
+			return teamPermissionDenied(w)
+		}
+
+		if existing.Role == models.TeamRoleOwner && !t.canGrantOwner(r.Context(), membership) {
+			return teamPermissionDenied(w)
+		}
+	}
+
+	if existing.Role == models.TeamRoleOwner && !t.hasOtherOwner(id) {
+		return lastTeamOwner(w)
+	}
+
+	err = t.Store.RemoveTeamMember(id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusNotFound,
+			Data:   &core.APIError{Detail: "Team member not found"},
+		})
+	}
+
+	if err != nil {
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	recordAuditEvent(r, t.Store, models.AuditTeamMemberRemoved, models.AuditTargetTeam, id.String(), existing, nil)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   map[string]string{"detail": "Team member removed successfully"},
+	})
+}
+
+// canGrantOwner reports whether the request user may hand out or take away
+// the owner role, only owners and users managing every team can.
+func (t *TeamService) canGrantOwner(ctx context.Context, membership *models.TeamMembership) bool {
+	if middlewares.ContextHasPermission(ctx, middlewares.ManageTeamMembers) {
+		return true
+	}
+
+	return membership != nil && membership.Role == models.TeamRoleOwner
+}
+
+func (t *TeamService) hasOtherOwner(teamId uuid.UUID) bool {
+	count, err := t.Store.CountTeamOwners(teamId)
+	if err != nil {
+		log.Println("Error in counting the team owners", err)
+		return false
+	}
+
+	return count > 1
+}
+
+func lastTeamOwner(w http.ResponseWriter) error {
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusBadRequest,
+		Data:   &core.APIError{Detail: "A team needs at least one owner"},
+	})
+}
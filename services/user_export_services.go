@@ -0,0 +1,78 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/Aakash-Pandit/reetro-golang/core"
+	"github.com/Aakash-Pandit/reetro-golang/middlewares"
+	"github.com/Aakash-Pandit/reetro-golang/models"
+)
+
+// ExportUserDataHandler returns everything stored about the request user as
+// a JSON archive to download.
+func (u *UserService) ExportUserDataHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	if _, scoped := middlewares.TokenScopesFromContext(r.Context()); scoped {
+		return personalAccessTokenForbidden(w)
+	}
+
+	export, err := u.Store.GetUserDataExport(requestUser.Id)
+	if err != nil {
+		log.Println("Error in exporting the user data", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Unable to export the user data"},
+		})
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"user_data_%s.json\"", export.ExportedAt.Format("20060102T150405Z")))
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   export,
+	})
+}
+
+// DeleteOwnAccountHandler deletes the account of the request user. The boards
+// and feedback they wrote are kept and shown as written by a deleted user.
+func (u *UserService) DeleteOwnAccountHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	if _, scoped := middlewares.TokenScopesFromContext(r.Context()); scoped {
+		return personalAccessTokenForbidden(w)
+	}
+
+	if deleted, err := u.deleteUser(w, requestUser.Id); !deleted {
+		return err
+	}
+
+	// Only the id is recorded, the profile of a deleted account must not be
+	// kept in the audit log.
+	recordAuditEvent(r, u.Store, models.AuditUserDeleted, models.AuditTargetUser, requestUser.Id.String(), nil, nil)
+
+	redisErr := u.RedisClient.Del(requestUser.Id.String())
+	if redisErr != nil {
+		log.Println("Error in deleting the user from redis", redisErr)
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   map[string]string{"detail": "Account deleted successfully"},
+	})
+}
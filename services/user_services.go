@@ -1,11 +1,19 @@
 package services
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/Aakash-Pandit/reetro-golang/common"
+	"github.com/Aakash-Pandit/reetro-golang/config"
 	"github.com/Aakash-Pandit/reetro-golang/core"
 	"github.com/Aakash-Pandit/reetro-golang/middlewares"
 	"github.com/Aakash-Pandit/reetro-golang/models"
@@ -15,15 +23,20 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
 type UserService struct {
 	Store       storages.Storage
-	User        RequestUser
 	RedisClient storages.RedisStoreInterface
 	Email       common.EmailInterface
+	GoogleAuth  middlewares.GoogleAuthInterface
+	// OIDCProviders are the OpenID Connect providers users can log in with,
+	// by name.
+	OIDCProviders map[string]*middlewares.OIDCProvider
 }
 
-func NewUserService(store storages.Storage, user RequestUser, redisClient storages.RedisStoreInterface, emailInterface common.EmailInterface) *UserService {
-	return &UserService{Store: store, User: user, RedisClient: redisClient, Email: emailInterface}
+func NewUserService(store storages.Storage, redisClient storages.RedisStoreInterface, emailInterface common.EmailInterface, googleAuth middlewares.GoogleAuthInterface) *UserService {
+	return &UserService{Store: store, RedisClient: redisClient, Email: emailInterface, GoogleAuth: googleAuth}
 }
 
 func (u *UserService) GetAllUsersHandler(w http.ResponseWriter, r *http.Request) error {
@@ -37,14 +50,6 @@ func (u *UserService) GetAllUsersHandler(w http.ResponseWriter, r *http.Request)
 		})
 	}
 
-	user := u.User.GetRequestUser(r)
-	if user.UserType != models.SuperAdmin {
-		return core.APIResponse(w, &core.Response{
-			Status: http.StatusUnauthorized,
-			Data:   &core.APIError{Detail: "Unauthorized Access"},
-		})
-	}
-
 	return core.ListAPIResponse(w, &core.ListAPI{
 		Status: http.StatusOK,
 		Result: &core.ListAPIResponseBody{
@@ -93,6 +98,8 @@ func (u *UserService) GetUserByIdHandler(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// CreateUserHandler signs a user up, they always start as a guest user. A
+// user_type in the payload is ignored.
 func (u *UserService) CreateUserHandler(w http.ResponseWriter, r *http.Request) error {
 	var userRequest models.CreateUserRequest
 
@@ -108,7 +115,31 @@ func (u *UserService) CreateUserHandler(w http.ResponseWriter, r *http.Request)
 
 	defer r.Body.Close()
 
-	user, bcrypt_err := models.NewUser(&userRequest)
+	return u.createUser(w, &userRequest, models.GuestUser)
+}
+
+// CreateUserAsAdminHandler creates a user with the role of the payload, the
+// route requires the permission to manage user roles.
+func (u *UserService) CreateUserAsAdminHandler(w http.ResponseWriter, r *http.Request) error {
+	var userRequest models.AdminCreateUserRequest
+
+	json.NewDecoder(r.Body).Decode(&userRequest)
+	err := models.ValidateStruct(&userRequest)
+	if err != nil {
+		log.Println("Error in validating the user struct", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   err,
+		})
+	}
+
+	defer r.Body.Close()
+
+	return u.createUser(w, &userRequest.CreateUserRequest, userRequest.UserType)
+}
+
+func (u *UserService) createUser(w http.ResponseWriter, userRequest *models.CreateUserRequest, role models.Role) error {
+	user, bcrypt_err := models.NewUser(userRequest, role)
 	if bcrypt_err != nil {
 		return core.APIResponse(w, &core.Response{
 			Status: http.StatusBadRequest,
@@ -134,19 +165,99 @@ func (u *UserService) CreateUserHandler(w http.ResponseWriter, r *http.Request)
 		log.Println("Error in setting the user in redis", redisErr)
 	}
 
+	emailErr := u.sendVerificationEmail(newUser.Id, newUser.Email)
+	if emailErr != nil {
+		log.Println("Error while sending the verification email", emailErr)
+	}
+
 	return core.APIResponse(w, &core.Response{
 		Status: http.StatusCreated,
 		Data:   userResponse,
 	})
 }
 
-func (u *UserService) UpdateUserHandler(w http.ResponseWriter, r *http.Request) error {
-	return nil
+func (u *UserService) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) error {
+	invalidLink := &core.Response{
+		Status: http.StatusBadRequest,
+		Data:   &core.APIError{Detail: "Invalid or expired verification link"},
+	}
+
+	claims, err := middlewares.ValidateTokenOfType(r.URL.Query().Get("token"), middlewares.EMAIL_VERIFICATION_TOKEN)
+	if err != nil {
+		log.Println("Error in validating the verification token", err)
+		return core.APIResponse(w, invalidLink)
+	}
+
+	id, _ := uuid.Parse(middlewares.ClaimString(claims, "id"))
+	user, err := u.Store.GetUserById(id)
+	if err != nil || user.Email != middlewares.ClaimString(claims, "email") {
+		log.Println("Verification token does not match the user", err)
+		return core.APIResponse(w, invalidLink)
+	}
+
+	if !user.EmailVerified {
+		err = u.Store.VerifyUserEmail(user.Id)
+		if err != nil {
+			log.Println("Error while verifying the email", err)
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusInternalServerError,
+				Data:   &core.APIError{Detail: "Error while verifying the email"},
+			})
+		}
+
+		user.EmailVerified = true
+		redisErr := u.RedisClient.Set(user.Id.String(), user)
+		if redisErr != nil {
+			log.Println("Error in setting the user in redis", redisErr)
+		}
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   &APISuccessResponse{Detail: "Email verified successfully"},
+	})
 }
 
-func (u *UserService) DeleteUserHandler(w http.ResponseWriter, r *http.Request) error {
-	id, err := uuid.Parse(mux.Vars(r)["id"])
+func (u *UserService) ResendVerificationEmailHandler(w http.ResponseWriter, r *http.Request) error {
+	var payload ResendVerificationPayload
 
+	json.NewDecoder(r.Body).Decode(&payload)
+	err := models.ValidateStruct(payload)
+	if err != nil {
+		log.Println("Error in validating the resend verification payload", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   err,
+		})
+	}
+
+	defer r.Body.Close()
+
+	user, dbError := u.Store.VerifyUserByEmail(payload.Email)
+	if dbError == nil && !user.EmailVerified {
+		emailErr := u.sendVerificationEmail(user.Id, user.Email)
+		if emailErr != nil {
+			log.Println("Error while sending the verification email", emailErr)
+		}
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   &APISuccessResponse{Detail: "If the email needs verification, a new link has been sent"},
+	})
+}
+
+func (u *UserService) UpdateUserHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
 	if err != nil {
 		log.Println("Error in parsing the id", err)
 		return core.APIResponse(w, &core.Response{
@@ -155,15 +266,170 @@ func (u *UserService) DeleteUserHandler(w http.ResponseWriter, r *http.Request)
 		})
 	}
 
-	err = u.Store.DeleteUser(id)
+	if requestUser.Id != id && !middlewares.ContextHasPermission(r.Context(), middlewares.ManageUsers) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusForbidden,
+			Data:   &core.APIError{Detail: "You can only update your own profile"},
+		})
+	}
+
+	var userRequest models.UpdateUserRequest
+
+	json.NewDecoder(r.Body).Decode(&userRequest)
+	structErr := models.ValidateStruct(&userRequest)
+	if structErr != nil {
+		log.Println("Error in validating the user struct", structErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   structErr,
+		})
+	}
+
+	defer r.Body.Close()
+
+	user, err := u.Store.GetUserById(id)
 	if err != nil {
-		log.Println("Error in fetching the user:", err)
+		log.Println("Error in fetching the user", err)
 		return core.APIResponse(w, &core.Response{
 			Status: http.StatusBadRequest,
 			Data:   &core.APIError{Detail: "User not found"},
 		})
 	}
 
+	if userRequest.UserType != "" && userRequest.UserType != user.UserType && !middlewares.ContextHasPermission(r.Context(), middlewares.ManageUserRoles) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusForbidden,
+			Data:   &core.APIError{Detail: "Only super admin can change the user type"},
+		})
+	}
+
+	if userRequest.Username != "" && userRequest.Username != user.Username {
+		existingUser, _ := u.Store.VerifyUserByUsername(userRequest.Username)
+		if existingUser != nil && existingUser.Id != user.Id {
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusConflict,
+				Data:   &core.APIError{Detail: "Username already exists"},
+			})
+		}
+	}
+
+	if userRequest.Email != "" && userRequest.Email != user.Email {
+		existingUser, _ := u.Store.VerifyUserByEmail(userRequest.Email)
+		if existingUser != nil && existingUser.Id != user.Id {
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusConflict,
+				Data:   &core.APIError{Detail: "Email already exists"},
+			})
+		}
+	}
+
+	emailChanged := userRequest.Email != "" && userRequest.Email != user.Email
+	before := *user
+	user = models.UpdateUser(user, &userRequest)
+
+	updatedUser, store_error := u.Store.UpdateUser(*user)
+	if store_error != nil {
+		if storages.IsUniqueViolation(store_error) {
+			return core.APIResponse(w, &core.Response{
+				Status: http.StatusConflict,
+				Data:   &core.APIError{Detail: "Username or Email already exists"},
+			})
+		}
+
+		msg := common.AnyToAnyStructField(store_error, &core.DatabaseError{})
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	redisErr := u.RedisClient.Set(updatedUser.Id.String(), updatedUser)
+	if redisErr != nil {
+		log.Println("Error in setting the user in redis", redisErr)
+	}
+
+	if emailChanged {
+		emailErr := u.sendVerificationEmail(updatedUser.Id, updatedUser.Email)
+		if emailErr != nil {
+			log.Println("Error while sending the verification email", emailErr)
+		}
+	}
+
+	auditAction := models.AuditUserUpdated
+	if updatedUser.UserType != before.UserType {
+		auditAction = models.AuditUserRoleChanged
+	}
+	recordAuditEvent(r, u.Store, auditAction, models.AuditTargetUser, updatedUser.Id.String(), before, updatedUser)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   updatedUser,
+	})
+}
+
+// deleteUser deletes the user unless they are the only owner of a team, the
+// ownership has to be transferred first so no team is left without one. When
+// the user is not deleted the error response is written and false returned.
+func (u *UserService) deleteUser(w http.ResponseWriter, id uuid.UUID) (bool, error) {
+	teams, err := u.Store.GetTeamsOwnedOnlyBy(id)
+	if err != nil {
+		log.Println("Error in fetching the teams owned by the user", err)
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return false, core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	if len(teams) > 0 {
+		names := make([]string, len(teams))
+		for i, team := range teams {
+			names[i] = team.Name
+		}
+
+		return false, core.APIResponse(w, &core.Response{
+			Status: http.StatusConflict,
+			Data:   &core.APIError{Detail: "Transfer the ownership of these teams first: " + strings.Join(names, ", ")},
+		})
+	}
+
+	err = u.Store.DeleteUser(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, core.APIResponse(w, &core.Response{
+			Status: http.StatusNotFound,
+			Data:   &core.APIError{Detail: "User not found"},
+		})
+	}
+
+	if err != nil {
+		log.Println("Error in deleting the user:", err)
+		msg := common.AnyToAnyStructField(err, &core.DatabaseError{})
+		return false, core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   msg,
+		})
+	}
+
+	return true, nil
+}
+
+func (u *UserService) DeleteUserHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	if deleted, err := u.deleteUser(w, id); !deleted {
+		return err
+	}
+
+	recordAuditEvent(r, u.Store, models.AuditUserDeleted, models.AuditTargetUser, id.String(), nil, nil)
+
 	redisErr := u.RedisClient.Del(id.String())
 	if redisErr != nil {
 		log.Println("Error in setting the user in redis", redisErr)
@@ -175,6 +441,49 @@ func (u *UserService) DeleteUserHandler(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+func (u *UserService) UnlockUserHandler(w http.ResponseWriter, r *http.Request) error {
+	requestUser, ok := middlewares.RequestUserFromContext(r.Context())
+	if !ok {
+		log.Println("Error while fetching Request User")
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unable to fetch user from token"},
+		})
+	}
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		log.Println("Error in parsing the id", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: err.Error()},
+		})
+	}
+
+	user, err := u.Store.GetUserById(id)
+	if err != nil {
+		log.Println("Error in fetching the user", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "User not found"},
+		})
+	}
+
+	clearFailedLogins(u.RedisClient, user.Username)
+
+	err = u.Store.UnlockAccountLockouts(user.Username, requestUser.Id)
+	if err != nil {
+		log.Println("Error while recording the unlock", err)
+	}
+
+	recordAuditEvent(r, u.Store, models.AuditUserUnlocked, models.AuditTargetUser, user.Id.String(), nil, nil)
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   &APISuccessResponse{Detail: "User unlocked successfully"},
+	})
+}
+
 func (u *UserService) LoginHandler(w http.ResponseWriter, r *http.Request) error {
 	var loginRequest models.LoginRequest
 
@@ -190,33 +499,204 @@ func (u *UserService) LoginHandler(w http.ResponseWriter, r *http.Request) error
 
 	defer r.Body.Close()
 
+	ipAddress := core.ClientIP(r)
+	retryAfter := loginLockedFor(u.RedisClient, loginRequest.Username, ipAddress)
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusTooManyRequests,
+			Data:   &core.APIError{Detail: "Too many failed login attempts, try again later"},
+		})
+	}
+
+	// The password is compared even for unknown usernames so both failures
+	// take the same time and return the same error.
+	passwordHash := dummyPasswordHash
 	user, dbError := u.Store.VerifyUserByUsername(loginRequest.Username)
-	if dbError != nil {
+	if dbError == nil {
+		passwordHash = []byte(user.Password)
+	}
+
+	passwordError := bcrypt.CompareHashAndPassword(passwordHash, []byte(loginRequest.Password))
+	if dbError != nil || passwordError != nil {
+		recordFailedLogin(u.Store, u.RedisClient, loginRequest.Username, ipAddress)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Invalid username or password"},
+		})
+	}
+
+	// The failed attempts are cleared by loginResponse once no second factor
+	// is left to check.
+	if config.EnvBool("EMAIL_VERIFICATION_REQUIRED_FOR_LOGIN", false) && !user.EmailVerified {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusForbidden,
+			Data:   &core.APIError{Detail: "Email address is not verified"},
+		})
+	}
+
+	return u.loginResponse(w, r, user)
+}
+
+func (u *UserService) GoogleLoginHandler(w http.ResponseWriter, r *http.Request) error {
+	var payload GoogleLoginPayload
+
+	json.NewDecoder(r.Body).Decode(&payload)
+	err := models.ValidateStruct(payload)
+	if err != nil {
+		log.Println("Error in validating the google login payload", err)
 		return core.APIResponse(w, &core.Response{
 			Status: http.StatusBadRequest,
-			Data:   &core.APIError{Detail: "Invalid Username"},
+			Data:   err,
+		})
+	}
+
+	defer r.Body.Close()
+
+	googlePayload, googleErr := u.GoogleAuth.GoogleTokenValidation(payload.IdToken)
+	if googleErr != nil {
+		log.Println("Error in validating the google token", googleErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Invalid Google Token"},
+		})
+	}
+
+	// Accounts are found by the Google subject, like the ones of an OpenID
+	// Connect provider, the first login links the account with the email.
+	identity := &middlewares.OIDCIdentity{}
+	identity.Subject, _ = googlePayload["sub"].(string)
+	identity.Email, _ = googlePayload["email"].(string)
+	identity.FirstName, _ = googlePayload["given_name"].(string)
+	identity.LastName, _ = googlePayload["family_name"].(string)
+
+	user, dbError := u.Store.GetUserByIdentity(middlewares.GOOGLE_IDENTITY_PROVIDER, identity.Subject)
+	if errors.Is(dbError, sql.ErrNoRows) {
+		return u.linkOIDCIdentity(w, r, middlewares.GOOGLE_IDENTITY_PROVIDER, identity, "")
+	}
+
+	if dbError != nil {
+		log.Println("Error in fetching the user", dbError)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while Fetching User"},
 		})
 	}
 
-	passwordError := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginRequest.Password))
-	if passwordError != nil {
+	return u.loginResponse(w, r, user)
+}
+
+func (u *UserService) RefreshTokenHandler(w http.ResponseWriter, r *http.Request) error {
+	var payload RefreshTokenPayload
+
+	json.NewDecoder(r.Body).Decode(&payload)
+	err := models.ValidateStruct(payload)
+	if err != nil {
+		log.Println("Error in validating the refresh token payload", err)
 		return core.APIResponse(w, &core.Response{
 			Status: http.StatusBadRequest,
-			Data:   &core.APIError{Detail: "Invalid Password"},
+			Data:   err,
 		})
 	}
 
-	token, tokenErr := middlewares.GenerateJSONWebToken(user.Id.String(), user.Email)
+	defer r.Body.Close()
+
+	claims, tokenErr := middlewares.ValidateTokenOfType(payload.RefreshToken, middlewares.REFRESH_TOKEN)
 	if tokenErr != nil {
 		return core.APIResponse(w, &core.Response{
-			Status: http.StatusBadRequest,
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Invalid refresh token"},
+		})
+	}
+
+	// Refresh tokens are single use. Revoking the presented one is how it is
+	// claimed, a request that loses the race is treated like a reused token.
+	claimed, redisErr := middlewares.ClaimToken(u.RedisClient, claims)
+	if redisErr != nil {
+		log.Println("Error while revoking the refresh token", redisErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
 			Data:   &core.APIError{Detail: "Error While Generating Token"},
 		})
 	}
 
+	if !claimed {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Refresh token has been revoked"},
+		})
+	}
+
+	id, _ := uuid.Parse(middlewares.ClaimString(claims, "id"))
+	user, dbErr := u.Store.GetUserById(id)
+	if dbErr != nil {
+		log.Println("Error in fetching the user", dbErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Unauthorized"},
+		})
+	}
+
+	sessionId, hasSession := middlewares.SessionIdFromClaims(claims)
+	tokens, tokenErr := u.refreshSession(user, sessionId, hasSession)
+	if tokenErr != nil {
+		log.Println("Error while refreshing the session", tokenErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Session has been revoked"},
+		})
+	}
+
+	return core.APIResponse(w, &core.Response{
+		Status: http.StatusOK,
+		Data:   tokens,
+	})
+}
+
+func (u *UserService) LogoutHandler(w http.ResponseWriter, r *http.Request) error {
+	var payload LogoutPayload
+
+	json.NewDecoder(r.Body).Decode(&payload)
+	defer r.Body.Close()
+
+	claims, ok := middlewares.TokenClaimsFromContext(r.Context())
+	if !ok {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusUnauthorized,
+			Data:   &core.APIError{Detail: "Invalid token"},
+		})
+	}
+
+	err := middlewares.RevokeToken(u.RedisClient, claims)
+	if err != nil {
+		log.Println("Error while revoking the access token", err)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error while logging out"},
+		})
+	}
+
+	if sessionId, ok := middlewares.SessionIdFromClaims(claims); ok {
+		userId, _ := uuid.Parse(middlewares.ClaimString(claims, "id"))
+		err = u.Store.RevokeUserSession(sessionId, userId)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Println("Error while revoking the session", err)
+		}
+	}
+
+	if payload.RefreshToken != "" {
+		refreshClaims, tokenErr := middlewares.ValidateTokenOfType(payload.RefreshToken, middlewares.REFRESH_TOKEN)
+		if tokenErr == nil && middlewares.ClaimString(refreshClaims, "id") == middlewares.ClaimString(claims, "id") {
+			err = middlewares.RevokeToken(u.RedisClient, refreshClaims)
+			if err != nil {
+				log.Println("Error while revoking the refresh token", err)
+			}
+		}
+	}
+
 	return core.APIResponse(w, &core.Response{
 		Status: http.StatusOK,
-		Data:   &core.TokenResponse{Token: token},
+		Data:   &APISuccessResponse{Detail: "Logged out successfully"},
 	})
 }
 
@@ -235,47 +715,137 @@ func (u *UserService) ForgotPasswordHandler(w http.ResponseWriter, r *http.Reque
 
 	defer r.Body.Close()
 
+	// The response is the same whether the email is known or not, otherwise
+	// this endpoint tells anyone which emails have an account.
+	successResponse := &core.Response{
+		Status: http.StatusOK,
+		Data:   &APISuccessResponse{Detail: "If an account exists for this email, a password reset link has been sent"},
+	}
+
 	user, dbError := u.Store.VerifyUserByEmail(payload.Email)
 	if dbError != nil {
-		log.Println("Error in fetching the user", err)
+		log.Println("Error in fetching the user", dbError)
+		return core.APIResponse(w, successResponse)
+	}
+
+	rawToken, tokenErr := common.GenerateSecureToken(32)
+	if tokenErr != nil {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error While Generating Reset Token, Try again later"},
+		})
+	}
+
+	resetToken := models.NewPasswordResetToken(user.Id, common.HashToken(rawToken))
+	dbError = u.Store.CreatePasswordResetToken(*resetToken)
+	if dbError != nil {
+		log.Println("Error while saving the reset token", dbError)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
+			Data:   &core.APIError{Detail: "Error While Generating Reset Token, Try again later"},
+		})
+	}
+
+	resetLink := fmt.Sprintf("%s?token=%s", passwordResetURL(), rawToken)
+	// A failure to send is only logged, answering differently would tell
+	// that the email has an account.
+	emailErr := u.Email.SendEmailForPasswordReset(user.Email, "Reset Password", resetLink)
+	if emailErr != nil {
+		log.Println("Error while sending email", emailErr)
+	}
+
+	return core.APIResponse(w, successResponse)
+}
+
+func (u *UserService) ResetPasswordConfirmHandler(w http.ResponseWriter, r *http.Request) error {
+	var payload ResetPasswordConfirmPayload
+
+	json.NewDecoder(r.Body).Decode(&payload)
+	err := models.ValidateStruct(payload)
+	if err != nil {
+		log.Println("Error in validating the reset password payload", err)
 		return core.APIResponse(w, &core.Response{
 			Status: http.StatusBadRequest,
-			Data:   &core.APIError{Detail: "User not found"},
+			Data:   err,
 		})
 	}
 
-	newPassword := common.GenerateRandomPassword()
-	password, bcrypt_err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
-	if bcrypt_err != nil {
+	defer r.Body.Close()
+
+	resetToken, dbErr := u.Store.GetPasswordResetTokenByHash(common.HashToken(payload.Token))
+	if dbErr != nil || !resetToken.IsValid() {
+		log.Println("Invalid password reset token", dbErr)
 		return core.APIResponse(w, &core.Response{
 			Status: http.StatusBadRequest,
-			Data:   &core.APIError{Detail: "Error While Generating Password, Try again later"},
+			Data:   &core.APIError{Detail: "Invalid or expired reset token"},
 		})
 	}
 
-	user.Password = string(password)
+	// The password is checked against the username and email before the
+	// token is redeemed, a rejected password leaves the link usable.
+	user, dbErr := u.Store.GetUserById(resetToken.UserId)
+	if dbErr != nil {
+		log.Println("Error in fetching the user of the reset token", dbErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Invalid or expired reset token"},
+		})
+	}
 
-	dbError = u.Store.SavePassword(*user)
-	if dbError != nil {
-		log.Println("Error while updating password", err)
+	if passwordErr := models.PasswordErrors("ResetPasswordConfirmPayload.NewPassword", payload.NewPassword, user.Username, user.Email); passwordErr != nil {
 		return core.APIResponse(w, &core.Response{
 			Status: http.StatusBadRequest,
+			Data:   passwordErr,
+		})
+	}
+
+	// The token is redeemed before the password is saved, of two requests
+	// racing with the same token only one gets through.
+	userId, dbErr := u.Store.UsePasswordResetToken(resetToken.TokenHash)
+	if errors.Is(dbErr, sql.ErrNoRows) {
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Invalid or expired reset token"},
+		})
+	}
+
+	if dbErr != nil {
+		log.Println("Error while redeeming the reset token", dbErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusInternalServerError,
 			Data:   &core.APIError{Detail: "Error while Updating Password"},
 		})
 	}
 
-	emailErr := u.Email.SendEmailForPasswordReset(user.Email, "Reset Password", newPassword)
-	if emailErr != nil {
-		log.Println("Error while sending email", emailErr)
+	bcryptPassword, bcryptErr := bcrypt.GenerateFromPassword([]byte(payload.NewPassword), bcrypt.DefaultCost)
+	if bcryptErr != nil {
+		log.Println("Unable to Encrypt Password:", bcryptErr)
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusBadRequest,
+			Data:   &core.APIError{Detail: "Error While Generating Password, Try again later"},
+		})
+	}
+
+	dbErr = u.Store.SavePassword(models.User{Id: userId, Password: string(bcryptPassword)})
+	if dbErr != nil {
+		log.Println("Error while updating password", dbErr)
 		return core.APIResponse(w, &core.Response{
 			Status: http.StatusBadRequest,
 			Data:   &core.APIError{Detail: "Error while Updating Password"},
 		})
 	}
 
+	// Every outstanding link of the user is burned, not only the one used.
+	dbErr = u.Store.MarkPasswordResetTokensUsed(userId)
+	if dbErr != nil {
+		log.Println("Error while invalidating the reset tokens", dbErr)
+	}
+
+	u.revokeOtherSessions(r, userId)
+
 	return core.APIResponse(w, &core.Response{
 		Status: http.StatusOK,
-		Data:   &APISuccessResponse{Detail: "Your Password is Successfully Updated, Please check your email"},
+		Data:   &APISuccessResponse{Detail: "Password Reset Successfully"},
 	})
 }
 
@@ -294,9 +864,22 @@ func (u *UserService) ResetPasswordHandler(w http.ResponseWriter, r *http.Reques
 
 	defer r.Body.Close()
 
+	// The old password is checked like a login, so it shares the lockout of
+	// the username and the ip address.
+	ipAddress := core.ClientIP(r)
+	retryAfter := loginLockedFor(u.RedisClient, payload.Username, ipAddress)
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		return core.APIResponse(w, &core.Response{
+			Status: http.StatusTooManyRequests,
+			Data:   &core.APIError{Detail: "Too many failed login attempts, try again later"},
+		})
+	}
+
 	user, dbErr := u.Store.VerifyUserByUsernamePassword(payload.Username, payload.OldPassword)
 	if dbErr != nil {
 		log.Println("Error in fetching the user", dbErr)
+		recordFailedLogin(u.Store, u.RedisClient, payload.Username, ipAddress)
 		return core.APIResponse(w, &core.Response{
 			Status: http.StatusBadRequest,
 			Data:   &core.APIError{Detail: "Error while Fetching User"},
@@ -322,8 +905,36 @@ func (u *UserService) ResetPasswordHandler(w http.ResponseWriter, r *http.Reques
 		})
 	}
 
+	u.revokeOtherSessions(r, user.Id)
+
 	return core.APIResponse(w, &core.Response{
 		Status: http.StatusOK,
 		Data:   &APISuccessResponse{Detail: "Password Reset Successfully"},
 	})
 }
+
+func passwordResetURL() string {
+	url := os.Getenv("PASSWORD_RESET_URL")
+	if url == "" {
+		url = fmt.Sprintf("http://localhost:%s/reset_password/confirm/", os.Getenv("APPLICATION_PORT"))
+	}
+	return url
+}
+
+func emailVerificationURL() string {
+	url := os.Getenv("EMAIL_VERIFICATION_URL")
+	if url == "" {
+		url = fmt.Sprintf("http://localhost:%s/verify_email/", os.Getenv("APPLICATION_PORT"))
+	}
+	return url
+}
+
+func (u *UserService) sendVerificationEmail(id uuid.UUID, email string) error {
+	token, err := middlewares.GenerateEmailVerificationToken(id.String(), email)
+	if err != nil {
+		return err
+	}
+
+	verificationLink := fmt.Sprintf("%s?token=%s", emailVerificationURL(), token)
+	return u.Email.SendEmailForVerification(email, "Verify your email address", verificationLink)
+}
@@ -0,0 +1,112 @@
+package storages
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+const AUDIT_EVENT_COLUMNS = "id, actor_id, action, target_type, target_id, before, after, ip_address, created_at"
+
+func scanAuditEvent(row rowScanner) (*models.AuditEvent, error) {
+	event := new(models.AuditEvent)
+
+	var actorId sql.NullString
+	var before, after []byte
+	err := row.Scan(&event.Id, &actorId, &event.Action, &event.TargetType, &event.TargetId, &before, &after, &event.IPAddress, &event.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if actorId.Valid {
+		if id, err := uuid.Parse(actorId.String); err == nil {
+			event.ActorId = &id
+		}
+	}
+
+	event.Before = before
+	event.After = after
+	return event, nil
+}
+
+// nullableJSON stores a missing snapshot as NULL rather than an empty string,
+// which is not valid JSONB.
+func nullableJSON(value []byte) any {
+	if len(value) == 0 {
+		return nil
+	}
+	return string(value)
+}
+
+func (p *PostgresStore) CreateAuditEvent(event models.AuditEvent) error {
+	_, err := p.DB.Exec(
+		"INSERT INTO audit_events ("+AUDIT_EVENT_COLUMNS+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		event.Id, event.ActorId, event.Action, event.TargetType, event.TargetId, nullableJSON(event.Before), nullableJSON(event.After), event.IPAddress, event.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the audit event", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetAuditEvents returns the events matching the filter, newest first.
+func (p *PostgresStore) GetAuditEvents(filter models.AuditEventFilter, limit, offset int) ([]*models.AuditEvent, error) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+
+	where := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ActorId != nil {
+		where("actor_id = $%d", *filter.ActorId)
+	}
+	if filter.Action != "" {
+		where("action = $%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		where("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetId != "" {
+		where("target_id = $%d", filter.TargetId)
+	}
+	if filter.From != nil {
+		where("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		where("created_at < $%d", *filter.To)
+	}
+
+	query := "SELECT " + AUDIT_EVENT_COLUMNS + " FROM audit_events"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, limit, offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := p.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*models.AuditEvent, 0)
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,214 @@
+package storages
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+const BOARD_COLUMN_COLUMNS = "id, board_id, key, title, description, color, position, created_at, modified_at"
+
+func scanBoardColumn(row rowScanner) (*models.BoardColumn, error) {
+	column := new(models.BoardColumn)
+	err := row.Scan(&column.Id, &column.BoardId, &column.Key, &column.Title, &column.Description, &column.Color, &column.Position, &column.CreatedAt, &column.ModifiedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return column, nil
+}
+
+func insertBoardColumn(tx *sql.Tx, column *models.BoardColumn) error {
+	_, err := tx.Exec(
+		"INSERT INTO board_columns ("+BOARD_COLUMN_COLUMNS+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		column.Id, column.BoardId, column.Key, column.Title, column.Description, column.Color, column.Position, column.CreatedAt, column.ModifiedAt,
+	)
+	return err
+}
+
+// renumberBoardColumns closes the gaps in the positions of the columns of
+// the board, keeping their order.
+func renumberBoardColumns(tx *sql.Tx, boardId uuid.UUID) error {
+	_, err := tx.Exec(
+		"UPDATE board_columns SET position = numbered.position FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY position, created_at) - 1 AS position FROM board_columns WHERE board_id = $1) AS numbered WHERE board_columns.id = numbered.id",
+		boardId,
+	)
+	return err
+}
+
+func (p *PostgresStore) GetBoardColumns(boardId uuid.UUID) ([]*models.BoardColumn, error) {
+	rows, err := p.DB.Query("SELECT "+BOARD_COLUMN_COLUMNS+" FROM board_columns WHERE board_id = $1 ORDER BY position", boardId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make([]*models.BoardColumn, 0)
+	for rows.Next() {
+		column, err := scanBoardColumn(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+// CreateBoardColumn adds the column after the last column of the board. The
+// board row is locked while counting, it returns sql.ErrNoRows when the
+// board already has models.BOARD_COLUMNS_MAX columns.
+func (p *PostgresStore) CreateBoardColumn(column models.BoardColumn) (models.BoardColumn, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return models.BoardColumn{}, err
+	}
+	defer tx.Rollback()
+
+	if err := lockBoard(tx, column.BoardId); err != nil {
+		log.Println("Error in locking the board", err)
+		return models.BoardColumn{}, err
+	}
+
+	err = tx.QueryRow("SELECT COUNT(*) FROM board_columns WHERE board_id = $1", column.BoardId).Scan(&column.Position)
+	if err != nil {
+		return models.BoardColumn{}, err
+	}
+
+	if column.Position >= models.BOARD_COLUMNS_MAX {
+		return models.BoardColumn{}, sql.ErrNoRows
+	}
+
+	if err := insertBoardColumn(tx, &column); err != nil {
+		log.Println("Error in creating the board column", err)
+		return models.BoardColumn{}, err
+	}
+
+	return column, tx.Commit()
+}
+
+// UpdateBoardColumn returns sql.ErrNoRows when the column is not on the
+// board. The key is left alone, feedback is filed under it.
+func (p *PostgresStore) UpdateBoardColumn(column models.BoardColumn) (models.BoardColumn, error) {
+	result, err := p.DB.Exec(
+		"UPDATE board_columns SET title = $1, description = $2, color = $3, modified_at = $4 WHERE id = $5 AND board_id = $6",
+		column.Title, column.Description, column.Color, column.ModifiedAt, column.Id, column.BoardId,
+	)
+	if err != nil {
+		log.Println("Error in updating the board column", err)
+		return models.BoardColumn{}, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return models.BoardColumn{}, err
+	}
+
+	if count == 0 {
+		return models.BoardColumn{}, sql.ErrNoRows
+	}
+
+	return column, nil
+}
+
+// ReorderBoardColumns puts the columns in the order of the ids, which have to
+// name every column of the board. sql.ErrNoRows is returned when one of them
+// is not on the board.
+func (p *PostgresStore) ReorderBoardColumns(boardId uuid.UUID, columnIds []uuid.UUID, modifiedAt time.Time) error {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockBoard(tx, boardId); err != nil {
+		log.Println("Error in locking the board", err)
+		return err
+	}
+
+	for position, columnId := range columnIds {
+		result, err := tx.Exec(
+			"UPDATE board_columns SET position = $1, modified_at = $2 WHERE id = $3 AND board_id = $4",
+			position, modifiedAt, columnId, boardId,
+		)
+		if err != nil {
+			log.Println("Error in reordering the board columns", err)
+			return err
+		}
+
+		count, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if count == 0 {
+			return sql.ErrNoRows
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (p *PostgresStore) CountColumnFeedbacks(boardId uuid.UUID, key models.ColumnType) (int, error) {
+	var count int
+	err := p.DB.QueryRow("SELECT COUNT(*) FROM feedbacks WHERE board_id = $1 AND board_column = $2", boardId, key).Scan(&count)
+	return count, err
+}
+
+// RemoveBoardColumn deletes the column and moves its feedback to the column
+// with the key moveTo, an empty moveTo deletes the feedback with it. Returns
+// the ids of that feedback, or sql.ErrNoRows when the column is not on the
+// board.
+func (p *PostgresStore) RemoveBoardColumn(boardId, columnId uuid.UUID, moveTo models.ColumnType) ([]uuid.UUID, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockBoard(tx, boardId); err != nil {
+		log.Println("Error in locking the board", err)
+		return nil, err
+	}
+
+	column, err := scanBoardColumn(tx.QueryRow("SELECT "+BOARD_COLUMN_COLUMNS+" FROM board_columns WHERE id = $1 AND board_id = $2 FOR UPDATE", columnId, boardId))
+	if err != nil {
+		return nil, err
+	}
+
+	var rows *sql.Rows
+	if moveTo != "" {
+		rows, err = tx.Query(
+			"UPDATE feedbacks SET board_column = $1 WHERE board_id = $2 AND board_column = $3 RETURNING id",
+			moveTo, boardId, column.Key,
+		)
+	} else {
+		rows, err = tx.Query("DELETE FROM feedbacks WHERE board_id = $1 AND board_column = $2 RETURNING id", boardId, column.Key)
+	}
+	if err != nil {
+		log.Println("Error in handling the feedback of the removed board column", err)
+		return nil, err
+	}
+
+	feedbackIds, err := scanFeedbackIds(rows)
+	if err != nil {
+		log.Println("Error in handling the feedback of the removed board column", err)
+		return nil, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM board_columns WHERE id = $1", columnId); err != nil {
+		log.Println("Error in removing the board column", err)
+		return nil, err
+	}
+
+	if err := renumberBoardColumns(tx, boardId); err != nil {
+		log.Println("Error in renumbering the board columns", err)
+		return nil, err
+	}
+
+	return feedbackIds, tx.Commit()
+}
@@ -1,26 +1,35 @@
 package storages
 
 import (
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/Aakash-Pandit/reetro-golang/models"
 	"github.com/google/uuid"
-	"github.com/lib/pq"
 )
 
-func ConvertToPQArray(columnTypes []models.ColumnType) string {
-	var words []string
-	for _, v := range columnTypes {
-		words = append(words, fmt.Sprintf("'%s'", v))
+const BOARD_COLUMNS = "id, name, template, settings, phase, previous_board_id, team_id, created_by_id, modified_by_id, closed_at, created_at, modified_at"
+
+func scanBoard(row rowScanner) (*models.Board, error) {
+	board := new(models.Board)
+
+	var settings []byte
+	err := row.Scan(&board.Id, &board.Name, &board.Template, &settings, &board.Phase, &board.PreviousBoardId, &board.TeamId, &board.CreatedById, &board.ModifiedById, &board.ClosedAt, &board.CreatedAt, &board.ModifiedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(settings, &board.Settings); err != nil {
+		return nil, err
 	}
 
-	return fmt.Sprintf("{%s}", strings.Join(words, ","))
+	return board, nil
 }
 
-func (p *PostgresStore) GetAllBoards(limit, offset int) ([]*models.Board, error) {
-	rows, err := p.DB.Query("SELECT * FROM boards LIMIT $1 OFFSET $2", limit, offset)
+func (p *PostgresStore) queryBoards(query string, args ...any) ([]*models.Board, error) {
+	rows, err := p.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -28,78 +37,129 @@ func (p *PostgresStore) GetAllBoards(limit, offset int) ([]*models.Board, error)
 
 	boards := make([]*models.Board, 0)
 	for rows.Next() {
-		board := new(models.Board)
-
-		var columnsString []string
-		err := rows.Scan(&board.Id, &board.Name, &board.Template, pq.Array(&columnsString), &board.CreatedById, &board.ModifiedById, &board.CreatedAt, &board.ModifiedAt)
+		board, err := scanBoard(rows)
 		if err != nil {
 			fmt.Printf("Error in scanning the board %v\n", err)
 			return nil, err
 		}
 
-		stringSlice := make([]string, len(columnsString))
-		for i, v := range columnsString {
-			stringSlice[i] = string(v)
-		}
-
-		for _, v := range stringSlice {
-			board.Columns = append(board.Columns, models.ColumnType(strings.ReplaceAll(v, "'", "")))
+		if err := p.loadBoardDetails(board); err != nil {
+			return nil, err
 		}
 
-		board.CreatedBy, _ = p.GetUserById(board.CreatedById)
-		board.ModifiedBy, _ = p.GetUserById(board.ModifiedById)
-
 		boards = append(boards, board)
 	}
 
 	return boards, nil
 }
 
-func (p *PostgresStore) GetBoardById(id uuid.UUID) (*models.Board, error) {
-	board := new(models.Board)
+// loadBoardDetails fills in the columns and the authors of the board.
+func (p *PostgresStore) loadBoardDetails(board *models.Board) error {
+	columns, err := p.GetBoardColumns(board.Id)
+	if err != nil {
+		log.Println("Error in fetching the board columns", err)
+		return err
+	}
+
+	board.Columns = columns
+	board.CreatedBy = p.authorById(board.CreatedById)
+	board.ModifiedBy = p.authorById(board.ModifiedById)
+
+	return nil
+}
+
+func (p *PostgresStore) GetAllBoards(limit, offset int) ([]*models.Board, error) {
+	return p.queryBoards("SELECT "+BOARD_COLUMNS+" FROM boards LIMIT $1 OFFSET $2", limit, offset)
+}
+
+// GetBoardsForUser returns the boards of the teams the user is a member of
+// and the boards they were invited to.
+func (p *PostgresStore) GetBoardsForUser(userId uuid.UUID, limit, offset int) ([]*models.Board, error) {
+	return p.queryBoards(
+		"SELECT "+BOARD_COLUMNS+" FROM boards WHERE team_id IN (SELECT team_id FROM team_members WHERE user_id = $1) OR id IN (SELECT board_id FROM board_members WHERE user_id = $1) ORDER BY created_at DESC LIMIT $2 OFFSET $3",
+		userId, limit, offset,
+	)
+}
 
-	var columnsString []string
-	err := p.DB.QueryRow("SELECT * FROM boards WHERE id = $1", id).Scan(&board.Id, &board.Name, &board.Template, pq.Array(&columnsString), &board.CreatedById, &board.ModifiedById, &board.CreatedAt, &board.ModifiedAt)
+func (p *PostgresStore) GetBoardById(id uuid.UUID) (*models.Board, error) {
+	board, err := scanBoard(p.DB.QueryRow("SELECT "+BOARD_COLUMNS+" FROM boards WHERE id = $1", id))
 	if err != nil {
 		return nil, err
 	}
 
-	stringSlice := make([]string, len(columnsString))
-	for i, v := range columnsString {
-		stringSlice[i] = string(v)
+	if err := p.loadBoardDetails(board); err != nil {
+		return nil, err
 	}
 
-	for _, v := range stringSlice {
-		board.Columns = append(board.Columns, models.ColumnType(strings.ReplaceAll(v, "'", "")))
+	return board, nil
+}
+
+// CreateBoard stores the board with its columns and makes its creator the
+// facilitator.
+func (p *PostgresStore) CreateBoard(board models.Board) (models.Board, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return models.Board{}, err
 	}
+	defer tx.Rollback()
 
-	board.CreatedBy, _ = p.GetUserById(board.CreatedById)
-	board.ModifiedBy, _ = p.GetUserById(board.ModifiedById)
+	if err := insertBoard(tx, board); err != nil {
+		return models.Board{}, err
+	}
 
-	return board, nil
+	return board, tx.Commit()
 }
 
-func (p *PostgresStore) CreateBoard(board models.Board) (models.Board, error) {
-	arrayInStringFormat := ConvertToPQArray(board.Columns)
+// lockBoard holds the board row until the transaction ends, so changes to
+// what belongs to the board are made one at a time.
+func lockBoard(tx *sql.Tx, id uuid.UUID) error {
+	_, err := tx.Exec("SELECT id FROM boards WHERE id = $1 FOR UPDATE", id)
+	return err
+}
+
+func insertBoard(tx *sql.Tx, board models.Board) error {
+	settings, err := json.Marshal(board.Settings)
+	if err != nil {
+		return err
+	}
 
-	_, err := p.DB.Exec(
-		"INSERT INTO boards (id, name, template, columns, created_by_id, modified_by_id, created_at, modified_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
-		board.Id, board.Name, board.Template, arrayInStringFormat, board.CreatedById, board.ModifiedById, board.CreatedAt, board.ModifiedAt,
+	_, err = tx.Exec(
+		"INSERT INTO boards ("+BOARD_COLUMNS+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)",
+		board.Id, board.Name, board.Template, string(settings), board.Phase, board.PreviousBoardId, board.TeamId, board.CreatedById, board.ModifiedById, board.ClosedAt, board.CreatedAt, board.ModifiedAt,
 	)
 	if err != nil {
-		log.Println("Error in creating the user", err)
-		return models.Board{}, err
+		log.Println("Error in creating the board", err)
+		return err
 	}
 
-	return board, nil
+	for _, column := range board.Columns {
+		if err := insertBoardColumn(tx, column); err != nil {
+			log.Println("Error in creating the board column", err)
+			return err
+		}
+	}
+
+	facilitator := models.NewBoardMember(board.Id, board.CreatedById, models.BoardFacilitator)
+	_, err = tx.Exec(
+		"INSERT INTO board_members ("+BOARD_MEMBER_COLUMNS+") VALUES ($1, $2, $3, $4)",
+		facilitator.BoardId, facilitator.UserId, facilitator.Role, facilitator.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the board facilitator", err)
+	}
+
+	return err
 }
 
 func (p *PostgresStore) UpdateBoard(board models.Board) (models.Board, error) {
-	arrayInStringFormat := ConvertToPQArray(board.Columns)
+	settings, err := json.Marshal(board.Settings)
+	if err != nil {
+		return models.Board{}, err
+	}
 
-	_, err := p.DB.Exec(
-		"UPDATE boards SET name = $1, template = $2, columns = $3, modified_by_id = $4, modified_at = $5 WHERE id = $6",
-		board.Name, board.Template, arrayInStringFormat, board.ModifiedById, board.ModifiedAt, board.Id,
+	_, err = p.DB.Exec(
+		"UPDATE boards SET name = $1, template = $2, settings = $3, modified_by_id = $4, modified_at = $5 WHERE id = $6",
+		board.Name, board.Template, string(settings), board.ModifiedById, board.ModifiedAt, board.Id,
 	)
 	if err != nil {
 		log.Println("Error in updating the board", err)
@@ -110,10 +170,7 @@ func (p *PostgresStore) UpdateBoard(board models.Board) (models.Board, error) {
 }
 
 func (p *PostgresStore) DeleteBoard(id uuid.UUID) error {
-	board := new(models.Board)
-
-	var columnsString []string
-	err := p.DB.QueryRow("SELECT * FROM boards WHERE id = $1", id).Scan(&board.Id, &board.Name, &board.Template, pq.Array(&columnsString), &board.CreatedById, &board.ModifiedById, &board.CreatedAt, &board.ModifiedAt)
+	_, err := scanBoard(p.DB.QueryRow("SELECT "+BOARD_COLUMNS+" FROM boards WHERE id = $1", id))
 	if err != nil {
 		log.Println("Error while fetching the board", err)
 		return err
@@ -0,0 +1,144 @@
+package storages
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+const (
+	BOARD_MEMBER_COLUMNS     = "board_id, user_id, role, created_at"
+	BOARD_INVITATION_COLUMNS = "id, board_id, email, role, token_hash, invited_by_id, expires_at, accepted_at, created_at"
+)
+
+func scanBoardMember(row rowScanner) (*models.BoardMember, error) {
+	member := new(models.BoardMember)
+	err := row.Scan(&member.BoardId, &member.UserId, &member.Role, &member.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+func scanBoardInvitation(row rowScanner) (*models.BoardInvitation, error) {
+	invitation := new(models.BoardInvitation)
+
+	var invitedById sql.NullString
+	err := row.Scan(&invitation.Id, &invitation.BoardId, &invitation.Email, &invitation.Role, &invitation.TokenHash, &invitedById, &invitation.ExpiresAt, &invitation.AcceptedAt, &invitation.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if invitedById.Valid {
+		invitation.InvitedById, _ = uuid.Parse(invitedById.String)
+	}
+
+	return invitation, nil
+}
+
+func (p *PostgresStore) GetBoardMembers(boardId uuid.UUID) ([]*models.BoardMember, error) {
+	rows, err := p.DB.Query("SELECT "+BOARD_MEMBER_COLUMNS+" FROM board_members WHERE board_id = $1 ORDER BY created_at", boardId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]*models.BoardMember, 0)
+	for rows.Next() {
+		member, err := scanBoardMember(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		member.User, _ = p.GetUserById(member.UserId)
+
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// GetBoardMember returns sql.ErrNoRows when the user is not a member of the
+// board.
+func (p *PostgresStore) GetBoardMember(boardId, userId uuid.UUID) (*models.BoardMember, error) {
+	return scanBoardMember(p.DB.QueryRow("SELECT "+BOARD_MEMBER_COLUMNS+" FROM board_members WHERE board_id = $1 AND user_id = $2", boardId, userId))
+}
+
+// RemoveBoardMember returns sql.ErrNoRows when the user is not a member of
+// the board.
+func (p *PostgresStore) RemoveBoardMember(boardId, userId uuid.UUID) error {
+	result, err := p.DB.Exec("DELETE FROM board_members WHERE board_id = $1 AND user_id = $2", boardId, userId)
+	if err != nil {
+		log.Println("Error in removing the board member", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) CreateBoardInvitation(invitation models.BoardInvitation) error {
+	_, err := p.DB.Exec(
+		"INSERT INTO board_invitations ("+BOARD_INVITATION_COLUMNS+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		invitation.Id, invitation.BoardId, invitation.Email, invitation.Role, invitation.TokenHash, invitation.InvitedById, invitation.ExpiresAt, invitation.AcceptedAt, invitation.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the board invitation", err)
+		return err
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) GetBoardInvitationByHash(tokenHash string) (*models.BoardInvitation, error) {
+	return scanBoardInvitation(p.DB.QueryRow("SELECT "+BOARD_INVITATION_COLUMNS+" FROM board_invitations WHERE token_hash = $1", tokenHash))
+}
+
+// AcceptBoardInvitation marks the invitation as accepted and adds the member
+// in one go, it returns sql.ErrNoRows when the invitation was already used.
+// Accepting never lowers the role of someone already on the board.
+func (p *PostgresStore) AcceptBoardInvitation(invitationId uuid.UUID, member models.BoardMember) error {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE board_invitations SET accepted_at = $1 WHERE id = $2 AND accepted_at IS NULL", time.Now().UTC(), invitationId)
+	if err != nil {
+		log.Println("Error in accepting the board invitation", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO board_members ("+BOARD_MEMBER_COLUMNS+") VALUES ($1, $2, $3, $4) ON CONFLICT (board_id, user_id) DO NOTHING",
+		member.BoardId, member.UserId, member.Role, member.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the board member", err)
+		return err
+	}
+
+	return tx.Commit()
+}
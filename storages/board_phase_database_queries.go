@@ -0,0 +1,126 @@
+package storages
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+const FEEDBACK_VOTE_COLUMNS = "id, feedback_id, board_id, user_id, created_at"
+
+// UpdateBoardPhase moves the board from one phase to another. It returns
+// sql.ErrNoRows when the board is no longer in the from phase, so two
+// facilitators cannot skip a phase by both moving on at once. Closing goes
+// through CloseBoard.
+func (p *PostgresStore) UpdateBoardPhase(id uuid.UUID, from, to models.BoardPhase, modifiedAt time.Time) error {
+	result, err := p.DB.Exec(
+		"UPDATE boards SET phase = $1, modified_at = $2 WHERE id = $3 AND phase = $4 AND closed_at IS NULL",
+		to, modifiedAt, id, from,
+	)
+	if err != nil {
+		log.Println("Error in updating the board phase", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// CreateFeedbackVote stores the vote when the user has given fewer than
+// limit votes on the board, no limit when it is 0. The board row is locked
+// while counting, so concurrent votes of the user cannot go over the limit.
+// It returns sql.ErrNoRows when the user has no votes left.
+func (p *PostgresStore) CreateFeedbackVote(vote models.FeedbackVote, limit int) error {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockBoard(tx, vote.BoardId); err != nil {
+		log.Println("Error in locking the board", err)
+		return err
+	}
+
+	result, err := tx.Exec(
+		"INSERT INTO feedback_votes ("+FEEDBACK_VOTE_COLUMNS+") SELECT $1, $2, $3, $4, $5::timestamp "+
+			"WHERE $6 = 0 OR (SELECT COUNT(*) FROM feedback_votes WHERE board_id = $3 AND user_id = $4) < $6",
+		vote.Id, vote.FeedbackId, vote.BoardId, vote.UserId, vote.CreatedAt, limit,
+	)
+	if err != nil {
+		log.Println("Error in creating the feedback vote", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// DeleteFeedbackVote takes back the latest vote of the user on the feedback,
+// it returns sql.ErrNoRows when they have not voted on it.
+func (p *PostgresStore) DeleteFeedbackVote(feedbackId, userId uuid.UUID) error {
+	result, err := p.DB.Exec(
+		"DELETE FROM feedback_votes WHERE id = (SELECT id FROM feedback_votes WHERE feedback_id = $1 AND user_id = $2 ORDER BY created_at DESC LIMIT 1)",
+		feedbackId, userId,
+	)
+	if err != nil {
+		log.Println("Error in deleting the feedback vote", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GroupFeedback puts the feedback, and the feedback stacked onto it, into
+// the group. A nil group takes the feedback out of its group. Returns the ids
+// of every feedback it moved.
+func (p *PostgresStore) GroupFeedback(id uuid.UUID, groupId *uuid.UUID, modifiedAt time.Time) ([]uuid.UUID, error) {
+	rows, err := p.DB.Query(
+		"UPDATE feedbacks SET group_id = $1, modified_at = $2 WHERE id = $3 OR group_id = $3 RETURNING id",
+		groupId, modifiedAt, id,
+	)
+	if err != nil {
+		log.Println("Error in grouping the feedback", err)
+		return nil, err
+	}
+
+	feedbackIds, err := scanFeedbackIds(rows)
+	if err != nil {
+		log.Println("Error in grouping the feedback", err)
+		return nil, err
+	}
+
+	if len(feedbackIds) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return feedbackIds, nil
+}
@@ -0,0 +1,93 @@
+package storages
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+// CloneBoard stores the board that follows its previous board in the series
+// and copies the members of the previous board into it, guests of the
+// previous board are left behind. When carryOverFrom is set the unresolved
+// feedback in that column is copied as well, pointing to the feedback it was
+// first written as. Feedback is left behind when a copy of it has been
+// resolved, or when it was already carried over to a later board, so cloning
+// a board twice does not open the same action item twice. The previous
+// board is locked so two clones of it are made one at a time.
+func (p *PostgresStore) CloneBoard(board models.Board, carryOverFrom models.ColumnType) error {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockBoard(tx, *board.PreviousBoardId); err != nil {
+		log.Println("Error in locking the previous board", err)
+		return err
+	}
+
+	if err := insertBoard(tx, board); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO board_members ("+BOARD_MEMBER_COLUMNS+") SELECT $1, bm.user_id, bm.role, $2::timestamp FROM board_members bm JOIN users u ON u.id = bm.user_id WHERE bm.board_id = $3 AND u.guest_board_id IS NULL ON CONFLICT DO NOTHING",
+		board.Id, board.CreatedAt, board.PreviousBoardId,
+	)
+	if err != nil {
+		log.Println("Error in copying the board members", err)
+		return err
+	}
+
+	if carryOverFrom != "" {
+		_, err = tx.Exec(
+			"INSERT INTO feedbacks ("+FEEDBACK_COLUMNS+") SELECT gen_random_uuid()::text, f.message, $1, f.board_column, NULL, NULL, COALESCE(f.origin_id, f.id), f.created_by_id, $2::timestamp, $2::timestamp "+
+				"FROM feedbacks f WHERE f.board_id = $3 AND f.board_column = $4 AND f.resolved_at IS NULL "+
+				"AND NOT EXISTS (SELECT 1 FROM feedbacks carried WHERE (carried.id = COALESCE(f.origin_id, f.id) OR carried.origin_id = COALESCE(f.origin_id, f.id)) "+
+				"AND (carried.resolved_at IS NOT NULL OR (carried.board_id <> f.board_id AND carried.created_at > f.created_at))) "+
+				"ORDER BY f.created_at",
+			board.Id, board.CreatedAt, board.PreviousBoardId, carryOverFrom,
+		)
+		if err != nil {
+			log.Println("Error in carrying over the action items", err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetBoardSeries returns every board the board was cloned from or that was
+// cloned from it, the board itself included, oldest first.
+func (p *PostgresStore) GetBoardSeries(id uuid.UUID) ([]*models.Board, error) {
+	return p.queryBoards(
+		"WITH RECURSIVE earlier AS (SELECT id, previous_board_id FROM boards WHERE id = $1 UNION SELECT b.id, b.previous_board_id FROM boards b JOIN earlier e ON b.id = e.previous_board_id), "+
+			"later AS (SELECT id FROM boards WHERE id = $1 UNION SELECT b.id FROM boards b JOIN later l ON b.previous_board_id = l.id) "+
+			"SELECT "+BOARD_COLUMNS+" FROM boards WHERE id IN (SELECT id FROM earlier UNION SELECT id FROM later) ORDER BY created_at",
+		id,
+	)
+}
+
+// ResolveFeedback marks the feedback as resolved at resolvedAt, nil opens it
+// again. It returns sql.ErrNoRows when the feedback does not exist.
+func (p *PostgresStore) ResolveFeedback(id uuid.UUID, resolvedAt *time.Time) error {
+	result, err := p.DB.Exec("UPDATE feedbacks SET resolved_at = $1 WHERE id = $2", resolvedAt, id)
+	if err != nil {
+		log.Println("Error in resolving the feedback", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package storages
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+const BOARD_TEMPLATE_COLUMNS = "id, team_id, key, name, description, columns, settings, created_by_id, created_at, modified_at"
+
+func scanBoardTemplate(row rowScanner) (*models.BoardTemplate, error) {
+	template := new(models.BoardTemplate)
+
+	var teamId uuid.UUID
+	var columns, settings []byte
+	err := row.Scan(&template.Id, &teamId, &template.Key, &template.Name, &template.Description, &columns, &settings, &template.CreatedById, &template.CreatedAt, &template.ModifiedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	template.TeamId = &teamId
+
+	if err := json.Unmarshal(columns, &template.Columns); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(settings, &template.Settings); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// GetBoardTemplates returns the custom templates of the team, the built-in
+// ones are not stored.
+func (p *PostgresStore) GetBoardTemplates(teamId uuid.UUID) ([]*models.BoardTemplate, error) {
+	rows, err := p.DB.Query("SELECT "+BOARD_TEMPLATE_COLUMNS+" FROM board_templates WHERE team_id = $1 ORDER BY name", teamId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := make([]*models.BoardTemplate, 0)
+	for rows.Next() {
+		template, err := scanBoardTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+// GetBoardTemplate returns sql.ErrNoRows when the team has no template with
+// the key.
+func (p *PostgresStore) GetBoardTemplate(teamId uuid.UUID, key models.TemplateType) (*models.BoardTemplate, error) {
+	return scanBoardTemplate(p.DB.QueryRow("SELECT "+BOARD_TEMPLATE_COLUMNS+" FROM board_templates WHERE team_id = $1 AND key = $2", teamId, key))
+}
+
+func (p *PostgresStore) CreateBoardTemplate(template models.BoardTemplate) (models.BoardTemplate, error) {
+	columns, err := json.Marshal(template.Columns)
+	if err != nil {
+		return models.BoardTemplate{}, err
+	}
+
+	settings, err := json.Marshal(template.Settings)
+	if err != nil {
+		return models.BoardTemplate{}, err
+	}
+
+	_, err = p.DB.Exec(
+		"INSERT INTO board_templates ("+BOARD_TEMPLATE_COLUMNS+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		template.Id, template.TeamId, template.Key, template.Name, template.Description, string(columns), string(settings), template.CreatedById, template.CreatedAt, template.ModifiedAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the board template", err)
+		return models.BoardTemplate{}, err
+	}
+
+	return template, nil
+}
+
+// UpdateBoardTemplate returns sql.ErrNoRows when the template does not exist,
+// boards created from it keep the columns they were created with.
+func (p *PostgresStore) UpdateBoardTemplate(template models.BoardTemplate) (models.BoardTemplate, error) {
+	columns, err := json.Marshal(template.Columns)
+	if err != nil {
+		return models.BoardTemplate{}, err
+	}
+
+	settings, err := json.Marshal(template.Settings)
+	if err != nil {
+		return models.BoardTemplate{}, err
+	}
+
+	result, err := p.DB.Exec(
+		"UPDATE board_templates SET name = $1, description = $2, columns = $3, settings = $4, modified_at = $5 WHERE id = $6",
+		template.Name, template.Description, string(columns), string(settings), template.ModifiedAt, template.Id,
+	)
+	if err != nil {
+		log.Println("Error in updating the board template", err)
+		return models.BoardTemplate{}, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return models.BoardTemplate{}, err
+	}
+
+	if count == 0 {
+		return models.BoardTemplate{}, sql.ErrNoRows
+	}
+
+	return template, nil
+}
+
+// DeleteBoardTemplate returns sql.ErrNoRows when the team has no template
+// with the key.
+func (p *PostgresStore) DeleteBoardTemplate(teamId uuid.UUID, key models.TemplateType) error {
+	result, err := p.DB.Exec("DELETE FROM board_templates WHERE team_id = $1 AND key = $2", teamId, key)
+	if err != nil {
+		log.Println("Error in deleting the board template", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
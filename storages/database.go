@@ -1,6 +1,8 @@
 package storages
 
 import (
+	"time"
+
 	"github.com/Aakash-Pandit/reetro-golang/models"
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
@@ -10,23 +12,112 @@ type Storage interface {
 	GetAllUsers(int, int) ([]*models.CreateUserResponse, error)
 	GetUserById(uuid.UUID) (*models.CreateUserResponse, error)
 	CreateUser(models.User) (models.User, error)
+	UpdateUser(models.CreateUserResponse) (models.CreateUserResponse, error)
 	DeleteUser(uuid.UUID) error
+	GetUserDataExport(uuid.UUID) (*models.UserDataExport, error)
 	VerifyUserByUsername(string) (*models.User, error)
 	VerifyUserByEmail(string) (*models.User, error)
 	SavePassword(models.User) error
+	VerifyUserEmail(uuid.UUID) error
 	VerifyUserByUsernamePassword(string, string) (*models.User, error)
 
+	GetUserByIdentity(string, string) (*models.User, error)
+	CreateUserIdentity(models.UserIdentity) error
+
+	CreatePasswordResetToken(models.PasswordResetToken) error
+	GetPasswordResetTokenByHash(string) (*models.PasswordResetToken, error)
+	UsePasswordResetToken(string) (uuid.UUID, error)
+	MarkPasswordResetTokensUsed(uuid.UUID) error
+
+	CreateAccountLockout(models.AccountLockout) error
+	UnlockAccountLockouts(string, uuid.UUID) error
+
+	CreatePersonalAccessToken(models.PersonalAccessToken) error
+	GetPersonalAccessTokensByUserId(uuid.UUID) ([]*models.PersonalAccessToken, error)
+	GetPersonalAccessTokenByHash(string) (*models.PersonalAccessToken, error)
+	RevokePersonalAccessToken(uuid.UUID, uuid.UUID) error
+	TouchPersonalAccessToken(uuid.UUID, time.Time) error
+
+	GetUserMFA(uuid.UUID) (*models.UserMFA, error)
+	SaveUserMFA(models.UserMFA) error
+	DeleteUserMFA(uuid.UUID) error
+	ReplaceMFARecoveryCodes(uuid.UUID, []models.MFARecoveryCode) error
+	UseMFAStep(uuid.UUID, int64) error
+	UseMFARecoveryCode(uuid.UUID, string) error
+
+	CreateUserSession(models.UserSession) error
+	GetUserSession(uuid.UUID) (*models.UserSession, error)
+	GetActiveUserSessions(uuid.UUID) ([]*models.UserSession, error)
+	TouchUserSession(uuid.UUID, time.Time, time.Time) error
+	RevokeUserSession(uuid.UUID, uuid.UUID) error
+	RevokeUserSessions(uuid.UUID, uuid.UUID) error
+
+	GetAllTeams(int, int) ([]*models.Team, error)
+	GetTeamsForUser(uuid.UUID, int, int) ([]*models.Team, error)
+	GetTeamById(uuid.UUID) (*models.Team, error)
+	CreateTeam(models.Team, models.TeamMembership) error
+	UpdateTeam(models.Team) (models.Team, error)
+	DeleteTeam(uuid.UUID) error
+	GetTeamMembers(uuid.UUID) ([]*models.TeamMembership, error)
+	GetTeamMembership(uuid.UUID, uuid.UUID) (*models.TeamMembership, error)
+	SaveTeamMember(models.TeamMembership) error
+	RemoveTeamMember(uuid.UUID, uuid.UUID) error
+	CountTeamOwners(uuid.UUID) (int, error)
+	GetTeamsOwnedOnlyBy(uuid.UUID) ([]*models.Team, error)
+
 	GetAllBoards(int, int) ([]*models.Board, error)
+	GetBoardsForUser(uuid.UUID, int, int) ([]*models.Board, error)
 	GetBoardById(uuid.UUID) (*models.Board, error)
 	CreateBoard(models.Board) (models.Board, error)
 	UpdateBoard(models.Board) (models.Board, error)
 	DeleteBoard(uuid.UUID) error
+	CloneBoard(models.Board, models.ColumnType) error
+	GetBoardSeries(uuid.UUID) ([]*models.Board, error)
+
+	GetBoardColumns(uuid.UUID) ([]*models.BoardColumn, error)
+	CreateBoardColumn(models.BoardColumn) (models.BoardColumn, error)
+	UpdateBoardColumn(models.BoardColumn) (models.BoardColumn, error)
+	ReorderBoardColumns(uuid.UUID, []uuid.UUID, time.Time) error
+	CountColumnFeedbacks(uuid.UUID, models.ColumnType) (int, error)
+	RemoveBoardColumn(uuid.UUID, uuid.UUID, models.ColumnType) ([]uuid.UUID, error)
+
+	GetBoardTemplates(uuid.UUID) ([]*models.BoardTemplate, error)
+	GetBoardTemplate(uuid.UUID, models.TemplateType) (*models.BoardTemplate, error)
+	CreateBoardTemplate(models.BoardTemplate) (models.BoardTemplate, error)
+	UpdateBoardTemplate(models.BoardTemplate) (models.BoardTemplate, error)
+	DeleteBoardTemplate(uuid.UUID, models.TemplateType) error
+
+	GetBoardMembers(uuid.UUID) ([]*models.BoardMember, error)
+	GetBoardMember(uuid.UUID, uuid.UUID) (*models.BoardMember, error)
+	RemoveBoardMember(uuid.UUID, uuid.UUID) error
+	CreateBoardInvitation(models.BoardInvitation) error
+	GetBoardInvitationByHash(string) (*models.BoardInvitation, error)
+	AcceptBoardInvitation(uuid.UUID, models.BoardMember) error
+
+	CreateBoardJoinLink(models.BoardJoinLink) error
+	GetBoardJoinLinkByHash(string) (*models.BoardJoinLink, error)
+	GetBoardJoinLinkByPin(string) (*models.BoardJoinLink, error)
+	RevokeBoardJoinLink(uuid.UUID, uuid.UUID) error
+	CreateGuestUser(models.User, uuid.UUID, time.Time) error
+	DeleteExpiredGuestUsers(time.Time) (int64, error)
+	CloseBoard(uuid.UUID, time.Time) error
+	UpdateBoardPhase(uuid.UUID, models.BoardPhase, models.BoardPhase, time.Time) error
 
 	GetAllFeedbacks(int, int) ([]*models.Feedback, error)
+	GetFeedbacksForUser(uuid.UUID, int, int) ([]*models.Feedback, error)
 	GetFeedbackById(uuid.UUID) (*models.Feedback, error)
 	CreateFeedback(models.Feedback) (models.Feedback, error)
 	UpdateFeedback(models.Feedback) (models.Feedback, error)
 	DeleteFeedback(uuid.UUID) error
+	RemoveFeedback(models.FeedbackRemoval) error
+	GetFeedbackRemovals(uuid.UUID) ([]*models.FeedbackRemoval, error)
+	GroupFeedback(uuid.UUID, *uuid.UUID, time.Time) ([]uuid.UUID, error)
+	ResolveFeedback(uuid.UUID, *time.Time) error
+	CreateFeedbackVote(models.FeedbackVote, int) error
+	DeleteFeedbackVote(uuid.UUID, uuid.UUID) error
+
+	CreateAuditEvent(models.AuditEvent) error
+	GetAuditEvents(models.AuditEventFilter, int, int) ([]*models.AuditEvent, error)
 }
 
 type Database struct {
@@ -1,6 +1,7 @@
 package storages
 
 import (
+	"database/sql"
 	"fmt"
 	"log"
 
@@ -8,9 +9,24 @@ import (
 	"github.com/google/uuid"
 )
 
-func (p *PostgresStore) GetAllFeedbacks(limit, offset int) ([]*models.Feedback, error) {
-	rows, err := p.DB.Query("SELECT * FROM feedbacks LIMIT $1 OFFSET $2", limit, offset)
+const FEEDBACK_COLUMNS = "id, message, board_id, board_column, group_id, resolved_at, origin_id, created_by_id, created_at, modified_at"
+
+// FEEDBACK_VOTE_COUNT is selected after FEEDBACK_COLUMNS, the votes are not
+// a column of feedbacks.
+const FEEDBACK_VOTE_COUNT = "(SELECT COUNT(*) FROM feedback_votes WHERE feedback_votes.feedback_id = feedbacks.id)"
 
+func scanFeedback(row rowScanner) (*models.Feedback, error) {
+	feedback := new(models.Feedback)
+	err := row.Scan(&feedback.Id, &feedback.Message, &feedback.BoardId, &feedback.Column, &feedback.GroupId, &feedback.ResolvedAt, &feedback.OriginId, &feedback.CreatedById, &feedback.CreatedAt, &feedback.ModifiedAt, &feedback.Votes)
+	if err != nil {
+		return nil, err
+	}
+
+	return feedback, nil
+}
+
+func (p *PostgresStore) queryFeedbacks(query string, args ...any) ([]*models.Feedback, error) {
+	rows, err := p.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -18,14 +34,13 @@ func (p *PostgresStore) GetAllFeedbacks(limit, offset int) ([]*models.Feedback,
 
 	feedbacks := make([]*models.Feedback, 0)
 	for rows.Next() {
-		feedback := new(models.Feedback)
-		err := rows.Scan(&feedback.Id, &feedback.Message, &feedback.BoardId, &feedback.CreatedById, &feedback.CreatedAt, &feedback.ModifiedAt)
+		feedback, err := scanFeedback(rows)
 		if err != nil {
 			fmt.Printf("Error in scanning the feedback %v\n", err)
 			return nil, err
 		}
 
-		feedback.CreatedBy, _ = p.GetUserById(feedback.CreatedById)
+		feedback.CreatedBy = p.authorById(feedback.CreatedById)
 
 		feedbacks = append(feedbacks, feedback)
 	}
@@ -33,23 +48,51 @@ func (p *PostgresStore) GetAllFeedbacks(limit, offset int) ([]*models.Feedback,
 	return feedbacks, nil
 }
 
-func (p *PostgresStore) GetFeedbackById(id uuid.UUID) (*models.Feedback, error) {
-	feedback := new(models.Feedback)
+// scanFeedbackIds reads the ids a RETURNING id statement on feedbacks gives
+// back, so the cache entries of the rows it touched can be dropped.
+func scanFeedbackIds(rows *sql.Rows) ([]uuid.UUID, error) {
+	defer rows.Close()
 
-	err := p.DB.QueryRow("SELECT * FROM feedbacks WHERE id = $1", id).Scan(&feedback.Id, &feedback.Message, &feedback.BoardId, &feedback.CreatedById, &feedback.CreatedAt, &feedback.ModifiedAt)
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (p *PostgresStore) GetAllFeedbacks(limit, offset int) ([]*models.Feedback, error) {
+	return p.queryFeedbacks("SELECT "+FEEDBACK_COLUMNS+", "+FEEDBACK_VOTE_COUNT+" FROM feedbacks LIMIT $1 OFFSET $2", limit, offset)
+}
+
+// GetFeedbacksForUser returns the feedbacks on boards of the teams the user
+// is a member of and on the boards they were invited to.
+func (p *PostgresStore) GetFeedbacksForUser(userId uuid.UUID, limit, offset int) ([]*models.Feedback, error) {
+	return p.queryFeedbacks(
+		"SELECT "+FEEDBACK_COLUMNS+", "+FEEDBACK_VOTE_COUNT+" FROM feedbacks WHERE board_id IN (SELECT b.id FROM boards b JOIN team_members tm ON tm.team_id = b.team_id WHERE tm.user_id = $1) OR board_id IN (SELECT board_id FROM board_members WHERE user_id = $1) ORDER BY created_at DESC LIMIT $2 OFFSET $3",
+		userId, limit, offset,
+	)
+}
+
+func (p *PostgresStore) GetFeedbackById(id uuid.UUID) (*models.Feedback, error) {
+	feedback, err := scanFeedback(p.DB.QueryRow("SELECT "+FEEDBACK_COLUMNS+", "+FEEDBACK_VOTE_COUNT+" FROM feedbacks WHERE id = $1", id))
 	if err != nil {
 		return nil, err
 	}
 
 	feedback.Board, _ = p.GetBoardById(feedback.BoardId)
-	feedback.CreatedBy, _ = p.GetUserById(feedback.CreatedById)
+	feedback.CreatedBy = p.authorById(feedback.CreatedById)
 	return feedback, nil
 }
 
 func (p *PostgresStore) CreateFeedback(feedback models.Feedback) (models.Feedback, error) {
 	_, err := p.DB.Exec(
-		"INSERT INTO feedbacks (id, message, board_id, created_by_id, created_at, modified_at) VALUES ($1, $2, $3, $4, $5, $6)",
-		feedback.Id, feedback.Message, feedback.BoardId, feedback.CreatedById, feedback.CreatedAt, feedback.ModifiedAt,
+		"INSERT INTO feedbacks ("+FEEDBACK_COLUMNS+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		feedback.Id, feedback.Message, feedback.BoardId, feedback.Column, feedback.GroupId, feedback.ResolvedAt, feedback.OriginId, feedback.CreatedById, feedback.CreatedAt, feedback.ModifiedAt,
 	)
 	if err != nil {
 		log.Println("Error in creating the user", err)
@@ -61,8 +104,8 @@ func (p *PostgresStore) CreateFeedback(feedback models.Feedback) (models.Feedbac
 
 func (p *PostgresStore) UpdateFeedback(feedback models.Feedback) (models.Feedback, error) {
 	_, err := p.DB.Exec(
-		"UPDATE feedbacks SET message = $1, modified_at = $2 WHERE id = $3",
-		feedback.Message, feedback.ModifiedAt, feedback.Id,
+		"UPDATE feedbacks SET message = $1, board_column = $2, modified_at = $3 WHERE id = $4",
+		feedback.Message, feedback.Column, feedback.ModifiedAt, feedback.Id,
 	)
 	if err != nil {
 		log.Println("Error in updating the board", err)
@@ -73,9 +116,7 @@ func (p *PostgresStore) UpdateFeedback(feedback models.Feedback) (models.Feedbac
 }
 
 func (p *PostgresStore) DeleteFeedback(id uuid.UUID) error {
-	feedback := new(models.Feedback)
-
-	err := p.DB.QueryRow("SELECT * FROM feedbacks WHERE id = $1", id).Scan(&feedback.Id, &feedback.Message, &feedback.BoardId, &feedback.CreatedById, &feedback.CreatedAt, &feedback.ModifiedAt)
+	_, err := scanFeedback(p.DB.QueryRow("SELECT "+FEEDBACK_COLUMNS+", "+FEEDBACK_VOTE_COUNT+" FROM feedbacks WHERE id = $1", id))
 	if err != nil {
 		log.Println("Error while fetching the feedback", err)
 		return err
@@ -0,0 +1,98 @@
+package storages
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+const FEEDBACK_REMOVAL_COLUMNS = "id, feedback_id, board_id, message, created_by_id, removed_by_id, reason, created_at"
+
+// nullableId stores uuid.Nil as NULL, for references to users that may have
+// been deleted since.
+func nullableId(id uuid.UUID) sql.NullString {
+	if id == uuid.Nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: id.String(), Valid: true}
+}
+
+func scanFeedbackRemoval(row rowScanner) (*models.FeedbackRemoval, error) {
+	removal := new(models.FeedbackRemoval)
+
+	var createdById, removedById sql.NullString
+	err := row.Scan(&removal.Id, &removal.FeedbackId, &removal.BoardId, &removal.Message, &createdById, &removedById, &removal.Reason, &removal.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if createdById.Valid {
+		removal.CreatedById, _ = uuid.Parse(createdById.String)
+	}
+
+	if removedById.Valid {
+		removal.RemovedById, _ = uuid.Parse(removedById.String)
+	}
+
+	return removal, nil
+}
+
+func (p *PostgresStore) GetFeedbackRemovals(boardId uuid.UUID) ([]*models.FeedbackRemoval, error) {
+	rows, err := p.DB.Query("SELECT "+FEEDBACK_REMOVAL_COLUMNS+" FROM feedback_removals WHERE board_id = $1 ORDER BY created_at DESC", boardId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	removals := make([]*models.FeedbackRemoval, 0)
+	for rows.Next() {
+		removal, err := scanFeedbackRemoval(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		removal.RemovedBy = p.authorById(removal.RemovedById)
+
+		removals = append(removals, removal)
+	}
+
+	return removals, nil
+}
+
+// RemoveFeedback deletes the feedback and records the removal in the same
+// transaction. It returns sql.ErrNoRows when the feedback is already gone.
+func (p *PostgresStore) RemoveFeedback(removal models.FeedbackRemoval) error {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM feedbacks WHERE id = $1", removal.FeedbackId)
+	if err != nil {
+		log.Println("Error in removing the feedback", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO feedback_removals ("+FEEDBACK_REMOVAL_COLUMNS+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		removal.Id, removal.FeedbackId, removal.BoardId, removal.Message, nullableId(removal.CreatedById), nullableId(removal.RemovedById), removal.Reason, removal.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error in recording the feedback removal", err)
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,177 @@
+package storages
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+const BOARD_JOIN_LINK_COLUMNS = "id, board_id, token_hash, pin_hash, created_by_id, expires_at, revoked_at, created_at"
+
+func scanBoardJoinLink(row rowScanner) (*models.BoardJoinLink, error) {
+	link := new(models.BoardJoinLink)
+
+	var createdById sql.NullString
+	err := row.Scan(&link.Id, &link.BoardId, &link.TokenHash, &link.PinHash, &createdById, &link.ExpiresAt, &link.RevokedAt, &link.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if createdById.Valid {
+		link.CreatedById, _ = uuid.Parse(createdById.String)
+	}
+
+	return link, nil
+}
+
+func (p *PostgresStore) CreateBoardJoinLink(link models.BoardJoinLink) error {
+	_, err := p.DB.Exec(
+		"INSERT INTO board_join_links ("+BOARD_JOIN_LINK_COLUMNS+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		link.Id, link.BoardId, link.TokenHash, link.PinHash, link.CreatedById, link.ExpiresAt, link.RevokedAt, link.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the board join link", err)
+		return err
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) GetBoardJoinLinkByHash(tokenHash string) (*models.BoardJoinLink, error) {
+	return scanBoardJoinLink(p.DB.QueryRow("SELECT "+BOARD_JOIN_LINK_COLUMNS+" FROM board_join_links WHERE token_hash = $1", tokenHash))
+}
+
+// GetBoardJoinLinkByPin only looks at links that can still be used, pins are
+// short and may repeat once older links expired.
+func (p *PostgresStore) GetBoardJoinLinkByPin(pinHash string) (*models.BoardJoinLink, error) {
+	return scanBoardJoinLink(p.DB.QueryRow(
+		"SELECT "+BOARD_JOIN_LINK_COLUMNS+" FROM board_join_links WHERE pin_hash = $1 AND revoked_at IS NULL AND expires_at > $2 ORDER BY created_at DESC LIMIT 1",
+		pinHash, time.Now().UTC(),
+	))
+}
+
+// RevokeBoardJoinLink returns sql.ErrNoRows when the board has no such link
+// left to revoke.
+func (p *PostgresStore) RevokeBoardJoinLink(id, boardId uuid.UUID) error {
+	result, err := p.DB.Exec("UPDATE board_join_links SET revoked_at = $1 WHERE id = $2 AND board_id = $3 AND revoked_at IS NULL", time.Now().UTC(), id, boardId)
+	if err != nil {
+		log.Println("Error in revoking the board join link", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// CreateGuestUser stores the guest together with its participation in the
+// board it joined, the guest is deleted after expiresAt.
+func (p *PostgresStore) CreateGuestUser(user models.User, boardId uuid.UUID, expiresAt time.Time) error {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		"INSERT INTO users ("+USER_COLUMNS+", guest_board_id, guest_expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)",
+		user.Id, user.FirstName, user.LastName, user.Username, user.Password, user.Email, user.UserType, user.EmailVerified, user.CreatedAt, user.ModifiedAt, boardId, expiresAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the guest user", err)
+		return err
+	}
+
+	member := models.NewBoardMember(boardId, user.Id, models.BoardParticipant)
+	_, err = tx.Exec(
+		"INSERT INTO board_members ("+BOARD_MEMBER_COLUMNS+") VALUES ($1, $2, $3, $4)",
+		member.BoardId, member.UserId, member.Role, member.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the guest board member", err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteGuestUsers removes the guests matching the condition. Their feedback
+// stays on the board under the deleted user placeholder.
+func deleteGuestUsers(tx *sql.Tx, condition string, args ...any) (int64, error) {
+	guests := "SELECT id FROM users WHERE guest_expires_at IS NOT NULL AND " + condition
+
+	if err := detachUsers(tx, guests, args...); err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec("DELETE FROM users WHERE id IN ("+guests+")", args...)
+	if err != nil {
+		log.Println("Error in deleting the guest users", err)
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (p *PostgresStore) DeleteExpiredGuestUsers(now time.Time) (int64, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	count, err := deleteGuestUsers(tx, "guest_expires_at <= $1", now)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, tx.Commit()
+}
+
+// CloseBoard marks the board as closed, revokes its join links and deletes
+// the guests who joined it. It returns sql.ErrNoRows when the board does not
+// exist or is already closed.
+func (p *PostgresStore) CloseBoard(id uuid.UUID, closedAt time.Time) error {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE boards SET closed_at = $1, phase = 'closed' WHERE id = $2 AND closed_at IS NULL", closedAt, id)
+	if err != nil {
+		log.Println("Error in closing the board", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec("UPDATE board_join_links SET revoked_at = $1 WHERE board_id = $2 AND revoked_at IS NULL", closedAt, id)
+	if err != nil {
+		log.Println("Error in revoking the board join links", err)
+		return err
+	}
+
+	if _, err = deleteGuestUsers(tx, "guest_board_id = $1", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
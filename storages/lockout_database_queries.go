@@ -0,0 +1,35 @@
+package storages
+
+import (
+	"log"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+func (p *PostgresStore) CreateAccountLockout(lockout models.AccountLockout) error {
+	_, err := p.DB.Exec(
+		"INSERT INTO account_lockouts (id, username, ip_address, lockout_type, failed_attempts, locked_until, unlocked_by_id, unlocked_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		lockout.Id, lockout.Username, lockout.IPAddress, lockout.LockoutType, lockout.FailedAttempts, lockout.LockedUntil, lockout.UnlockedById, lockout.UnlockedAt, lockout.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the account lockout", err)
+		return err
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) UnlockAccountLockouts(username string, unlockedById uuid.UUID) error {
+	_, err := p.DB.Exec(
+		"UPDATE account_lockouts SET unlocked_by_id = $1, unlocked_at = $2 WHERE username = $3 AND unlocked_at IS NULL",
+		unlockedById, time.Now().UTC(), username,
+	)
+	if err != nil {
+		log.Println("Error in unlocking the account lockouts", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package storages
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+func (p *PostgresStore) GetUserMFA(userId uuid.UUID) (*models.UserMFA, error) {
+	mfa := new(models.UserMFA)
+
+	err := p.DB.QueryRow(
+		"SELECT user_id, secret, enabled, last_used_step, confirmed_at, created_at, modified_at FROM user_mfa WHERE user_id = $1", userId,
+	).Scan(&mfa.UserId, &mfa.Secret, &mfa.Enabled, &mfa.LastUsedStep, &mfa.ConfirmedAt, &mfa.CreatedAt, &mfa.ModifiedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return mfa, nil
+}
+
+func (p *PostgresStore) SaveUserMFA(mfa models.UserMFA) error {
+	_, err := p.DB.Exec(
+		`INSERT INTO user_mfa (user_id, secret, enabled, last_used_step, confirmed_at, created_at, modified_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE SET secret = $2, enabled = $3, last_used_step = $4, confirmed_at = $5, modified_at = $7`,
+		mfa.UserId, mfa.Secret, mfa.Enabled, mfa.LastUsedStep, mfa.ConfirmedAt, mfa.CreatedAt, mfa.ModifiedAt,
+	)
+	if err != nil {
+		log.Println("Error in saving the user mfa", err)
+		return err
+	}
+
+	return nil
+}
+
+// DeleteUserMFA removes the secret and every recovery code of the user.
+func (p *PostgresStore) DeleteUserMFA(userId uuid.UUID) error {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec("DELETE FROM mfa_recovery_codes WHERE user_id = $1", userId); err != nil {
+		log.Println("Error in deleting the mfa recovery codes", err)
+		return err
+	}
+
+	if _, err = tx.Exec("DELETE FROM user_mfa WHERE user_id = $1", userId); err != nil {
+		log.Println("Error in deleting the user mfa", err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceMFARecoveryCodes drops the previous codes of the user so only the
+// last generated set can be used.
+func (p *PostgresStore) ReplaceMFARecoveryCodes(userId uuid.UUID, codes []models.MFARecoveryCode) error {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec("DELETE FROM mfa_recovery_codes WHERE user_id = $1", userId); err != nil {
+		log.Println("Error in deleting the mfa recovery codes", err)
+		return err
+	}
+
+	for _, code := range codes {
+		_, err = tx.Exec(
+			"INSERT INTO mfa_recovery_codes (id, user_id, code_hash, used_at, created_at) VALUES ($1, $2, $3, $4, $5)",
+			code.Id, code.UserId, code.CodeHash, code.UsedAt, code.CreatedAt,
+		)
+		if err != nil {
+			log.Println("Error in creating the mfa recovery code", err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UseMFAStep records the time step of an accepted code, only when it is later
+// than the last one used. It returns sql.ErrNoRows otherwise, so a code is
+// accepted once even when it is sent by concurrent requests.
+func (p *PostgresStore) UseMFAStep(userId uuid.UUID, step int64) error {
+	result, err := p.DB.Exec(
+		"UPDATE user_mfa SET last_used_step = $1, modified_at = $2 WHERE user_id = $3 AND last_used_step < $1",
+		step, time.Now().UTC(), userId,
+	)
+	if err != nil {
+		log.Println("Error in using the mfa step", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UseMFARecoveryCode marks the code as used and returns sql.ErrNoRows when
+// it does not exist or was already used.
+func (p *PostgresStore) UseMFARecoveryCode(userId uuid.UUID, codeHash string) error {
+	result, err := p.DB.Exec(
+		"UPDATE mfa_recovery_codes SET used_at = $1 WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL",
+		time.Now().UTC(), userId, codeHash,
+	)
+	if err != nil {
+		log.Println("Error in using the mfa recovery code", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS account_lockouts;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE account_lockouts (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    username VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(64) NOT NULL,
+    lockout_type VARCHAR(20) NOT NULL,
+    failed_attempts INTEGER NOT NULL,
+    locked_until TIMESTAMP(3) NOT NULL,
+    unlocked_by_id VARCHAR(36),
+    FOREIGN KEY (unlocked_by_id) REFERENCES users(id),
+    unlocked_at TIMESTAMP(3),
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX account_lockouts_username_idx ON account_lockouts (username);
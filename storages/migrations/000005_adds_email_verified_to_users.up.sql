@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- accounts created before verification existed are trusted as they are
+UPDATE users SET email_verified = TRUE;
@@ -0,0 +1,13 @@
+CREATE TABLE user_sessions (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP(3) NOT NULL,
+    revoked_at TIMESTAMP(3)
+);
+
+CREATE INDEX user_sessions_user_id_idx ON user_sessions (user_id);
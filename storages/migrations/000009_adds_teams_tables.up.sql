@@ -0,0 +1,35 @@
+CREATE TABLE teams (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    created_by_id VARCHAR(36),
+    FOREIGN KEY (created_by_id) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE team_members (
+    team_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    user_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX team_members_user_id_idx ON team_members (user_id);
+
+-- Boards created before teams existed are moved into a default team that
+-- every existing user joins, so nobody loses access with the migration.
+INSERT INTO teams (id, name, description) VALUES ('00000000-0000-0000-0000-000000000001', 'Default', 'Boards created before teams were introduced');
+
+INSERT INTO team_members (team_id, user_id, role)
+SELECT '00000000-0000-0000-0000-000000000001', id, CASE WHEN user_type = 'super_admin' THEN 'owner' ELSE 'member' END FROM users;
+
+ALTER TABLE boards ADD COLUMN team_id VARCHAR(36);
+UPDATE boards SET team_id = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE boards ALTER COLUMN team_id SET NOT NULL;
+ALTER TABLE boards ADD CONSTRAINT boards_team_id_fkey FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE;
+
+CREATE INDEX boards_team_id_idx ON boards (team_id);
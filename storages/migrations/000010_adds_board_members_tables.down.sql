@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS board_invitations;
+DROP TABLE IF EXISTS board_members;
\ No newline at end of file
@@ -0,0 +1,36 @@
+CREATE TABLE board_members (
+    board_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (board_id) REFERENCES boards(id) ON DELETE CASCADE,
+    user_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (board_id, user_id)
+);
+
+CREATE INDEX board_members_user_id_idx ON board_members (user_id);
+
+CREATE TABLE board_invitations (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    board_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (board_id) REFERENCES boards(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    invited_by_id VARCHAR(36),
+    FOREIGN KEY (invited_by_id) REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP(3) NOT NULL,
+    accepted_at TIMESTAMP(3),
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX board_invitations_board_id_idx ON board_invitations (board_id);
+
+-- Existing boards keep working: their creators facilitate them and everyone
+-- who already posted feedback stays a participant.
+INSERT INTO board_members (board_id, user_id, role)
+SELECT id, created_by_id, 'facilitator' FROM boards WHERE created_by_id IS NOT NULL;
+
+INSERT INTO board_members (board_id, user_id, role)
+SELECT DISTINCT board_id, created_by_id, 'participant' FROM feedbacks WHERE created_by_id IS NOT NULL
+ON CONFLICT (board_id, user_id) DO NOTHING;
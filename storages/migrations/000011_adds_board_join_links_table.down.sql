@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS board_join_links;
+ALTER TABLE users DROP COLUMN IF EXISTS guest_expires_at;
+ALTER TABLE users DROP COLUMN IF EXISTS guest_board_id;
+ALTER TABLE boards DROP COLUMN IF EXISTS closed_at;
\ No newline at end of file
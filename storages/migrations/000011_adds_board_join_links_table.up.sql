@@ -0,0 +1,24 @@
+ALTER TABLE boards ADD COLUMN closed_at TIMESTAMP(3);
+
+-- Guests are regular users with a lifetime, they are deleted once it passed
+-- or when the board they joined is closed.
+ALTER TABLE users ADD COLUMN guest_board_id VARCHAR(36);
+ALTER TABLE users ADD CONSTRAINT users_guest_board_id_fkey FOREIGN KEY (guest_board_id) REFERENCES boards(id) ON DELETE SET NULL;
+ALTER TABLE users ADD COLUMN guest_expires_at TIMESTAMP(3);
+
+CREATE INDEX users_guest_expires_at_idx ON users (guest_expires_at) WHERE guest_expires_at IS NOT NULL;
+
+CREATE TABLE board_join_links (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    board_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (board_id) REFERENCES boards(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    pin_hash VARCHAR(64) NOT NULL,
+    created_by_id VARCHAR(36),
+    FOREIGN KEY (created_by_id) REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP(3) NOT NULL,
+    revoked_at TIMESTAMP(3),
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX board_join_links_pin_hash_idx ON board_join_links (pin_hash);
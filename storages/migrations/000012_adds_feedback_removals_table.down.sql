@@ -0,0 +1 @@
+DROP TABLE IF EXISTS feedback_removals;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE feedback_removals (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    feedback_id VARCHAR(36) NOT NULL,
+    board_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (board_id) REFERENCES boards(id) ON DELETE CASCADE,
+    message VARCHAR(100) NOT NULL,
+    created_by_id VARCHAR(36),
+    FOREIGN KEY (created_by_id) REFERENCES users(id) ON DELETE SET NULL,
+    removed_by_id VARCHAR(36),
+    FOREIGN KEY (removed_by_id) REFERENCES users(id) ON DELETE SET NULL,
+    reason VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX feedback_removals_board_id_idx ON feedback_removals (board_id);
@@ -0,0 +1,27 @@
+-- actor_id is not a foreign key on purpose, events have to outlive the users
+-- they mention.
+CREATE TABLE audit_events (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    actor_id VARCHAR(36),
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id VARCHAR(36) NOT NULL,
+    before JSONB,
+    after JSONB,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_events_created_at_idx ON audit_events (created_at);
+CREATE INDEX audit_events_actor_id_idx ON audit_events (actor_id);
+CREATE INDEX audit_events_target_idx ON audit_events (target_type, target_id);
+
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+BEFORE UPDATE OR DELETE ON audit_events
+FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS feedbacks_board_id_board_column_idx;
+ALTER TABLE feedbacks DROP COLUMN IF EXISTS board_column;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- Board columns are stored quoted, e.g. {'went_well','action'}. Existing
+-- feedback is put in the first column of its board.
+ALTER TABLE feedbacks ADD COLUMN board_column VARCHAR(100);
+UPDATE feedbacks SET board_column = trim(both '''' from boards.columns[1]) FROM boards WHERE boards.id = feedbacks.board_id;
+UPDATE feedbacks SET board_column = '' WHERE board_column IS NULL;
+ALTER TABLE feedbacks ALTER COLUMN board_column SET NOT NULL;
+
+CREATE INDEX feedbacks_board_id_board_column_idx ON feedbacks (board_id, board_column);
@@ -0,0 +1,9 @@
+ALTER TABLE boards ADD COLUMN columns TEXT[] NOT NULL DEFAULT '{}';
+UPDATE boards SET columns = board_column.keys
+FROM (
+    SELECT board_id, array_agg('''' || key || '''' ORDER BY position) AS keys FROM board_columns GROUP BY board_id
+) AS board_column
+WHERE boards.id = board_column.board_id;
+ALTER TABLE boards ALTER COLUMN columns DROP DEFAULT;
+
+DROP TABLE IF EXISTS board_columns;
\ No newline at end of file
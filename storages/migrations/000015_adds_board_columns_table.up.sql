@@ -0,0 +1,28 @@
+CREATE TABLE board_columns (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    board_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (board_id) REFERENCES boards(id) ON DELETE CASCADE,
+    key VARCHAR(50) NOT NULL,
+    title VARCHAR(100) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    color VARCHAR(7) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (board_id, key)
+);
+
+CREATE INDEX board_columns_board_id_position_idx ON board_columns (board_id, position);
+
+-- The array held quoted keys, e.g. {'went_well','action'}, the title is made
+-- from the key so went_well reads "Went Well".
+INSERT INTO board_columns (id, board_id, key, title, position)
+SELECT gen_random_uuid()::text, boards.id, column_key.key, initcap(replace(column_key.key, '_', ' ')), column_key.position
+FROM boards
+CROSS JOIN LATERAL (
+    SELECT DISTINCT ON (trim(both '''' from unnested.value)) trim(both '''' from unnested.value) AS key, unnested.ordinality - 1 AS position
+    FROM unnest(boards.columns) WITH ORDINALITY AS unnested(value, ordinality)
+    ORDER BY trim(both '''' from unnested.value), unnested.ordinality
+) AS column_key;
+
+ALTER TABLE boards DROP COLUMN columns;
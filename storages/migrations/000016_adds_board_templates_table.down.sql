@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS board_templates;
+ALTER TABLE boards DROP COLUMN IF EXISTS settings;
\ No newline at end of file
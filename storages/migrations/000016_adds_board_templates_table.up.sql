@@ -0,0 +1,22 @@
+ALTER TABLE boards ADD COLUMN settings JSONB NOT NULL DEFAULT '{}';
+
+-- Boards made from the old templates keep their action items in the action
+-- column.
+UPDATE boards SET settings = jsonb_build_object('votes_per_participant', 0, 'action_column', 'action')
+WHERE EXISTS (SELECT 1 FROM board_columns WHERE board_columns.board_id = boards.id AND board_columns.key = 'action');
+
+CREATE TABLE board_templates (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    team_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    key VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    columns JSONB NOT NULL,
+    settings JSONB NOT NULL DEFAULT '{}',
+    created_by_id VARCHAR(36),
+    FOREIGN KEY (created_by_id) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (team_id, key)
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS feedback_votes;
+DROP INDEX IF EXISTS feedbacks_group_id_idx;
+ALTER TABLE feedbacks DROP COLUMN IF EXISTS group_id;
+ALTER TABLE boards DROP COLUMN IF EXISTS phase;
\ No newline at end of file
@@ -0,0 +1,23 @@
+-- Boards that were open before phases existed are taken to be collecting
+-- feedback, new boards start as drafts.
+ALTER TABLE boards ADD COLUMN phase VARCHAR(20) NOT NULL DEFAULT 'draft';
+UPDATE boards SET phase = CASE WHEN closed_at IS NULL THEN 'collecting' ELSE 'closed' END;
+
+ALTER TABLE feedbacks ADD COLUMN group_id VARCHAR(36);
+ALTER TABLE feedbacks ADD FOREIGN KEY (group_id) REFERENCES feedbacks(id) ON DELETE SET NULL;
+
+CREATE INDEX feedbacks_group_id_idx ON feedbacks (group_id);
+
+CREATE TABLE feedback_votes (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    feedback_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (feedback_id) REFERENCES feedbacks(id) ON DELETE CASCADE,
+    board_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (board_id) REFERENCES boards(id) ON DELETE CASCADE,
+    user_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX feedback_votes_feedback_id_idx ON feedback_votes (feedback_id);
+CREATE INDEX feedback_votes_board_id_user_id_idx ON feedback_votes (board_id, user_id);
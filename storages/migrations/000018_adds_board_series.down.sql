@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS feedbacks_origin_id_idx;
+ALTER TABLE feedbacks DROP COLUMN IF EXISTS origin_id;
+ALTER TABLE feedbacks DROP COLUMN IF EXISTS resolved_at;
+DROP INDEX IF EXISTS boards_previous_board_id_idx;
+ALTER TABLE boards DROP COLUMN IF EXISTS previous_board_id;
\ No newline at end of file
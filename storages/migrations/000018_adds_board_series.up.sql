@@ -0,0 +1,12 @@
+ALTER TABLE boards ADD COLUMN previous_board_id VARCHAR(36);
+ALTER TABLE boards ADD FOREIGN KEY (previous_board_id) REFERENCES boards(id) ON DELETE SET NULL;
+
+CREATE INDEX boards_previous_board_id_idx ON boards (previous_board_id);
+
+-- origin_id points at the feedback an action item was first written as, when
+-- it was carried over from an earlier board of the series.
+ALTER TABLE feedbacks ADD COLUMN resolved_at TIMESTAMP(3);
+ALTER TABLE feedbacks ADD COLUMN origin_id VARCHAR(36);
+ALTER TABLE feedbacks ADD FOREIGN KEY (origin_id) REFERENCES feedbacks(id) ON DELETE SET NULL;
+
+CREATE INDEX feedbacks_origin_id_idx ON feedbacks (origin_id);
@@ -0,0 +1,2 @@
+ALTER TABLE feedbacks DROP CONSTRAINT IF EXISTS feedbacks_board_id_fkey;
+ALTER TABLE feedbacks ADD CONSTRAINT feedbacks_board_id_fkey FOREIGN KEY (board_id) REFERENCES boards(id);
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Feedback was the only table left that kept a board, and so its team, from
+-- being deleted.
+ALTER TABLE feedbacks DROP CONSTRAINT IF EXISTS feedbacks_board_id_fkey;
+ALTER TABLE feedbacks ADD CONSTRAINT feedbacks_board_id_fkey FOREIGN KEY (board_id) REFERENCES boards(id) ON DELETE CASCADE;
@@ -0,0 +1,11 @@
+-- Links an account to the subject of an OpenID Connect provider, logins are
+-- matched on it instead of the email, which the provider may let change.
+CREATE TABLE user_identities (
+    provider VARCHAR(64) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, subject),
+    UNIQUE (provider, user_id)
+);
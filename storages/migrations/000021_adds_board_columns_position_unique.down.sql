@@ -0,0 +1,2 @@
+ALTER TABLE board_columns DROP CONSTRAINT IF EXISTS board_columns_board_id_position_key;
+CREATE INDEX IF NOT EXISTS board_columns_board_id_position_idx ON board_columns (board_id, position);
\ No newline at end of file
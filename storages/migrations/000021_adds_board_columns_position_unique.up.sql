@@ -0,0 +1,12 @@
+-- Columns added at the same time could end up at the same position, number
+-- them again before the positions are made unique. The constraint is only
+-- checked at commit, reordering moves columns through taken positions.
+UPDATE board_columns SET position = numbered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY board_id ORDER BY position, created_at) - 1 AS position
+    FROM board_columns
+) AS numbered
+WHERE board_columns.id = numbered.id;
+
+DROP INDEX IF EXISTS board_columns_board_id_position_idx;
+ALTER TABLE board_columns ADD CONSTRAINT board_columns_board_id_position_key UNIQUE (board_id, position) DEFERRABLE INITIALLY DEFERRED;
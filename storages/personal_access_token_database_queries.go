@@ -0,0 +1,91 @@
+package storages
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const PERSONAL_ACCESS_TOKEN_COLUMNS = "id, user_id, name, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at"
+
+func scanPersonalAccessToken(row rowScanner) (*models.PersonalAccessToken, error) {
+	token := new(models.PersonalAccessToken)
+	err := row.Scan(&token.Id, &token.UserId, &token.Name, &token.TokenHash, pq.Array(&token.Scopes), &token.ExpiresAt, &token.LastUsedAt, &token.RevokedAt, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (p *PostgresStore) CreatePersonalAccessToken(token models.PersonalAccessToken) error {
+	_, err := p.DB.Exec(
+		"INSERT INTO personal_access_tokens ("+PERSONAL_ACCESS_TOKEN_COLUMNS+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		token.Id, token.UserId, token.Name, token.TokenHash, pq.Array(token.Scopes), token.ExpiresAt, token.LastUsedAt, token.RevokedAt, token.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the personal access token", err)
+		return err
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) GetPersonalAccessTokensByUserId(userId uuid.UUID) ([]*models.PersonalAccessToken, error) {
+	rows, err := p.DB.Query("SELECT "+PERSONAL_ACCESS_TOKEN_COLUMNS+" FROM personal_access_tokens WHERE user_id = $1 AND revoked_at IS NULL ORDER BY created_at DESC", userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make([]*models.PersonalAccessToken, 0)
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+func (p *PostgresStore) GetPersonalAccessTokenByHash(tokenHash string) (*models.PersonalAccessToken, error) {
+	return scanPersonalAccessToken(p.DB.QueryRow("SELECT "+PERSONAL_ACCESS_TOKEN_COLUMNS+" FROM personal_access_tokens WHERE token_hash = $1", tokenHash))
+}
+
+// RevokePersonalAccessToken only revokes tokens owned by the given user and
+// returns sql.ErrNoRows when there is none to revoke.
+func (p *PostgresStore) RevokePersonalAccessToken(id, userId uuid.UUID) error {
+	result, err := p.DB.Exec("UPDATE personal_access_tokens SET revoked_at = $1 WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL", time.Now().UTC(), id, userId)
+	if err != nil {
+		log.Println("Error in revoking the personal access token", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) TouchPersonalAccessToken(id uuid.UUID, lastUsedAt time.Time) error {
+	_, err := p.DB.Exec("UPDATE personal_access_tokens SET last_used_at = $1 WHERE id = $2", lastUsedAt, id)
+	if err != nil {
+		log.Println("Error in updating the personal access token last used time", err)
+		return err
+	}
+
+	return nil
+}
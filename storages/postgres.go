@@ -2,11 +2,16 @@ package storages
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+
+	"github.com/lib/pq"
 )
 
+const UNIQUE_VIOLATION = "23505"
+
 type PostgresStore struct {
 	DB *sql.DB
 }
@@ -27,3 +32,11 @@ func NewPostgresDB() (*PostgresStore, error) {
 	log.Println("Connected to Postgres")
 	return &PostgresStore{DB: db}, nil
 }
+
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == UNIQUE_VIOLATION
+	}
+	return false
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 )
@@ -16,9 +17,15 @@ type RedisStore struct {
 
 type RedisStoreInterface interface {
 	Set(key string, value interface{}) error
+	SetWithExpiry(key string, value interface{}, expiration time.Duration) error
+	SetNX(key string, value interface{}, expiration time.Duration) (bool, error)
 	Get(key string, typeInfo interface{}) (interface{}, error)
+	GetDel(key string, typeInfo interface{}) (interface{}, error)
+	Exists(key string) (bool, error)
+	Incr(key string, expiration time.Duration) (int64, error)
+	TTL(key string) (time.Duration, error)
 	Del(key string) error
-	FlushAll() error
+	DelMatching(pattern string) error
 }
 
 func NewRedisDB() (*RedisStore, error) {
@@ -47,6 +54,22 @@ func (r *RedisStore) Set(key string, value interface{}) error {
 	return nil
 }
 
+func (r *RedisStore) SetWithExpiry(key string, value interface{}, expiration time.Duration) error {
+	data, _ := json.Marshal(value)
+	err := r.Client.Set(context.Background(), key, data, expiration).Err()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetNX only sets the key when it does not exist yet and reports whether it
+// did, of several concurrent callers exactly one gets true.
+func (r *RedisStore) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	data, _ := json.Marshal(value)
+	return r.Client.SetNX(context.Background(), key, data, expiration).Result()
+}
+
 func (r *RedisStore) Get(key string, typeInfo interface{}) (interface{}, error) {
 	data, err := r.Client.Get(context.Background(), key).Result()
 	if err != nil {
@@ -57,6 +80,46 @@ func (r *RedisStore) Get(key string, typeInfo interface{}) (interface{}, error)
 	return typeInfo, nil
 }
 
+// GetDel reads the value and deletes the key in one step, so only one of
+// several concurrent readers gets it.
+func (r *RedisStore) GetDel(key string, typeInfo interface{}) (interface{}, error) {
+	data, err := r.Client.GetDel(context.Background(), key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	json.Unmarshal([]byte(data), &typeInfo)
+	return typeInfo, nil
+}
+
+func (r *RedisStore) Exists(key string) (bool, error) {
+	count, err := r.Client.Exists(context.Background(), key).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Incr increments the counter stored at key and pushes its expiry to
+// expiration from now, so the counter lives as long as it keeps being hit.
+func (r *RedisStore) Incr(key string, expiration time.Duration) (int64, error) {
+	pipe := r.Client.TxPipeline()
+	count := pipe.Incr(context.Background(), key)
+	pipe.Expire(context.Background(), key, expiration)
+
+	_, err := pipe.Exec(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	return count.Val(), nil
+}
+
+func (r *RedisStore) TTL(key string) (time.Duration, error) {
+	return r.Client.TTL(context.Background(), key).Result()
+}
+
 func (r *RedisStore) Del(key string) error {
 	data, err := r.Client.Del(context.Background(), key).Result()
 	if err != nil {
@@ -70,11 +133,15 @@ func (r *RedisStore) Del(key string) error {
 	return nil
 }
 
-func (r *RedisStore) FlushAll() error {
-	err := r.Client.FlushAll(context.Background()).Err()
-	if err != nil {
-		return err
+// DelMatching deletes every key matching the glob pattern, walking the keys
+// with SCAN so Redis is not blocked on a large keyspace.
+func (r *RedisStore) DelMatching(pattern string) error {
+	iter := r.Client.Scan(context.Background(), 0, pattern, 100).Iterator()
+	for iter.Next(context.Background()) {
+		if err := r.Client.Del(context.Background(), iter.Val()).Err(); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return iter.Err()
 }
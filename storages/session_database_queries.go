@@ -0,0 +1,113 @@
+package storages
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+const USER_SESSION_COLUMNS = "id, user_id, user_agent, ip_address, created_at, last_seen_at, expires_at, revoked_at"
+
+func scanUserSession(row rowScanner) (*models.UserSession, error) {
+	session := new(models.UserSession)
+	err := row.Scan(&session.Id, &session.UserId, &session.UserAgent, &session.IPAddress, &session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt, &session.RevokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (p *PostgresStore) CreateUserSession(session models.UserSession) error {
+	_, err := p.DB.Exec(
+		"INSERT INTO user_sessions ("+USER_SESSION_COLUMNS+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		session.Id, session.UserId, session.UserAgent, session.IPAddress, session.CreatedAt, session.LastSeenAt, session.ExpiresAt, session.RevokedAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the user session", err)
+		return err
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) GetUserSession(id uuid.UUID) (*models.UserSession, error) {
+	return scanUserSession(p.DB.QueryRow("SELECT "+USER_SESSION_COLUMNS+" FROM user_sessions WHERE id = $1", id))
+}
+
+func (p *PostgresStore) GetActiveUserSessions(userId uuid.UUID) ([]*models.UserSession, error) {
+	rows, err := p.DB.Query(
+		"SELECT "+USER_SESSION_COLUMNS+" FROM user_sessions WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2 ORDER BY last_seen_at DESC",
+		userId, time.Now().UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]*models.UserSession, 0)
+	for rows.Next() {
+		session, err := scanUserSession(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// TouchUserSession records activity on the session, expiresAt is only moved
+// forward when the session is refreshed and is left alone when zero.
+func (p *PostgresStore) TouchUserSession(id uuid.UUID, lastSeenAt, expiresAt time.Time) error {
+	var err error
+	if expiresAt.IsZero() {
+		_, err = p.DB.Exec("UPDATE user_sessions SET last_seen_at = $1 WHERE id = $2", lastSeenAt, id)
+	} else {
+		_, err = p.DB.Exec("UPDATE user_sessions SET last_seen_at = $1, expires_at = $2 WHERE id = $3", lastSeenAt, expiresAt, id)
+	}
+
+	if err != nil {
+		log.Println("Error in updating the user session", err)
+		return err
+	}
+
+	return nil
+}
+
+// RevokeUserSession only revokes sessions of the given user and returns
+// sql.ErrNoRows when there is no active one to revoke.
+func (p *PostgresStore) RevokeUserSession(id, userId uuid.UUID) error {
+	result, err := p.DB.Exec("UPDATE user_sessions SET revoked_at = $1 WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL", time.Now().UTC(), id, userId)
+	if err != nil {
+		log.Println("Error in revoking the user session", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// RevokeUserSessions revokes every session of the user except the one given,
+// pass uuid.Nil to revoke them all.
+func (p *PostgresStore) RevokeUserSessions(userId, exceptId uuid.UUID) error {
+	_, err := p.DB.Exec("UPDATE user_sessions SET revoked_at = $1 WHERE user_id = $2 AND id <> $3 AND revoked_at IS NULL", time.Now().UTC(), userId, exceptId)
+	if err != nil {
+		log.Println("Error in revoking the user sessions", err)
+		return err
+	}
+
+	return nil
+}
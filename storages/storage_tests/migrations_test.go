@@ -0,0 +1,56 @@
+package storages_tests
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var (
+	migrationTablePattern      = regexp.MustCompile(`(?i)^(?:CREATE|ALTER) TABLE (?:IF EXISTS )?(\w+)`)
+	migrationForeignKeyPattern = regexp.MustCompile(`(?i)FOREIGN KEY \((\w+)\) REFERENCES boards\(id\)(.*)`)
+)
+
+// TestBoardForeignKeysCascade replays the up migrations and checks that every
+// foreign key to boards, as last defined, says what happens when the board is
+// deleted. Without it deleting a board or a team fails.
+func TestBoardForeignKeysCascade(t *testing.T) {
+	files, err := filepath.Glob("../migrations/*.up.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("failed to find the migrations: %v", err)
+	}
+	sort.Strings(files)
+
+	foreignKeys := make(map[string]string)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %v: %v", file, err)
+		}
+
+		table := ""
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			if match := migrationTablePattern.FindStringSubmatch(line); match != nil {
+				table = match[1]
+			}
+
+			if match := migrationForeignKeyPattern.FindStringSubmatch(line); match != nil {
+				foreignKeys[table+"."+match[1]] = match[2]
+			}
+		}
+	}
+
+	if _, ok := foreignKeys["feedbacks.board_id"]; !ok {
+		t.Fatalf("returned unexpected output: feedbacks.board_id has no foreign key to boards")
+	}
+
+	for column, action := range foreignKeys {
+		if !strings.Contains(strings.ToUpper(action), "ON DELETE") {
+			t.Errorf("returned unexpected output: %v keeps its board from being deleted", column)
+		}
+	}
+}
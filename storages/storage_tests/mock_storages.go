@@ -1,17 +1,98 @@
 package storages_tests
 
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
 func (m *MockRedisClient) Set(key string, value interface{}) error {
 	return nil
 }
 
+func (m *MockRedisClient) SetWithExpiry(key string, value interface{}, expiration time.Duration) error {
+	return nil
+}
+
 func (m *MockRedisClient) Get(key string, typeInfo interface{}) (interface{}, error) {
 	return nil, nil
 }
 
+func (m *MockRedisClient) GetDel(key string, typeInfo interface{}) (interface{}, error) {
+	return nil, nil
+}
+
+func (m *MockRedisClient) Exists(key string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockRedisClient) Incr(key string, expiration time.Duration) (int64, error) {
+	return 1, nil
+}
+
+func (m *MockRedisClient) TTL(key string) (time.Duration, error) {
+	return 0, nil
+}
+
 func (m *MockRedisClient) Del(key string) error {
 	return nil
 }
 
-func (m *MockRedisClient) FlushAll() error {
+func (m *MockRedisClient) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (m *MockRedisClient) DelMatching(pattern string) error {
+	return nil
+}
+
+// MockRedisClientWithValues keeps what is set, for flows that read back a
+// value they stored earlier.
+type MockRedisClientWithValues struct {
+	MockRedisClient
+	Values map[string][]byte
+}
+
+func (m *MockRedisClientWithValues) SetWithExpiry(key string, value interface{}, expiration time.Duration) error {
+	if m.Values == nil {
+		m.Values = make(map[string][]byte)
+	}
+
+	data, _ := json.Marshal(value)
+	m.Values[key] = data
+	return nil
+}
+
+func (m *MockRedisClientWithValues) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	if _, ok := m.Values[key]; ok {
+		return false, nil
+	}
+
+	return true, m.SetWithExpiry(key, value, expiration)
+}
+
+func (m *MockRedisClientWithValues) Exists(key string) (bool, error) {
+	_, ok := m.Values[key]
+	return ok, nil
+}
+
+func (m *MockRedisClientWithValues) Get(key string, typeInfo interface{}) (interface{}, error) {
+	data, ok := m.Values[key]
+	if !ok {
+		return nil, errors.New("redis: nil")
+	}
+
+	json.Unmarshal(data, &typeInfo)
+	return typeInfo, nil
+}
+
+func (m *MockRedisClientWithValues) GetDel(key string, typeInfo interface{}) (interface{}, error) {
+	value, err := m.Get(key, typeInfo)
+	delete(m.Values, key)
+	return value, err
+}
+
+func (m *MockRedisClientWithValues) Del(key string) error {
+	delete(m.Values, key)
 	return nil
 }
@@ -0,0 +1,219 @@
+package storages
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+const (
+	TEAM_COLUMNS        = "id, name, description, created_by_id, created_at, modified_at"
+	TEAM_MEMBER_COLUMNS = "team_id, user_id, role, created_at"
+)
+
+func scanTeam(row rowScanner) (*models.Team, error) {
+	team := new(models.Team)
+
+	var createdById sql.NullString
+	err := row.Scan(&team.Id, &team.Name, &team.Description, &createdById, &team.CreatedAt, &team.ModifiedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if createdById.Valid {
+		team.CreatedById, _ = uuid.Parse(createdById.String)
+	}
+
+	return team, nil
+}
+
+func scanTeamMembership(row rowScanner) (*models.TeamMembership, error) {
+	membership := new(models.TeamMembership)
+	err := row.Scan(&membership.TeamId, &membership.UserId, &membership.Role, &membership.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return membership, nil
+}
+
+func (p *PostgresStore) queryTeams(query string, args ...any) ([]*models.Team, error) {
+	rows, err := p.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := make([]*models.Team, 0)
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		teams = append(teams, team)
+	}
+
+	return teams, nil
+}
+
+func (p *PostgresStore) GetAllTeams(limit, offset int) ([]*models.Team, error) {
+	return p.queryTeams("SELECT "+TEAM_COLUMNS+" FROM teams ORDER BY name LIMIT $1 OFFSET $2", limit, offset)
+}
+
+func (p *PostgresStore) GetTeamsForUser(userId uuid.UUID, limit, offset int) ([]*models.Team, error) {
+	return p.queryTeams(
+		"SELECT "+TEAM_COLUMNS+" FROM teams WHERE id IN (SELECT team_id FROM team_members WHERE user_id = $1) ORDER BY name LIMIT $2 OFFSET $3",
+		userId, limit, offset,
+	)
+}
+
+func (p *PostgresStore) GetTeamById(id uuid.UUID) (*models.Team, error) {
+	return scanTeam(p.DB.QueryRow("SELECT "+TEAM_COLUMNS+" FROM teams WHERE id = $1", id))
+}
+
+// CreateTeam stores the team together with the membership of its owner, a
+// team is never left without anyone able to manage it.
+func (p *PostgresStore) CreateTeam(team models.Team, owner models.TeamMembership) error {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		"INSERT INTO teams ("+TEAM_COLUMNS+") VALUES ($1, $2, $3, $4, $5, $6)",
+		team.Id, team.Name, team.Description, team.CreatedById, team.CreatedAt, team.ModifiedAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the team", err)
+		return err
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO team_members ("+TEAM_MEMBER_COLUMNS+") VALUES ($1, $2, $3, $4)",
+		owner.TeamId, owner.UserId, owner.Role, owner.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the team owner", err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (p *PostgresStore) UpdateTeam(team models.Team) (models.Team, error) {
+	_, err := p.DB.Exec(
+		"UPDATE teams SET name = $1, description = $2, modified_at = $3 WHERE id = $4",
+		team.Name, team.Description, team.ModifiedAt, team.Id,
+	)
+	if err != nil {
+		log.Println("Error in updating the team", err)
+		return models.Team{}, err
+	}
+
+	return team, nil
+}
+
+// DeleteTeam removes the team with its memberships and boards, it returns
+// sql.ErrNoRows when the team does not exist.
+func (p *PostgresStore) DeleteTeam(id uuid.UUID) error {
+	result, err := p.DB.Exec("DELETE FROM teams WHERE id = $1", id)
+	if err != nil {
+		log.Println("Error in deleting the team", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) GetTeamMembers(teamId uuid.UUID) ([]*models.TeamMembership, error) {
+	rows, err := p.DB.Query("SELECT "+TEAM_MEMBER_COLUMNS+" FROM team_members WHERE team_id = $1 ORDER BY created_at", teamId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]*models.TeamMembership, 0)
+	for rows.Next() {
+		membership, err := scanTeamMembership(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		membership.User, _ = p.GetUserById(membership.UserId)
+
+		members = append(members, membership)
+	}
+
+	return members, nil
+}
+
+// GetTeamMembership returns sql.ErrNoRows when the user is not a member of
+// the team.
+func (p *PostgresStore) GetTeamMembership(teamId, userId uuid.UUID) (*models.TeamMembership, error) {
+	return scanTeamMembership(p.DB.QueryRow("SELECT "+TEAM_MEMBER_COLUMNS+" FROM team_members WHERE team_id = $1 AND user_id = $2", teamId, userId))
+}
+
+// SaveTeamMember adds the user to the team, or changes their role when they
+// already are a member.
+func (p *PostgresStore) SaveTeamMember(membership models.TeamMembership) error {
+	_, err := p.DB.Exec(
+		"INSERT INTO team_members ("+TEAM_MEMBER_COLUMNS+") VALUES ($1, $2, $3, $4) ON CONFLICT (team_id, user_id) DO UPDATE SET role = EXCLUDED.role",
+		membership.TeamId, membership.UserId, membership.Role, membership.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error in saving the team member", err)
+		return err
+	}
+
+	return nil
+}
+
+// RemoveTeamMember returns sql.ErrNoRows when the user is not a member of
+// the team.
+func (p *PostgresStore) RemoveTeamMember(teamId, userId uuid.UUID) error {
+	result, err := p.DB.Exec("DELETE FROM team_members WHERE team_id = $1 AND user_id = $2", teamId, userId)
+	if err != nil {
+		log.Println("Error in removing the team member", err)
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetTeamsOwnedOnlyBy returns the teams the user is the only owner of, they
+// would be left without an owner if the user was deleted.
+func (p *PostgresStore) GetTeamsOwnedOnlyBy(userId uuid.UUID) ([]*models.Team, error) {
+	return p.queryTeams(
+		"SELECT "+TEAM_COLUMNS+" FROM teams WHERE id IN (SELECT team_id FROM team_members WHERE user_id = $1 AND role = $2) "+
+			"AND NOT EXISTS (SELECT 1 FROM team_members WHERE team_id = teams.id AND role = $2 AND user_id <> $1) ORDER BY name",
+		userId, models.TeamRoleOwner,
+	)
+}
+
+func (p *PostgresStore) CountTeamOwners(teamId uuid.UUID) (int, error) {
+	var count int
+	err := p.DB.QueryRow("SELECT COUNT(*) FROM team_members WHERE team_id = $1 AND role = $2", teamId, models.TeamRoleOwner).Scan(&count)
+	return count, err
+}
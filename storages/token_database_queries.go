@@ -0,0 +1,61 @@
+package storages
+
+import (
+	"log"
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+func (p *PostgresStore) CreatePasswordResetToken(token models.PasswordResetToken) error {
+	_, err := p.DB.Exec(
+		"INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, used_at, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+		token.Id, token.UserId, token.TokenHash, token.ExpiresAt, token.UsedAt, token.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error in creating the password reset token", err)
+		return err
+	}
+
+	return nil
+}
+
+func (p *PostgresStore) GetPasswordResetTokenByHash(tokenHash string) (*models.PasswordResetToken, error) {
+	token := new(models.PasswordResetToken)
+
+	err := p.DB.QueryRow("SELECT * FROM password_reset_tokens WHERE token_hash = $1", tokenHash).Scan(&token.Id, &token.UserId, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// UsePasswordResetToken marks the token as used and returns its user, in one
+// statement so a token can only be redeemed once even by concurrent requests.
+// It returns sql.ErrNoRows when the token is unknown, used or expired.
+func (p *PostgresStore) UsePasswordResetToken(tokenHash string) (uuid.UUID, error) {
+	var userId uuid.UUID
+
+	now := time.Now().UTC()
+	err := p.DB.QueryRow(
+		"UPDATE password_reset_tokens SET used_at = $1 WHERE token_hash = $2 AND used_at IS NULL AND expires_at > $1 RETURNING user_id",
+		now, tokenHash,
+	).Scan(&userId)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return userId, nil
+}
+
+func (p *PostgresStore) MarkPasswordResetTokensUsed(userId uuid.UUID) error {
+	_, err := p.DB.Exec("UPDATE password_reset_tokens SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL", time.Now().UTC(), userId)
+	if err != nil {
+		log.Println("Error in updating the password reset tokens", err)
+		return err
+	}
+
+	return nil
+}
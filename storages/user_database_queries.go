@@ -1,7 +1,9 @@
 package storages
 
 import (
+	"database/sql"
 	"log"
+	"time"
 
 	"github.com/Aakash-Pandit/reetro-golang/common"
 	"github.com/Aakash-Pandit/reetro-golang/models"
@@ -9,8 +11,24 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const USER_COLUMNS = "id, first_name, last_name, username, password, email, user_type, email_verified, created_at, modified_at"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
+	user := new(models.User)
+	err := row.Scan(&user.Id, &user.FirstName, &user.LastName, &user.Username, &user.Password, &user.Email, &user.UserType, &user.EmailVerified, &user.CreatedAt, &user.ModifiedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
 func (p *PostgresStore) GetAllUsers(limit, offset int) ([]*models.CreateUserResponse, error) {
-	rows, err := p.DB.Query("SELECT * FROM users LIMIT $1 OFFSET $2", limit, offset)
+	rows, err := p.DB.Query("SELECT "+USER_COLUMNS+" FROM users LIMIT $1 OFFSET $2", limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -18,8 +36,7 @@ func (p *PostgresStore) GetAllUsers(limit, offset int) ([]*models.CreateUserResp
 
 	users := make([]*models.CreateUserResponse, 0)
 	for rows.Next() {
-		user := new(models.User)
-		err := rows.Scan(&user.Id, &user.FirstName, &user.LastName, &user.Username, &user.Password, &user.Email, &user.UserType, &user.CreatedAt, &user.ModifiedAt)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -33,9 +50,7 @@ func (p *PostgresStore) GetAllUsers(limit, offset int) ([]*models.CreateUserResp
 }
 
 func (p *PostgresStore) GetUserById(id uuid.UUID) (*models.CreateUserResponse, error) {
-	user := new(models.User)
-
-	err := p.DB.QueryRow("SELECT * FROM users WHERE id = $1", id).Scan(&user.Id, &user.FirstName, &user.LastName, &user.Username, &user.Password, &user.Email, &user.UserType, &user.CreatedAt, &user.ModifiedAt)
+	user, err := scanUser(p.DB.QueryRow("SELECT "+USER_COLUMNS+" FROM users WHERE id = $1", id))
 	if err != nil {
 		return nil, err
 	}
@@ -45,10 +60,25 @@ func (p *PostgresStore) GetUserById(id uuid.UUID) (*models.CreateUserResponse, e
 	return msg.(*models.CreateUserResponse), nil
 }
 
+// authorById returns the user for an author column, the deleted user
+// placeholder when the account was deleted and the column cleared.
+func (p *PostgresStore) authorById(id uuid.UUID) *models.CreateUserResponse {
+	if id == uuid.Nil {
+		return models.DeletedUser()
+	}
+
+	user, err := p.GetUserById(id)
+	if err != nil {
+		return models.DeletedUser()
+	}
+
+	return user
+}
+
 func (p *PostgresStore) CreateUser(user models.User) (models.User, error) {
 	_, err := p.DB.Exec(
-		"INSERT INTO users (id, first_name, last_name, username, password, email, user_type, created_at, modified_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
-		user.Id, user.FirstName, user.LastName, user.Username, user.Password, user.Email, user.UserType, user.CreatedAt, user.ModifiedAt,
+		"INSERT INTO users (id, first_name, last_name, username, password, email, user_type, email_verified, created_at, modified_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
+		user.Id, user.FirstName, user.LastName, user.Username, user.Password, user.Email, user.UserType, user.EmailVerified, user.CreatedAt, user.ModifiedAt,
 	)
 	if err != nil {
 		log.Println("Error in creating the user", err)
@@ -58,21 +88,76 @@ func (p *PostgresStore) CreateUser(user models.User) (models.User, error) {
 	return user, nil
 }
 
+func (p *PostgresStore) UpdateUser(user models.CreateUserResponse) (models.CreateUserResponse, error) {
+	_, err := p.DB.Exec(
+		"UPDATE users SET first_name = $1, last_name = $2, username = $3, email = $4, user_type = $5, email_verified = $6, modified_at = $7 WHERE id = $8",
+		user.FirstName, user.LastName, user.Username, user.Email, user.UserType, user.EmailVerified, user.ModifiedAt, user.Id,
+	)
+	if err != nil {
+		log.Println("Error in updating the user", err)
+		return models.CreateUserResponse{}, err
+	}
+
+	return user, nil
+}
+
+// detachUsers clears the references to the users selected by the subquery
+// that would otherwise block deleting them. Their boards and feedback stay
+// and are shown with the deleted user placeholder as author.
+func detachUsers(tx *sql.Tx, users string, args ...any) error {
+	statements := []string{
+		"UPDATE boards SET created_by_id = NULL WHERE created_by_id IN (" + users + ")",
+		"UPDATE boards SET modified_by_id = NULL WHERE modified_by_id IN (" + users + ")",
+		"UPDATE feedbacks SET created_by_id = NULL WHERE created_by_id IN (" + users + ")",
+		"UPDATE account_lockouts SET unlocked_by_id = NULL WHERE unlocked_by_id IN (" + users + ")",
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement, args...); err != nil {
+			log.Println("Error in detaching the content of the users", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteUser deletes the account and everything owned by it, the boards and
+// feedback it authored are kept but anonymized. It returns sql.ErrNoRows when
+// the user does not exist.
 func (p *PostgresStore) DeleteUser(id uuid.UUID) error {
-	user := new(models.User)
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	user, err := scanUser(tx.QueryRow("SELECT "+USER_COLUMNS+" FROM users WHERE id = $1", id))
+	if err != nil {
+		return err
+	}
+
+	if err = detachUsers(tx, "SELECT id FROM users WHERE id = $1", id); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM account_lockouts WHERE username = $1", user.Username)
+	if err != nil {
+		log.Println("Error in deleting the account lockouts of the user", err)
+		return err
+	}
 
-	err := p.DB.QueryRow("SELECT * FROM users WHERE id = $1", id).Scan(&user.Id, &user.FirstName, &user.LastName, &user.Username, &user.Password, &user.Email, &user.UserType, &user.CreatedAt, &user.ModifiedAt)
+	_, err = tx.Exec("DELETE FROM users WHERE id = $1", id)
 	if err != nil {
+		log.Println("Error in deleting the user", err)
 		return err
 	}
 
-	_, err = p.DB.Exec("DELETE FROM users WHERE id = $1", id)
-	return err
+	return tx.Commit()
 }
 
 func (p *PostgresStore) VerifyUserByUsername(username string) (*models.User, error) {
-	user := new(models.User)
-	err := p.DB.QueryRow("SELECT * FROM users WHERE username = $1", username).Scan(&user.Id, &user.FirstName, &user.LastName, &user.Username, &user.Password, &user.Email, &user.UserType, &user.CreatedAt, &user.ModifiedAt)
+	user, err := scanUser(p.DB.QueryRow("SELECT "+USER_COLUMNS+" FROM users WHERE username = $1", username))
 
 	if err != nil {
 		log.Println("Error in fetching the user", err)
@@ -83,8 +168,7 @@ func (p *PostgresStore) VerifyUserByUsername(username string) (*models.User, err
 }
 
 func (p *PostgresStore) VerifyUserByEmail(email string) (*models.User, error) {
-	user := new(models.User)
-	err := p.DB.QueryRow("SELECT * FROM users WHERE email = $1", email).Scan(&user.Id, &user.FirstName, &user.LastName, &user.Username, &user.Password, &user.Email, &user.UserType, &user.CreatedAt, &user.ModifiedAt)
+	user, err := scanUser(p.DB.QueryRow("SELECT "+USER_COLUMNS+" FROM users WHERE email = $1", email))
 
 	if err != nil {
 		log.Println("Error in fetching the user", err)
@@ -104,9 +188,18 @@ func (p *PostgresStore) SavePassword(user models.User) error {
 	return nil
 }
 
+func (p *PostgresStore) VerifyUserEmail(id uuid.UUID) error {
+	_, err := p.DB.Exec("UPDATE users SET email_verified = TRUE, modified_at = $1 WHERE id = $2", time.Now().UTC(), id)
+	if err != nil {
+		log.Println("Error in verifying the user email", err)
+		return err
+	}
+
+	return nil
+}
+
 func (p *PostgresStore) VerifyUserByUsernamePassword(username, password string) (*models.User, error) {
-	user := new(models.User)
-	err := p.DB.QueryRow("SELECT * FROM users WHERE username = $1", username).Scan(&user.Id, &user.FirstName, &user.LastName, &user.Username, &user.Password, &user.Email, &user.UserType, &user.CreatedAt, &user.ModifiedAt)
+	user, err := scanUser(p.DB.QueryRow("SELECT "+USER_COLUMNS+" FROM users WHERE username = $1", username))
 
 	if err != nil {
 		log.Println("Invalid Username", err)
@@ -0,0 +1,70 @@
+package storages
+
+import (
+	"time"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+	"github.com/google/uuid"
+)
+
+// GetUserDataExport gathers the profile of the user with the teams and boards
+// they belong to, the feedback they wrote, their sessions and personal access
+// tokens. It returns sql.ErrNoRows when the user does not exist.
+func (p *PostgresStore) GetUserDataExport(userId uuid.UUID) (*models.UserDataExport, error) {
+	profile, err := p.GetUserById(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := p.queryTeams(
+		"SELECT "+TEAM_COLUMNS+" FROM teams WHERE id IN (SELECT team_id FROM team_members WHERE user_id = $1) ORDER BY name",
+		userId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	boards, err := p.queryBoards(
+		"SELECT "+BOARD_COLUMNS+" FROM boards WHERE created_by_id = $1 OR id IN (SELECT board_id FROM board_members WHERE user_id = $1) ORDER BY created_at DESC",
+		userId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	feedbacks, err := p.queryFeedbacks("SELECT "+FEEDBACK_COLUMNS+", "+FEEDBACK_VOTE_COUNT+" FROM feedbacks WHERE created_by_id = $1 ORDER BY created_at DESC", userId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.DB.Query("SELECT "+USER_SESSION_COLUMNS+" FROM user_sessions WHERE user_id = $1 ORDER BY created_at DESC", userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]*models.UserSession, 0)
+	for rows.Next() {
+		session, err := scanUserSession(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	tokens, err := p.GetPersonalAccessTokensByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UserDataExport{
+		ExportedAt:           time.Now().UTC(),
+		Profile:              profile,
+		Teams:                teams,
+		Boards:               boards,
+		Feedbacks:            feedbacks,
+		Sessions:             sessions,
+		PersonalAccessTokens: tokens,
+	}, nil
+}
@@ -0,0 +1,31 @@
+package storages
+
+import (
+	"log"
+
+	"github.com/Aakash-Pandit/reetro-golang/models"
+)
+
+// GetUserByIdentity returns the user linked to the subject of the provider,
+// sql.ErrNoRows when the subject was never linked.
+func (p *PostgresStore) GetUserByIdentity(provider, subject string) (*models.User, error) {
+	return scanUser(p.DB.QueryRow(
+		"SELECT "+USER_COLUMNS+" FROM users WHERE id = (SELECT user_id FROM user_identities WHERE provider = $1 AND subject = $2)",
+		provider, subject,
+	))
+}
+
+// CreateUserIdentity links the user to the subject, a user can only be
+// linked to one subject of each provider.
+func (p *PostgresStore) CreateUserIdentity(identity models.UserIdentity) error {
+	_, err := p.DB.Exec(
+		"INSERT INTO user_identities (provider, subject, user_id, created_at) VALUES ($1, $2, $3, $4)",
+		identity.Provider, identity.Subject, identity.UserId, identity.CreatedAt,
+	)
+	if err != nil {
+		log.Println("Error in linking the user identity", err)
+		return err
+	}
+
+	return nil
+}